@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+// writeJSONL exports tasks as JSON Lines, one task object per line
+func writeJSONL(out io.Writer, tasks []*task.Task) error {
+	w := bufio.NewWriter(out)
+	for _, t := range tasks {
+		data, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Errorf("failed to marshal task %s: %w", t.ID, err)
+		}
+		w.Write(data)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write JSONL file: %w", err)
+	}
+	return nil
+}
+
+// appendJSONL adds tasks to the end of an existing JSONL export, so a
+// reader should let later lines for an ID win
+func (tm *TaskManager) appendJSONL(tasks []*task.Task, filename string) error {
+	if err := checkJSONLFile(filename); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open JSONL file: %w", err)
+	}
+	if err := writeJSONL(file, tasks); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// checkJSONLFile refuses to append to a file whose first line is not a
+// task object, such as a JSON array export
+func checkJSONLFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	first, _, _ := bytes.Cut(bytes.TrimSpace(data), []byte("\n"))
+	if len(first) == 0 {
+		return nil
+	}
+	var t task.Task
+	if first[0] != '{' || json.Unmarshal(first, &t) != nil || t.ID == "" {
+		return fmt.Errorf("cannot append to %s: it is not a JSONL task export", filename)
+	}
+	return nil
+}
+
+// appendJSON merges tasks into an existing JSON export by ID, keeping the
+// more recently updated copy, and rewrites it. Tasks only in the file stay.
+func (tm *TaskManager) appendJSON(tasks []*task.Task, filename string, opts ExportOptions) error {
+	data, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	var existing []*task.Task
+	if len(bytes.TrimSpace(data)) > 0 {
+		trimmed := bytes.TrimSpace(data)
+		if trimmed[0] != '[' && trimmed[0] != '{' {
+			return fmt.Errorf("cannot append to %s: it is not a JSON task export", filename)
+		}
+		if existing, err = parseJSONImport(data); err != nil {
+			return fmt.Errorf("cannot append to %s: %w", filename, err)
+		}
+	}
+
+	e, err := tm.exporters(opts).Lookup("json")
+	if err != nil {
+		return err
+	}
+	return storage.ExportFile(e, filename, mergeByID(existing, tasks))
+}
+
+// mergeByID combines two task lists, keeping the copy with the later
+// UpdatedAt when an ID appears in both. Existing order is kept and new IDs
+// follow in their own order.
+func mergeByID(existing, incoming []*task.Task) []*task.Task {
+	merged := make([]*task.Task, 0, len(existing)+len(incoming))
+	index := make(map[string]int, len(existing)+len(incoming))
+	for _, list := range [][]*task.Task{existing, incoming} {
+		for _, t := range list {
+			if i, ok := index[t.ID]; ok {
+				if t.UpdatedAt.After(merged[i].UpdatedAt) {
+					merged[i] = t
+				}
+				continue
+			}
+			index[t.ID] = len(merged)
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
+
+// checkAppendFormat reports whether format can be appended to
+func checkAppendFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "json", "jsonl":
+		return nil
+	}
+	return fmt.Errorf("--append supports json and jsonl exports, not %s", format)
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestExportAppendJSON(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-append-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	ctx := context.Background()
+
+	base := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	filename := filepath.Join(tempDir, "backup.json")
+
+	// An earlier export holds an old copy of "shared" and a task since deleted
+	earlier := NewTaskManager(storage.NewInMemoryStorage())
+	for _, tt := range []*task.Task{
+		{ID: "gone", Title: "Deleted since", CreatedAt: base, UpdatedAt: base},
+		{ID: "shared", Title: "Old title", CreatedAt: base, UpdatedAt: base},
+	} {
+		if err := earlier.storage.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+	if err := earlier.ExportTasks(ctx, "json", filename, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	for _, tt := range []*task.Task{
+		{ID: "shared", Title: "New title", CreatedAt: base, UpdatedAt: base.Add(time.Hour)},
+		{ID: "fresh", Title: "Fresh", CreatedAt: base, UpdatedAt: base},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+	// Appending twice must not duplicate anything
+	for i := 0; i < 2; i++ {
+		if err := tm.ExportTasks(ctx, "json", filename, ExportOptions{Append: true}); err != nil {
+			t.Fatalf("Unexpected error appending: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error reading export: %v", err)
+	}
+	merged, err := parseJSONImport(data)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing export: %v", err)
+	}
+
+	var got []string
+	for _, tt := range merged {
+		got = append(got, tt.ID+"="+tt.Title)
+	}
+	if want := "gone=Deleted since,shared=New title,fresh=Fresh"; strings.Join(got, ",") != want {
+		t.Errorf("Expected %s, got %s", want, strings.Join(got, ","))
+	}
+}
+
+func TestExportAppendGuards(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-append-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	ctx := context.Background()
+
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	if err := tm.Add(ctx, "Task", "", task.Medium, time.Time{}, nil); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	csvFile := filepath.Join(tempDir, "tasks.csv")
+	if err := tm.ExportTasks(ctx, "csv", csvFile, ExportOptions{Append: true}); err == nil {
+		t.Error("Expected --append to refuse CSV")
+	}
+
+	// A JSON array is not JSONL, and CSV is not JSON
+	jsonFile := filepath.Join(tempDir, "tasks.json")
+	if err := tm.ExportTasks(ctx, "json", jsonFile, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+	if err := tm.ExportTasks(ctx, "jsonl", jsonFile, ExportOptions{Append: true}); err == nil {
+		t.Error("Expected appending JSONL to a JSON array to fail")
+	}
+	if err := tm.ExportTasks(ctx, "csv", csvFile, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+	if err := tm.ExportTasks(ctx, "json", csvFile, ExportOptions{Append: true}); err == nil {
+		t.Error("Expected appending JSON to a CSV file to fail")
+	}
+
+	jsonlFile := filepath.Join(tempDir, "tasks.jsonl")
+	for i := 0; i < 2; i++ {
+		if err := tm.ExportTasks(ctx, "jsonl", jsonlFile, ExportOptions{Append: true}); err != nil {
+			t.Fatalf("Unexpected error appending JSONL: %v", err)
+		}
+	}
+	data, err := os.ReadFile(jsonlFile)
+	if err != nil {
+		t.Fatalf("Unexpected error reading export: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("Expected 2 appended lines, got %d", lines)
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+// backupLayout timestamps backup file names so they sort chronologically
+const backupLayout = "20060102-150405"
+
+// Backup writes a JSON snapshot of every task to a timestamped file in dir,
+// created if needed, and returns its path. Unlike the human-facing export
+// formats it keeps every field as stored, IDs and nanosecond timestamps
+// included, so Restore gives back an identical task set.
+func (tm *TaskManager) Backup(ctx context.Context, dir string) (string, error) {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	path := filepath.Join(dir, "tasks-"+time.Now().Format(backupLayout)+".json")
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("backup %s already exists", path)
+	}
+
+	if err := storage.NewJSONFileStorage(path).Save(ctx, tasks); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	fmt.Fprintf(tm.out, "%s Backed up %d tasks to %s\n", tm.Icons().Success, len(tasks), path)
+	return path, nil
+}
+
+// Restore replaces every task with the contents of a backup file. The file
+// is checked in full first, so a corrupt backup leaves the store untouched.
+// The replacement is one undoable operation.
+func (tm *TaskManager) Restore(ctx context.Context, path string) (n int, err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	tasks, err := readBackup(path)
+	if err != nil {
+		return 0, err
+	}
+
+	current, err := tm.storage.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	if err := tm.storage.Save(ctx, tasks); err != nil {
+		return 0, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	if err := tm.logChanges(pairByID(current, tasks)); err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintf(tm.out, "%s Restored %d tasks from %s\n", tm.Icons().Success, len(tasks), path)
+	return len(tasks), nil
+}
+
+// pairByID pairs the tasks of current and restored by ID as before and
+// after, nil on the side where a task is missing
+func pairByID(current, restored []*task.Task) [][2]*task.Task {
+	byID := make(map[string]*task.Task, len(current))
+	for _, t := range current {
+		byID[t.ID] = t
+	}
+
+	var changed [][2]*task.Task
+	for _, t := range restored {
+		changed = append(changed, [2]*task.Task{byID[t.ID], t})
+		delete(byID, t.ID)
+	}
+	for _, t := range current {
+		if _, gone := byID[t.ID]; gone {
+			changed = append(changed, [2]*task.Task{t, nil})
+		}
+	}
+	return changed
+}
+
+// readBackup parses a backup file, requiring every task to be valid and
+// every ID to be unique
+func readBackup(path string) ([]*task.Task, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup %s: %w", path, err)
+	}
+
+	tasks, err := storage.DecodeTasks(data)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s is corrupt: %w", path, err)
+	}
+	if tasks == nil {
+		return nil, fmt.Errorf("backup %s is corrupt: no task list", path)
+	}
+
+	seen := make(map[string]bool, len(tasks))
+	for i, t := range tasks {
+		if t == nil {
+			return nil, fmt.Errorf("backup %s is corrupt: entry %d is empty", path, i+1)
+		}
+		if err := t.Validate(); err != nil {
+			return nil, fmt.Errorf("backup %s is corrupt: task %s: %w", path, t.ID, err)
+		}
+		if seen[t.ID] {
+			return nil, fmt.Errorf("backup %s is corrupt: duplicate task ID %s", path, t.ID)
+		}
+		seen[t.ID] = true
+	}
+
+	return tasks, nil
+}
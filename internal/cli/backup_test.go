@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerBackupRestore(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-backup-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	store := storage.NewJSONFileStorage(filepath.Join(tempDir, "tasks.json"))
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+
+	if err := store.Add(ctx, &task.Task{ID: "kept", Title: "Kept"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	path, err := tm.Backup(ctx, filepath.Join(tempDir, "backups"))
+	if err != nil {
+		t.Fatalf("Unexpected error backing up: %v", err)
+	}
+
+	// Edits after the snapshot are undone by restoring it
+	if err := store.Add(ctx, &task.Task{ID: "later", Title: "Later"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	n, err := tm.Restore(ctx, path)
+	if err != nil {
+		t.Fatalf("Unexpected error restoring: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 task restored, got %d", n)
+	}
+
+	tasks, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "kept" {
+		t.Errorf("Expected only the backed up task, got %v", tasks)
+	}
+}
+
+func TestTaskManagerRestoreRejectsCorrupt(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-backup-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	storePath := filepath.Join(tempDir, "tasks.json")
+	store := storage.NewJSONFileStorage(storePath)
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+
+	if err := store.Add(ctx, &task.Task{ID: "kept", Title: "Kept"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	before, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading store: %v", err)
+	}
+
+	tests := map[string]string{
+		"truncated":    `[{"id": "a", "title": "A"`,
+		"not a list":   `{"id": "a", "title": "A"}`,
+		"empty title":  `[{"id": "a", "title": ""}]`,
+		"duplicate id": `[{"id": "a", "title": "A"}, {"id": "a", "title": "B"}]`,
+		"null entry":   `[null]`,
+		"empty":        ``,
+	}
+
+	for name, content := range tests {
+		path := filepath.Join(tempDir, "bad.json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error writing backup: %v", err)
+		}
+		if _, err := tm.Restore(ctx, path); err == nil {
+			t.Errorf("%s: expected restore to be refused", name)
+		}
+	}
+
+	after, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading store: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("Expected the store to be untouched by a refused restore")
+	}
+}
+
+func TestBackupRoundTripIsLossless(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-backup-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	zone := time.FixedZone("UTC+5:30", 5*60*60+30*60)
+	created := time.Date(2025, 3, 9, 8, 15, 30, 123456789, zone)
+	rich := &task.Task{
+		ID:              "task_1741488330123456789",
+		Title:           "Ship the \"quarterly\" report, v2",
+		Description:     "Numbers; charts\nand a summary, with ünïcode",
+		Priority:        task.Urgent,
+		DueDate:         created.Add(72 * time.Hour),
+		Completed:       true,
+		CompletedAt:     created.Add(80*time.Hour + time.Nanosecond),
+		CreatedAt:       created,
+		UpdatedAt:       created.Add(80 * time.Hour),
+		Tags:            []string{"q3", "work"},
+		RelatedTo:       []string{"plain"},
+		DependsOn:       []string{"plain"},
+		Recurrence:      &task.Recurrence{Interval: "monthly", Count: 11},
+		ParentID:        "plain",
+		EstimateMinutes: 95,
+		WasLate:         true,
+		StartDate:       created.Add(24 * time.Hour),
+		// UTC so the restored times DeepEqual the originals
+		Notes: []task.Note{{Text: "Draft sent; waiting on \"finance\"", CreatedAt: created.Add(30 * time.Hour).UTC()}},
+	}
+
+	// Every field must be set so a newly added one is covered too
+	v := reflect.ValueOf(*rich)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsZero() {
+			t.Fatalf("Fixture leaves %s unset; give it a value", v.Type().Field(i).Name)
+		}
+	}
+
+	plain := &task.Task{ID: "plain", Title: "Plain", CreatedAt: created, UpdatedAt: created}
+	source := storage.NewInMemoryStorage()
+	for _, tt := range []*task.Task{rich, plain} {
+		if err := source.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+	original, _ := source.Load(ctx)
+
+	exporter := NewTaskManager(source)
+	exporter.SetOutput(&bytes.Buffer{})
+	first, err := exporter.Backup(ctx, filepath.Join(tempDir, "first"))
+	if err != nil {
+		t.Fatalf("Unexpected error backing up: %v", err)
+	}
+
+	target := storage.NewInMemoryStorage()
+	importer := NewTaskManager(target)
+	importer.SetOutput(&bytes.Buffer{})
+	if _, err := importer.Restore(ctx, first); err != nil {
+		t.Fatalf("Unexpected error restoring: %v", err)
+	}
+
+	restored, err := target.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading restored tasks: %v", err)
+	}
+	if len(restored) != len(original) {
+		t.Fatalf("Expected %d tasks, got %d", len(original), len(restored))
+	}
+	for i, want := range original {
+		got := restored[i]
+		for _, pair := range [][2]time.Time{
+			{got.DueDate, want.DueDate}, {got.CompletedAt, want.CompletedAt},
+			{got.CreatedAt, want.CreatedAt}, {got.UpdatedAt, want.UpdatedAt},
+			{got.StartDate, want.StartDate},
+		} {
+			if !pair[0].Equal(pair[1]) || pair[0].Format(time.RFC3339Nano) != pair[1].Format(time.RFC3339Nano) {
+				t.Errorf("%s: expected time %v, got %v", want.ID, pair[1], pair[0])
+			}
+		}
+		// Parsed offsets are new *time.Location values, so compare the rest
+		// with the times cleared
+		gotCopy, wantCopy := *got, *want
+		for _, c := range []*task.Task{&gotCopy, &wantCopy} {
+			c.DueDate, c.CompletedAt, c.CreatedAt, c.UpdatedAt, c.StartDate = time.Time{}, time.Time{}, time.Time{}, time.Time{}, time.Time{}
+		}
+		if !reflect.DeepEqual(gotCopy, wantCopy) {
+			t.Errorf("%s: expected %+v, got %+v", want.ID, wantCopy, gotCopy)
+		}
+	}
+
+	// Backing up the restored store reproduces the file byte for byte
+	second, err := importer.Backup(ctx, filepath.Join(tempDir, "second"))
+	if err != nil {
+		t.Fatalf("Unexpected error backing up again: %v", err)
+	}
+	firstData, _ := os.ReadFile(first)
+	secondData, _ := os.ReadFile(second)
+	if !bytes.Equal(firstData, secondData) {
+		t.Errorf("Expected identical backups, got:\n%s\nand:\n%s", firstData, secondData)
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// BranchResolver reports the version-control branch of the working directory
+type BranchResolver interface {
+	CurrentBranch(ctx context.Context) (string, error)
+}
+
+// GitBranchResolver asks git for the checked-out branch
+type GitBranchResolver struct{}
+
+// CurrentBranch runs git rev-parse --abbrev-ref HEAD
+func (GitBranchResolver) CurrentBranch(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git branch: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SetBranchResolver replaces how BranchTag finds the current branch, which
+// defaults to asking git
+func (tm *TaskManager) SetBranchResolver(r BranchResolver) {
+	tm.branches = r
+}
+
+// BranchTag returns the current branch as a tag, or "" when there is none,
+// such as outside a repository or on a detached HEAD
+func (tm *TaskManager) BranchTag(ctx context.Context) string {
+	branch, err := tm.branches.CurrentBranch(ctx)
+	if err != nil {
+		return ""
+	}
+	return sanitizeBranchTag(branch)
+}
+
+// sanitizeBranchTag lowercases a branch name and replaces characters that
+// tags cannot hold, such as commas and whitespace
+func sanitizeBranchTag(branch string) string {
+	branch = strings.ToLower(strings.TrimSpace(branch))
+	if branch == "" || branch == "head" {
+		return ""
+	}
+	return strings.Join(strings.FieldsFunc(branch, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	}), "-")
+}
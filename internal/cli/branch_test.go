@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-fun/internal/storage"
+)
+
+type fakeBranchResolver struct {
+	branch string
+	err    error
+}
+
+func (f fakeBranchResolver) CurrentBranch(ctx context.Context) (string, error) {
+	return f.branch, f.err
+}
+
+func TestTaskManagerBranchTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver fakeBranchResolver
+		want     string
+	}{
+		{"branch", fakeBranchResolver{branch: "feature/Login-Form\n"}, "feature/login-form"},
+		{"unsafe characters", fakeBranchResolver{branch: "fix a,b"}, "fix-a-b"},
+		{"detached head", fakeBranchResolver{branch: "HEAD"}, ""},
+		{"not a repository", fakeBranchResolver{err: errors.New("fatal: not a git repository")}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := NewTaskManager(storage.NewInMemoryStorage())
+			tm.SetBranchResolver(tt.resolver)
+
+			if got := tm.BranchTag(context.Background()); got != tt.want {
+				t.Errorf("BranchTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
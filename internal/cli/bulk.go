@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// CompleteMatching completes every pending task matching opts, along with
+// their open subtasks, in a single save and returns how many tasks changed.
+// With require-subtasks set it refuses if a match has open subtasks that do
+// not match themselves. With confirm the matches are listed and the user is
+// asked first.
+func (tm *TaskManager) CompleteMatching(ctx context.Context, opts ListOptions, confirm bool) (n int, err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	opts.ShowCompleted = false
+	matched, err := tm.selectTasks(tasks, opts)
+	if err != nil {
+		return 0, err
+	}
+	if len(matched) == 0 {
+		fmt.Fprintln(tm.out, "No tasks match the current filters.")
+		return 0, nil
+	}
+
+	if confirm {
+		for _, t := range matched {
+			fmt.Fprintf(tm.out, "  - %s (%s)\n", t.Title, t.ID)
+		}
+		ok, err := tm.Confirm(fmt.Sprintf("Complete %d matching tasks?", len(matched)))
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			fmt.Fprintln(tm.out, "Aborted.")
+			return 0, nil
+		}
+	}
+
+	byID := make(map[string]*task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	selected := make(map[string]bool, len(matched))
+	for _, t := range matched {
+		selected[t.ID] = true
+	}
+
+	// Subtasks that did not match are completed alongside their parent
+	var subtasks []*task.Task
+	for _, t := range matched {
+		for _, childID := range descendantIDs(tasks, t.ID) {
+			child := byID[childID]
+			if child.Completed || selected[childID] {
+				continue
+			}
+			if tm.requireSubtasks {
+				return 0, fmt.Errorf("cannot complete %s: %w: %s", t.ID, ErrOpenSubtasks, describeTasks([]*task.Task{child}))
+			}
+			selected[childID] = true
+			subtasks = append(subtasks, child)
+		}
+	}
+
+	now := time.Now()
+	type change struct{ before, after *task.Task }
+	var changes []change
+	for _, t := range append(matched, subtasks...) {
+		before := snapshot(t)
+		t.Complete()
+		changes = append(changes, change{before, t})
+	}
+
+	// Only directly matched recurring tasks schedule their next occurrence
+	var spawned []*task.Task
+	for _, t := range matched {
+		if next := t.Spawn(now); next != nil {
+			spawned = append(spawned, next)
+		}
+	}
+	tasks = append(tasks, spawned...)
+
+	if err := tm.storage.Save(ctx, tasks); err != nil {
+		return 0, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	for _, c := range changes {
+		if err := tm.logEvent("complete", c.after.ID, c.before, c.after); err != nil {
+			return 0, err
+		}
+	}
+	for _, next := range spawned {
+		if err := tm.logEvent("add", next.ID, nil, next); err != nil {
+			return 0, err
+		}
+	}
+
+	fmt.Fprintf(tm.out, "%s Completed %d tasks\n", tm.Icons().Success, len(changes))
+	if len(spawned) > 0 {
+		fmt.Fprintf(tm.out, "%s Scheduled %d next occurrences\n", tm.Icons().Repeats, len(spawned))
+	}
+	return len(changes), nil
+}
+
+// DeleteMatching removes every task matching opts in a single save and
+// returns how many were removed. A match with subtasks is refused with
+// ErrHasSubtasks unless cascade is set or the subtasks match too. Like
+// Purge, it refuses a store that loads empty but holds data unless force is
+// set.
+func (tm *TaskManager) DeleteMatching(ctx context.Context, opts ListOptions, cascade, force bool) (n int, err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	if err := tm.guardBulkDestructive(tasks, force); err != nil {
+		return 0, err
+	}
+
+	matched, err := tm.selectTasks(tasks, opts)
+	if err != nil {
+		return 0, err
+	}
+	if len(matched) == 0 {
+		fmt.Fprintln(tm.out, "No tasks match the current filters.")
+		return 0, nil
+	}
+
+	doomed := make(map[string]bool, len(matched))
+	for _, t := range matched {
+		doomed[t.ID] = true
+	}
+	for _, t := range matched {
+		for _, childID := range descendantIDs(tasks, t.ID) {
+			if doomed[childID] {
+				continue
+			}
+			if !cascade {
+				return 0, fmt.Errorf("cannot delete %s: %w", t.ID, ErrHasSubtasks)
+			}
+			doomed[childID] = true
+		}
+	}
+
+	remaining := make([]*task.Task, 0, len(tasks))
+	var deleted []*task.Task
+	for _, t := range tasks {
+		if doomed[t.ID] {
+			deleted = append(deleted, t)
+			continue
+		}
+		remaining = append(remaining, t)
+	}
+
+	unlinked := removeReferences(remaining, doomed)
+
+	if err := tm.storage.Save(ctx, remaining); err != nil {
+		return 0, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	if err := tm.logDeleted(deleted, unlinked); err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintf(tm.out, "%s Deleted %d tasks\n", tm.Icons().Deleted, len(deleted))
+	return len(deleted), nil
+}
+
+// selectTasks picks the tasks List would show for opts: filtered, sorted
+// and cut to the --offset/--limit page, so a bulk command acts on exactly
+// what the same list flags preview
+func (tm *TaskManager) selectTasks(tasks []*task.Task, opts ListOptions) ([]*task.Task, error) {
+	matched, err := filterTasks(tasks, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := tm.sortTasksBy(matched, opts.Sort, opts.Reverse); err != nil {
+		return nil, err
+	}
+	return paginate(matched, opts.Offset, opts.Limit), nil
+}
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/filter"
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func newBulkStore(t *testing.T) (*TaskManager, storage.Storage, *bytes.Buffer) {
+	t.Helper()
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "s1", Title: "Sprint bug", Priority: task.High, Tags: []string{"sprint"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "s2", Title: "Sprint docs", Priority: task.Low, Tags: []string{"sprint"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "s3", Title: "Sprint done", Priority: task.High, Tags: []string{"sprint"}, Completed: true, CreatedAt: now, UpdatedAt: now},
+		{ID: "child", Title: "Child", ParentID: "s1", CreatedAt: now, UpdatedAt: now},
+		{ID: "other", Title: "Other", Priority: task.High, DependsOn: []string{"s2"}, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(context.Background(), tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+	return tm, store, &out
+}
+
+func completedIDs(t *testing.T, store storage.Storage) []string {
+	t.Helper()
+	tasks, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	var ids []string
+	for _, tt := range tasks {
+		if tt.Completed {
+			ids = append(ids, tt.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestTaskManagerCompleteMatching(t *testing.T) {
+	tm, store, out := newBulkStore(t)
+	ctx := context.Background()
+
+	high := filter.PriorityFilter{Level: task.High}
+	n, err := tm.CompleteMatching(ctx, ListOptions{Tag: "sprint", Priority: &high}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error completing tasks: %v", err)
+	}
+	// s1 matches and brings its open child along; s3 was already done
+	if n != 2 {
+		t.Errorf("Expected 2 tasks completed, got %d", n)
+	}
+	if got := strings.Join(completedIDs(t, store), ","); got != "child,s1,s3" {
+		t.Errorf("Expected child,s1,s3 completed, got %s", got)
+	}
+	if !strings.Contains(out.String(), "Completed 2 tasks") {
+		t.Errorf("Expected summary count, got %q", out.String())
+	}
+
+	n, err = tm.CompleteMatching(ctx, ListOptions{Search: "nothing like this"}, false)
+	if err != nil || n != 0 {
+		t.Errorf("Expected no matches, got %d, %v", n, err)
+	}
+}
+
+func TestTaskManagerCompleteMatchingRequireSubtasks(t *testing.T) {
+	tm, store, _ := newBulkStore(t)
+	tm.SetRequireSubtasks(true)
+
+	_, err := tm.CompleteMatching(context.Background(), ListOptions{Tag: "sprint"}, false)
+	if !errors.Is(err, ErrOpenSubtasks) {
+		t.Fatalf("Expected ErrOpenSubtasks, got %v", err)
+	}
+	if got := completedIDs(t, store); len(got) != 1 {
+		t.Errorf("Expected nothing new completed, got %v", got)
+	}
+}
+
+func TestTaskManagerDeleteMatching(t *testing.T) {
+	tm, store, _ := newBulkStore(t)
+	ctx := context.Background()
+
+	// s1 has a subtask, so the sprint filter is refused without cascade
+	if _, err := tm.DeleteMatching(ctx, ListOptions{Tag: "sprint"}, false, false); !errors.Is(err, ErrHasSubtasks) {
+		t.Fatalf("Expected ErrHasSubtasks, got %v", err)
+	}
+
+	// Completed tasks only match with ShowCompleted, as in list
+	low := filter.PriorityFilter{Op: filter.OpLessOrEqual, Level: task.Low}
+	n, err := tm.DeleteMatching(ctx, ListOptions{Tag: "sprint", Priority: &low, ShowCompleted: true}, false, false)
+	if err != nil {
+		t.Fatalf("Unexpected error deleting tasks: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 task deleted, got %d", n)
+	}
+
+	other, err := store.GetByID(ctx, "other")
+	if err != nil {
+		t.Fatalf("Unexpected error getting task: %v", err)
+	}
+	if len(other.DependsOn) != 0 {
+		t.Errorf("Expected dependency on deleted task removed, got %v", other.DependsOn)
+	}
+
+	n, err = tm.DeleteMatching(ctx, ListOptions{Tag: "sprint", ShowCompleted: true}, true, false)
+	if err != nil {
+		t.Fatalf("Unexpected error deleting tasks: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected s1, s3 and child deleted, got %d", n)
+	}
+}
+
+func TestTaskManagerBulkHonorsPagination(t *testing.T) {
+	tm, store, _ := newBulkStore(t)
+	ctx := context.Background()
+
+	// Pending sprint tasks by title are s1 then s2, so the second page is s2
+	n, err := tm.CompleteMatching(ctx, ListOptions{Tag: "sprint", Sort: "title", Offset: 1, Limit: 1}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error completing tasks: %v", err)
+	}
+	if got := completedIDs(t, store); n != 1 || strings.Join(got, ",") != "s2,s3" {
+		t.Errorf("Expected only s2 completed, got %d: %v", n, got)
+	}
+
+	n, err = tm.DeleteMatching(ctx, ListOptions{Tag: "sprint", ShowCompleted: true, Sort: "title", Reverse: true, Limit: 1}, false, false)
+	if err != nil {
+		t.Fatalf("Unexpected error deleting tasks: %v", err)
+	}
+	if _, err := store.GetByID(ctx, "s3"); n != 1 || err == nil {
+		t.Errorf("Expected only s3 deleted, got %d", n)
+	}
+	if tasks, _ := store.Load(ctx); len(tasks) != 4 {
+		t.Errorf("Expected 4 tasks left, got %d", len(tasks))
+	}
+}
+
+func TestTaskManagerCompleteMatchingOverdueSkipsUndated(t *testing.T) {
+	tm, store, _ := newBulkStore(t)
+	ctx := context.Background()
+
+	// Every fixture task is undated; only the late one is overdue
+	now := time.Now()
+	late := &task.Task{ID: "late", Title: "Late", DueDate: now.AddDate(0, 0, -2), CreatedAt: now, UpdatedAt: now}
+	if err := store.Add(ctx, late); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	n, err := tm.CompleteMatching(ctx, ListOptions{Due: "overdue"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error completing tasks: %v", err)
+	}
+	if got := completedIDs(t, store); n != 1 || strings.Join(got, ",") != "late,s3" {
+		t.Errorf("Expected only the overdue task completed, got %d: %v", n, got)
+	}
+}
+
+func TestTaskManagerCompleteMatchingConfirm(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 23, 0, 0, 0, now.Location())
+	for _, tt := range []*task.Task{
+		{ID: "work-today", Title: "Ship", Tags: []string{"work"}, DueDate: today},
+		{ID: "work-today-2", Title: "Review", Tags: []string{"work", "team"}, DueDate: today},
+		{ID: "home-today", Title: "Laundry", Tags: []string{"home"}, DueDate: today},
+		{ID: "work-later", Title: "Plan", Tags: []string{"work"}, DueDate: today.AddDate(0, 0, 3)},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+	opts := ListOptions{Due: "today", Tag: "work"}
+
+	// Declining changes nothing
+	tm.SetInput(strings.NewReader("n\n"))
+	n, err := tm.CompleteMatching(ctx, opts, true)
+	if err != nil {
+		t.Fatalf("Unexpected error completing: %v", err)
+	}
+	if n != 0 || len(completedIDs(t, store)) != 0 {
+		t.Errorf("Expected nothing completed after declining, got %d", n)
+	}
+	if !strings.Contains(out.String(), "Complete 2 matching tasks?") {
+		t.Errorf("Expected a prompt naming the count, got %q", out.String())
+	}
+
+	tm.SetInput(strings.NewReader("y\n"))
+	n, err = tm.CompleteMatching(ctx, opts, true)
+	if err != nil {
+		t.Fatalf("Unexpected error completing: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 tasks completed, got %d", n)
+	}
+	if got := completedIDs(t, store); strings.Join(got, ",") != "work-today,work-today-2" {
+		t.Errorf("Expected only tasks due today tagged work completed, got %v", got)
+	}
+}
+
+func TestTaskManagerDeleteMatchingSuspiciousStore(t *testing.T) {
+	// A "null" document parses to zero tasks without error
+	filePath := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(filePath, []byte("null"), 0644); err != nil {
+		t.Fatalf("Failed to write tasks file: %v", err)
+	}
+	tm := NewTaskManager(storage.NewJSONFileStorage(filePath))
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	if _, err := tm.DeleteMatching(ctx, ListOptions{}, false, false); !errors.Is(err, ErrSuspiciousStore) {
+		t.Fatalf("Expected ErrSuspiciousStore, got %v", err)
+	}
+	if _, err := tm.DeleteMatching(ctx, ListOptions{}, false, true); err != nil {
+		t.Errorf("Expected force to bypass the guard, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-fun/internal/task"
+)
+
+// TaskEdit is a task present in both versions with changed fields
+type TaskEdit struct {
+	Task    *task.Task
+	Changes []FieldChange
+}
+
+// ChangeSet summarizes how a task list moved between two versions
+type ChangeSet struct {
+	Added     []*task.Task
+	Removed   []*task.Task
+	Completed []*task.Task
+	Reopened  []*task.Task
+	Edited    []TaskEdit
+}
+
+// Empty reports whether the two versions were identical
+func (c ChangeSet) Empty() bool {
+	return len(c.Added)+len(c.Removed)+len(c.Completed)+len(c.Reopened)+len(c.Edited) == 0
+}
+
+// diffStores compares an older task list with a newer one by ID. A task
+// can appear both as completed and edited. Results follow the order of the
+// list they were found in.
+func (tm *TaskManager) diffStores(old, current []*task.Task) ChangeSet {
+	oldByID := make(map[string]*task.Task, len(old))
+	for _, t := range old {
+		oldByID[t.ID] = t
+	}
+	currentIDs := make(map[string]bool, len(current))
+
+	var changes ChangeSet
+	for _, t := range current {
+		currentIDs[t.ID] = true
+		before, ok := oldByID[t.ID]
+		if !ok {
+			changes.Added = append(changes.Added, t)
+			continue
+		}
+		if t.Completed && !before.Completed {
+			changes.Completed = append(changes.Completed, t)
+		} else if !t.Completed && before.Completed {
+			changes.Reopened = append(changes.Reopened, t)
+		}
+		if fields := tm.diffTasks(before, t); len(fields) > 0 {
+			changes.Edited = append(changes.Edited, TaskEdit{Task: t, Changes: fields})
+		}
+	}
+
+	for _, t := range old {
+		if !currentIDs[t.ID] {
+			changes.Removed = append(changes.Removed, t)
+		}
+	}
+	return changes
+}
+
+// Changes compares the current store against a JSON export and prints what
+// was added, removed, completed, reopened or edited since
+func (tm *TaskManager) Changes(ctx context.Context, sinceFile string) error {
+	data, err := os.ReadFile(sinceFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", sinceFile, err)
+	}
+	old, err := parseJSONImport(data)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", sinceFile, err)
+	}
+
+	current, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	changes := tm.diffStores(old, current)
+	if changes.Empty() {
+		fmt.Fprintf(tm.out, "No changes since %s.\n", sinceFile)
+		return nil
+	}
+
+	fmt.Fprintf(tm.out, "\n%s Changes since %s\n", tm.Icons().Stats, sinceFile)
+	fmt.Fprintln(tm.out, strings.Repeat("=", 50))
+
+	sections := []struct {
+		label  string
+		marker string
+		tasks  []*task.Task
+	}{
+		{"Added", "+", changes.Added},
+		{"Removed", "-", changes.Removed},
+		{"Completed", tm.Icons().Done, changes.Completed},
+		{"Reopened", tm.Icons().Pending, changes.Reopened},
+	}
+	for _, s := range sections {
+		if len(s.tasks) == 0 {
+			continue
+		}
+		fmt.Fprintf(tm.out, "\n%s (%d):\n", s.label, len(s.tasks))
+		for _, t := range s.tasks {
+			fmt.Fprintf(tm.out, "  %s %s (%s)\n", s.marker, t.Title, t.ID)
+		}
+	}
+
+	if len(changes.Edited) > 0 {
+		fmt.Fprintf(tm.out, "\nEdited (%d):\n", len(changes.Edited))
+		for _, e := range changes.Edited {
+			fmt.Fprintf(tm.out, "  ~ %s (%s)\n", e.Task.Title, e.Task.ID)
+			for _, c := range e.Changes {
+				fmt.Fprintf(tm.out, "      %s: %q → %q\n", c.Field, c.Old, c.New)
+			}
+		}
+	}
+
+	fmt.Fprintf(tm.out, "\nSummary: %d added, %d removed, %d completed, %d reopened, %d edited\n\n",
+		len(changes.Added), len(changes.Removed), len(changes.Completed), len(changes.Reopened), len(changes.Edited))
+	return nil
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerChangesSinceExport(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-changes-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	now := time.Now()
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+
+	for _, tt := range []*task.Task{
+		{ID: "keep", Title: "Unchanged", Priority: task.Low, CreatedAt: now, UpdatedAt: now},
+		{ID: "edit", Title: "Old title", Priority: task.Low, CreatedAt: now, UpdatedAt: now},
+		{ID: "done", Title: "Finish me", Priority: task.Medium, CreatedAt: now, UpdatedAt: now},
+		{ID: "gone", Title: "Remove me", Priority: task.High, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	exportPath := filepath.Join(tempDir, "before.json")
+	if err := tm.ExportTasks(ctx, "json", exportPath, ExportOptions{Summary: true}); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+
+	// Change the store after the export
+	if err := store.Update(ctx, "edit", &task.Task{Title: "New title", Priority: task.High, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+	if err := tm.Complete(ctx, "done"); err != nil {
+		t.Fatalf("Unexpected error completing task: %v", err)
+	}
+	if err := store.Delete(ctx, "gone"); err != nil {
+		t.Fatalf("Unexpected error deleting task: %v", err)
+	}
+	if err := store.Add(ctx, &task.Task{ID: "new", Title: "Brand new", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	current, _ := store.Load(ctx)
+	data, _ := os.ReadFile(exportPath)
+	old, err := parseJSONImport(data)
+	if err != nil {
+		t.Fatalf("Unexpected error reading export: %v", err)
+	}
+	changes := tm.diffStores(old, current)
+
+	ids := func(tasks []*task.Task) string {
+		var list []string
+		for _, t := range tasks {
+			list = append(list, t.ID)
+		}
+		return strings.Join(list, ",")
+	}
+	if got := ids(changes.Added); got != "new" {
+		t.Errorf("Expected added [new], got [%s]", got)
+	}
+	if got := ids(changes.Removed); got != "gone" {
+		t.Errorf("Expected removed [gone], got [%s]", got)
+	}
+	if got := ids(changes.Completed); got != "done" {
+		t.Errorf("Expected completed [done], got [%s]", got)
+	}
+	if len(changes.Reopened) != 0 {
+		t.Errorf("Expected nothing reopened, got [%s]", ids(changes.Reopened))
+	}
+	if len(changes.Edited) != 1 || changes.Edited[0].Task.ID != "edit" {
+		t.Fatalf("Expected only [edit] to be edited, got %+v", changes.Edited)
+	}
+	fields := make(map[string]FieldChange)
+	for _, c := range changes.Edited[0].Changes {
+		fields[c.Field] = c
+	}
+	if fields["title"].Old != "Old title" || fields["title"].New != "New title" || fields["priority"].New != "High" || len(fields) != 2 {
+		t.Errorf("Unexpected field changes: %+v", changes.Edited[0].Changes)
+	}
+
+	out.Reset()
+	if err := tm.Changes(ctx, exportPath); err != nil {
+		t.Fatalf("Unexpected error showing changes: %v", err)
+	}
+	if !strings.Contains(out.String(), "Summary: 1 added, 1 removed, 1 completed, 0 reopened, 1 edited") {
+		t.Errorf("Expected a change summary, got:\n%s", out.String())
+	}
+
+	// Against a fresh export nothing has changed
+	freshPath := filepath.Join(tempDir, "after.json")
+	if err := tm.ExportTasks(ctx, "json", freshPath, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+	out.Reset()
+	if err := tm.Changes(ctx, freshPath); err != nil {
+		t.Fatalf("Unexpected error showing changes: %v", err)
+	}
+	if !strings.Contains(out.String(), "No changes") {
+		t.Errorf("Expected no changes against a fresh export, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,28 @@
+package cli
+
+import "os"
+
+// ANSI styles used by paint
+const (
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// ColorEnabled decides whether output gets ANSI styling: only on a
+// terminal, and never when disabled is set or NO_COLOR is non-empty
+// (https://no-color.org)
+func ColorEnabled(terminal, disabled bool) bool {
+	return terminal && !disabled && os.Getenv("NO_COLOR") == ""
+}
+
+// paint wraps text in an ANSI style when color is enabled and returns it
+// unchanged otherwise, so callers never test tm.color themselves
+func (tm *TaskManager) paint(style, text string) string {
+	if !tm.color || text == "" {
+		return text
+	}
+	return style + text + ansiReset
+}
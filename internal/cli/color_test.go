@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if !ColorEnabled(true, false) {
+		t.Error("Expected color on a terminal")
+	}
+	if ColorEnabled(false, false) {
+		t.Error("Expected no color when not a terminal")
+	}
+	if ColorEnabled(true, true) {
+		t.Error("Expected -no-color to disable color")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if ColorEnabled(true, false) {
+		t.Error("Expected NO_COLOR to disable color")
+	}
+}
+
+func TestTaskManagerOutputColor(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "late", Title: "Late", Priority: task.High, DueDate: now.Add(-48 * time.Hour)},
+		{ID: "done", Title: "Done", Priority: task.Low, Completed: true, CompletedAt: now},
+	} {
+		tt.CreatedAt, tt.UpdatedAt = now, now
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	render := func() string {
+		out.Reset()
+		if err := tm.List(ctx, ListOptions{ShowCompleted: true}); err != nil {
+			t.Fatalf("Unexpected error listing tasks: %v", err)
+		}
+		if err := tm.Show(ctx, "late"); err != nil {
+			t.Fatalf("Unexpected error showing task: %v", err)
+		}
+		if err := tm.Stats(ctx); err != nil {
+			t.Fatalf("Unexpected error getting stats: %v", err)
+		}
+		return out.String()
+	}
+
+	// A buffer is not a terminal, so the default leaves color off
+	if text := render(); strings.Contains(text, "\x1b[") {
+		t.Errorf("Expected no escape codes in plain output, got:\n%q", text)
+	}
+
+	tm.SetColor(true)
+	text := render()
+	for _, want := range []string{ansiRed + "Due: ", ansiDim + "Done" + ansiReset, "Overdue: " + ansiRed + "1" + ansiReset} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in colored output, got:\n%q", want, text)
+		}
+	}
+}
@@ -1,267 +1,1254 @@
 package cli
 
 import (
+	"bufio"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"go-fun/internal/config"
 	"go-fun/internal/filter"
 	"go-fun/internal/storage"
 	"go-fun/internal/task"
+	"io"
+	"maps"
+	"math/rand/v2"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // TaskManager handles CLI operations for tasks
 type TaskManager struct {
 	storage storage.Storage
+	config  *config.Config
+	out     io.Writer
+	color   bool
+
+	in        *bufio.Reader
+	assumeYes bool
+
+	branches BranchResolver
+
+	requireSubtasks    bool
+	autoCompleteParent bool
+	jsonOutput         bool
+	strictTitles       bool
+	dueSoonWindow      time.Duration
+
+	rng *rand.Rand
+
+	undoLog  string
+	opDepth  int
+	opEvents []Event
+	undoing  bool
 }
 
 // NewTaskManager creates a new TaskManager instance
 func NewTaskManager(s storage.Storage) *TaskManager {
 	return &TaskManager{
-		storage: s,
+		storage:  s,
+		config:   config.Default(),
+		out:      os.Stdout,
+		in:       bufio.NewReader(os.Stdin),
+		branches: GitBranchResolver{},
 	}
 }
 
+// SetConfig replaces the display configuration
+func (tm *TaskManager) SetConfig(cfg *config.Config) {
+	tm.config = cfg
+}
+
+// SetOutput redirects command output, which defaults to stdout
+func (tm *TaskManager) SetOutput(w io.Writer) {
+	tm.out = w
+}
+
+// SetColor enables ANSI styling in command output
+func (tm *TaskManager) SetColor(enabled bool) {
+	tm.color = enabled
+}
+
 // Add creates a new task
 func (tm *TaskManager) Add(ctx context.Context, title, description string, priority task.Priority, dueDate time.Time, tags []string) error {
-	newTask := task.NewTask(title, description, priority, dueDate, tags)
-	return tm.storage.Add(ctx, newTask)
+	return tm.AddTask(ctx, task.NewTask(title, description, priority, dueDate, tags))
 }
 
-// List displays tasks with optional filtering
-func (tm *TaskManager) List(ctx context.Context, showCompleted bool, filterPriority *task.Priority, searchTerm string, showDue string) error {
+// AddTask stores a task built by the caller, such as one with a recurrence
+// or a parent
+func (tm *TaskManager) AddTask(ctx context.Context, t *task.Task) error {
+	if err := tm.checkTitle(t.Title); err != nil {
+		return err
+	}
+
+	if t.ParentID != "" {
+		tasks, err := tm.storage.Load(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load tasks: %w", err)
+		}
+		if err := checkParent(tasks, t.ID, t.ParentID); err != nil {
+			return err
+		}
+	}
+
+	if err := tm.storage.Add(ctx, t); err != nil {
+		return err
+	}
+	return tm.logEvent("add", t.ID, nil, t)
+}
+
+// ListOptions controls which tasks List shows and how
+type ListOptions struct {
+	ShowCompleted bool
+	Priority      *filter.PriorityFilter // e.g. high or >=medium
+	PriorityRange *filter.PriorityRangeFilter
+	Search        string
+	SearchTags    bool // also match the search against tags
+	Regex         bool // treat Search as a regular expression
+	Due           string
+	DueFilter     *filter.TaskDueFilter
+	Tag           string // only tasks carrying this tag
+	Weekday       string // only tasks due on this day, e.g. "friday"
+	Tree          bool   // indent subtasks under their parents
+	Sort          string // "priority" (default), "due", "created", "updated" or "title"
+	Reverse       bool   // flip the sort order
+	Limit         int    // show at most this many tasks; zero or less means all
+	Offset        int    // skip this many tasks after sorting
+	OnlyIDs       bool   // print bare IDs, one per line, for scripting
+	Explain       bool   // show which filters each task passed
+	Scheduled     bool   // also show tasks whose start date is still ahead
+}
+
+// Upsert updates the task if its ID exists and adds it otherwise, using a
+// single load and save. It reports whether a new task was created.
+func (tm *TaskManager) Upsert(ctx context.Context, t *task.Task) (bool, error) {
+	if err := tm.checkIncoming(t); err != nil {
+		return false, err
+	}
+
 	tasks, err := tm.storage.Load(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to load tasks: %w", err)
+		return false, fmt.Errorf("failed to load tasks: %w", err)
 	}
 
-	if len(tasks) == 0 {
-		fmt.Println("No tasks found.")
-		return nil
+	if t.ParentID != "" {
+		if err := checkParent(tasks, t.ID, t.ParentID); err != nil {
+			return false, err
+		}
 	}
 
-	// Filter tasks
-	filtered := make([]*task.Task, 0)
-	for _, task := range tasks {
-		if !showCompleted && task.Completed {
-			continue
+	var replaced *task.Task
+	for i, existing := range tasks {
+		if existing.ID == t.ID {
+			t.CreatedAt = existing.CreatedAt
+			replaced, tasks[i] = existing, t
+			break
 		}
-		if filterPriority != nil && task.Priority != *filterPriority {
-			continue
+	}
+	if replaced == nil {
+		tasks = append(tasks, t)
+	}
+
+	if err := tm.storage.Save(ctx, tasks); err != nil {
+		return false, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	if err := tm.logChanges([][2]*task.Task{{replaced, t}}); err != nil {
+		return false, err
+	}
+	return replaced == nil, nil
+}
+
+// checkIncoming normalizes the tags of a task read from outside and checks
+// it the way Add would
+func (tm *TaskManager) checkIncoming(t *task.Task) error {
+	if err := tm.checkTitle(t.Title); err != nil {
+		return err
+	}
+	t.Tags = task.NormalizeTags(t.Tags)
+	if err := task.ValidateTags(t.Tags); err != nil {
+		return fmt.Errorf("invalid task: %w", err)
+	}
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid task: %w", err)
+	}
+	return nil
+}
+
+// AddFromJSON reads a JSON array of tasks and adds them in a single load
+// and save, as one undoable operation. With upsert, tasks whose ID already
+// exists are updated instead of rejected. Nothing is saved if any task is
+// rejected or ctx ends first.
+func (tm *TaskManager) AddFromJSON(ctx context.Context, r io.Reader, upsert bool) (err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	var incoming []*task.Task
+	if err := json.NewDecoder(r).Decode(&incoming); err != nil {
+		return fmt.Errorf("failed to parse JSON input: %w", err)
+	}
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+	index := make(map[string]int, len(tasks))
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+
+	now := time.Now()
+	var changed [][2]*task.Task
+	var added, updated int
+	for i, t := range incoming {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("stopped at task %d of %d, nothing saved: %w", i+1, len(incoming), err)
 		}
-		if searchTerm != "" && !strings.Contains(strings.ToLower(task.Title), strings.ToLower(searchTerm)) &&
-			!strings.Contains(strings.ToLower(task.Description), strings.ToLower(searchTerm)) {
-			continue
+		if t.ID == "" {
+			t.ID = task.NewID()
 		}
-		if showDue != "" {
-			dueFilter, err := filter.CreateTaskDueFilter(showDue)
-			if err != nil {
-				return fmt.Errorf("invalid due filter: %w", err)
-			}
-			if !dueFilter.Matches(task.DueDate) {
-				continue
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = now
+		}
+		t.UpdatedAt = now
+
+		if err := tm.checkIncoming(t); err != nil {
+			return fmt.Errorf("failed to add task %s: %w", t.ID, err)
+		}
+		if t.ParentID != "" {
+			if err := checkParent(tasks, t.ID, t.ParentID); err != nil {
+				return fmt.Errorf("failed to add task %s: %w", t.ID, err)
 			}
 		}
-		filtered = append(filtered, task)
+
+		j, clash := index[t.ID]
+		switch {
+		case !clash:
+			index[t.ID] = len(tasks)
+			tasks = append(tasks, t)
+			changed = append(changed, [2]*task.Task{nil, t})
+			added++
+		case !upsert:
+			return fmt.Errorf("failed to add task %s: task with ID %s already exists", t.ID, t.ID)
+		default:
+			t.CreatedAt = tasks[j].CreatedAt
+			changed = append(changed, [2]*task.Task{tasks[j], t})
+			tasks[j] = t
+			updated++
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("stopped before saving %d tasks: %w", len(incoming), err)
+	}
+
+	if len(changed) > 0 {
+		if err := tm.storage.Save(ctx, tasks); err != nil {
+			return fmt.Errorf("failed to save tasks: %w", err)
+		}
+		if err := tm.logChanges(changed); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(tm.out, "%s Added %d tasks, updated %d\n", tm.Icons().Success, added, updated)
+	return nil
+}
+
+// List displays tasks with optional filtering
+func (tm *TaskManager) List(ctx context.Context, opts ListOptions) error {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	if tm.jsonOutput {
+		filtered, err := filterTasks(tasks, opts)
+		if err != nil {
+			return err
+		}
+		if err := tm.sortTasksBy(filtered, opts.Sort, opts.Reverse); err != nil {
+			return err
+		}
+		return tm.writeTasksJSON(paginate(filtered, opts.Offset, opts.Limit))
+	}
+
+	if len(tasks) == 0 {
+		if !opts.OnlyIDs {
+			fmt.Fprintln(tm.out, "No tasks found.")
+		}
+		return nil
+	}
+
+	filtered, err := filterTasks(tasks, opts)
+	if err != nil {
+		return err
 	}
 
 	if len(filtered) == 0 {
-		fmt.Println("No tasks match the current filters.")
+		if !opts.OnlyIDs {
+			fmt.Fprintln(tm.out, "No tasks match the current filters.")
+		}
 		return nil
 	}
 
-	// Sort by priority (High -> Medium -> Low) and then by due date
-	sort.Slice(filtered, func(i, j int) bool {
-		if filtered[i].Priority != filtered[j].Priority {
-			return filtered[i].Priority > filtered[j].Priority // Higher priority first
+	if err := tm.sortTasksBy(filtered, opts.Sort, opts.Reverse); err != nil {
+		return err
+	}
+
+	total := len(filtered)
+	paged := opts.Limit > 0 || opts.Offset > 0
+	filtered = paginate(filtered, opts.Offset, opts.Limit)
+
+	if opts.OnlyIDs {
+		for _, t := range filtered {
+			fmt.Fprintln(tm.out, t.ID)
 		}
-		if filtered[i].DueDate.IsZero() && !filtered[j].DueDate.IsZero() {
-			return false
+		return nil
+	}
+
+	// Display tasks
+	fmt.Fprintf(tm.out, "\n%s %s\n", tm.Icons().List, tm.paint(ansiBold, fmt.Sprintf("Task List (%d tasks)", total)))
+	fmt.Fprintln(tm.out, strings.Repeat("=", 50))
+	if paged {
+		defer fmt.Fprintln(tm.out, pageFooter(opts.Offset, len(filtered), total))
+	}
+
+	// Highlighting only understands plain terms
+	highlightTerm := opts.Search
+	if opts.Regex {
+		highlightTerm = ""
+	}
+
+	show := func(t *task.Task) { tm.displayTask(t, highlightTerm) }
+	if opts.Explain {
+		predicates, err := listPredicates(opts)
+		if err != nil {
+			return err
 		}
-		if !filtered[i].DueDate.IsZero() && filtered[j].DueDate.IsZero() {
-			return true
+		show = func(t *task.Task) {
+			tm.displayTask(t, highlightTerm)
+			fmt.Fprintf(tm.out, "   Matched: %s\n", explainMatch(predicates, t))
 		}
-		return filtered[i].DueDate.Before(filtered[j].DueDate)
-	})
+	}
 
-	// Display tasks
-	fmt.Printf("\n📋 Task List (%d tasks)\n", len(filtered))
-	fmt.Println(strings.Repeat("=", 50))
+	if opts.Tree {
+		tm.displayTree(filtered, show)
+		return nil
+	}
 
 	for _, t := range filtered {
-		tm.displayTask(t)
-		fmt.Println()
+		show(t)
+		fmt.Fprintln(tm.out)
 	}
 
 	return nil
 }
 
-// Complete marks a task as completed
-func (tm *TaskManager) Complete(ctx context.Context, id string) error {
-	t, err := tm.storage.GetByID(ctx, id)
+// filterTasks returns the tasks matching the list options
+func filterTasks(tasks []*task.Task, opts ListOptions) ([]*task.Task, error) {
+	predicates, err := listPredicates(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*task.Task, 0)
+	for _, t := range tasks {
+		if matchesAll(predicates, t) {
+			filtered = append(filtered, t)
+		}
+	}
+
+	return filtered, nil
+}
+
+// paginate returns the tasks after skipping offset, at most limit of them.
+// A limit of zero or less means no limit.
+func paginate(tasks []*task.Task, offset, limit int) []*task.Task {
+	offset = min(max(offset, 0), len(tasks))
+	tasks = tasks[offset:]
+	if limit > 0 && limit < len(tasks) {
+		tasks = tasks[:limit]
+	}
+	return tasks
+}
+
+// pageFooter describes which slice of the matching tasks a page shows
+func pageFooter(offset, shown, total int) string {
+	if shown == 0 {
+		return fmt.Sprintf("Showing none of %d (offset %d is past the end)", total, offset)
+	}
+	return fmt.Sprintf("Showing %d-%d of %d", offset+1, offset+shown, total)
+}
+
+// taskPredicate is one list filter. The label names it in list --explain;
+// the implicit pending filter has none.
+type taskPredicate struct {
+	label   string
+	matches func(t *task.Task) bool
+}
+
+// listPredicates builds the filters the list options ask for
+func listPredicates(opts ListOptions) ([]taskPredicate, error) {
+	var predicates []taskPredicate
+	if !opts.ShowCompleted {
+		predicates = append(predicates, taskPredicate{matches: func(t *task.Task) bool { return !t.Completed }})
+	}
+	if !opts.Scheduled {
+		now := time.Now()
+		predicates = append(predicates, taskPredicate{matches: func(t *task.Task) bool { return t.IsActionable(now) }})
+	}
+	if f := opts.Priority; f != nil {
+		predicates = append(predicates, taskPredicate{f.Label(), func(t *task.Task) bool { return f.Matches(t.Priority) }})
+	}
+	if f := opts.PriorityRange; f != nil {
+		predicates = append(predicates, taskPredicate{f.Label(), func(t *task.Task) bool { return f.Matches(t.Priority) }})
+	}
+	if opts.Search != "" {
+		f, err := filter.CreateSearchFilter(opts.Search, opts.Regex)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, taskPredicate{f.Label(), func(t *task.Task) bool { return matchesSearch(&f, t, opts.SearchTags) }})
+	}
+	if opts.Tag != "" {
+		predicates = append(predicates, taskPredicate{"tag:" + opts.Tag, func(t *task.Task) bool { return hasTag(t, opts.Tag) }})
+	}
+	if opts.Due != "" {
+		f, err := filter.CreateTaskDueFilter(opts.Due)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due filter: %w", err)
+		}
+		predicates = append(predicates, taskPredicate{f.Label(), func(t *task.Task) bool { return f.Matches(t.DueDate) }})
+	} else if f := opts.DueFilter; f != nil {
+		predicates = append(predicates, taskPredicate{f.Label(), func(t *task.Task) bool { return f.Matches(t.DueDate) }})
+	}
+	if opts.Weekday != "" {
+		f, err := filter.CreateWeekdayFilter(opts.Weekday)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, taskPredicate{f.Label(), func(t *task.Task) bool { return f.Matches(t.DueDate) }})
+	}
+	return predicates, nil
+}
+
+// matchesAll reports whether t passes every predicate
+func matchesAll(predicates []taskPredicate, t *task.Task) bool {
+	for _, p := range predicates {
+		if !p.matches(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// explainMatch lists the labels of the predicates t passed, e.g.
+// "[priority:high due:next7d]"
+func explainMatch(predicates []taskPredicate, t *task.Task) string {
+	var labels []string
+	for _, p := range predicates {
+		if p.label != "" && p.matches(t) {
+			labels = append(labels, p.label)
+		}
+	}
+	if len(labels) == 0 {
+		return "[no filters]"
+	}
+	return "[" + strings.Join(labels, " ") + "]"
+}
+
+// matchesSearch checks the title and description, and the tags when asked
+func matchesSearch(f *filter.SearchFilter, t *task.Task, tags bool) bool {
+	if f.Matches(t.Title, t.Description) {
+		return true
+	}
+	return tags && f.Matches(t.Tags...)
+}
+
+// sortTasks orders tasks by priority (High -> Medium -> Low) and then by due date
+func sortTasks(tasks []*task.Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Priority != tasks[j].Priority {
+			return tasks[i].Priority > tasks[j].Priority // Higher priority first
+		}
+		if tasks[i].DueDate.IsZero() && !tasks[j].DueDate.IsZero() {
+			return false
+		}
+		if !tasks[i].DueDate.IsZero() && tasks[j].DueDate.IsZero() {
+			return true
+		}
+		return tasks[i].DueDate.Before(tasks[j].DueDate)
+	})
+}
+
+// Complete marks a task as completed along with its open subtasks, or
+// refuses with ErrOpenSubtasks when subtasks are required to be done first.
+// With SetAutoCompleteParent, finishing a parent's last open subtask
+// completes the parent too.
+func (tm *TaskManager) Complete(ctx context.Context, id string) (err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	t, err := tm.resolveID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get task: %w", err)
 	}
+	id = t.ID
 
+	wasCompleted := t.Completed
+	var openSubtasks []*task.Task
+	if !wasCompleted {
+		if openSubtasks, err = tm.openSubtasks(ctx, id); err != nil {
+			return err
+		}
+		if tm.requireSubtasks && len(openSubtasks) > 0 {
+			return fmt.Errorf("cannot complete %s: %w: %s", id, ErrOpenSubtasks, describeTasks(openSubtasks))
+		}
+	}
+
+	before := snapshot(t)
 	t.Complete()
-	return tm.storage.Update(ctx, id, t)
+	if err := tm.storage.Update(ctx, id, t); err != nil {
+		return err
+	}
+	if err := tm.logEvent("complete", id, before, t); err != nil {
+		return err
+	}
+
+	if wasCompleted {
+		return nil
+	}
+	if err := tm.completeSubtasks(ctx, openSubtasks); err != nil {
+		return err
+	}
+
+	// Completing a recurring task schedules its next occurrence
+	if next := t.Spawn(time.Now()); next != nil {
+		if err := tm.AddTask(ctx, next); err != nil {
+			return fmt.Errorf("failed to schedule next occurrence: %w", err)
+		}
+		fmt.Fprintf(tm.out, "%s Next occurrence %s due %s\n", tm.Icons().Repeats, next.ID, next.DueDate.Format("2006-01-02"))
+	}
+
+	if tm.autoCompleteParent && t.ParentID != "" {
+		return tm.completeFinishedParent(ctx, t.ParentID)
+	}
+	return nil
 }
 
 // Uncomplete marks a task as not completed
 func (tm *TaskManager) Uncomplete(ctx context.Context, id string) error {
-	t, err := tm.storage.GetByID(ctx, id)
+	t, err := tm.resolveID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get task: %w", err)
 	}
+	id = t.ID
 
+	before := snapshot(t)
 	t.Uncomplete()
-	return tm.storage.Update(ctx, id, t)
+	if err := tm.storage.Update(ctx, id, t); err != nil {
+		return err
+	}
+	return tm.logEvent("update", id, before, t)
 }
 
-// Delete removes a task
+// Delete removes a task and strips references to it from other tasks. A task
+// with subtasks is refused with ErrHasSubtasks; use DeleteCascade instead.
 func (tm *TaskManager) Delete(ctx context.Context, id string) error {
-	// Check if task exists first
-	_, err := tm.storage.GetByID(ctx, id)
+	return tm.deleteTask(ctx, id, false)
+}
+
+// DeleteCascade removes a task together with all of its subtasks
+func (tm *TaskManager) DeleteCascade(ctx context.Context, id string) error {
+	return tm.deleteTask(ctx, id, true)
+}
+
+// deleteTask removes a task, and with cascade its descendants, in one save
+func (tm *TaskManager) deleteTask(ctx context.Context, id string, cascade bool) (err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	target, err := tm.findByIDPrefix(tasks, id)
 	if err != nil {
 		return fmt.Errorf("failed to get task: %w", err)
 	}
+	id = target.ID
 
-	return tm.storage.Delete(ctx, id)
+	descendants := descendantIDs(tasks, id)
+	if len(descendants) > 0 && !cascade {
+		return fmt.Errorf("cannot delete %s: %w", id, ErrHasSubtasks)
+	}
+
+	doomed := map[string]bool{id: true}
+	for _, d := range descendants {
+		doomed[d] = true
+	}
+
+	remaining := make([]*task.Task, 0, len(tasks))
+	var deleted []*task.Task
+	for _, t := range tasks {
+		if doomed[t.ID] {
+			deleted = append(deleted, t)
+			continue
+		}
+		remaining = append(remaining, t)
+	}
+
+	unlinked := removeReferences(remaining, doomed)
+
+	if err := tm.storage.Save(ctx, remaining); err != nil {
+		return err
+	}
+	return tm.logDeleted(deleted, unlinked)
 }
 
-// Update modifies an existing task
-func (tm *TaskManager) Update(ctx context.Context, id, title, description string, priority task.Priority, dueDate time.Time) error {
+// removeReferences strips dependencies and links to doomed tasks from
+// tasks, returning the changed tasks as event pairs of before and after
+func removeReferences(tasks []*task.Task, doomed map[string]bool) [][2]*task.Task {
+	var changed [][2]*task.Task
+	for _, t := range tasks {
+		before := snapshot(t)
+		removed := false
+		for doomedID := range doomed {
+			if t.RemoveReferences(doomedID) {
+				removed = true
+			}
+		}
+		if removed {
+			changed = append(changed, [2]*task.Task{before, t})
+		}
+	}
+	return changed
+}
+
+// logChanges logs before and after pairs as adds when before is nil,
+// deletes when after is nil and updates otherwise
+func (tm *TaskManager) logChanges(changed [][2]*task.Task) error {
+	for _, pair := range changed {
+		op, id := "update", ""
+		switch {
+		case pair[0] == nil:
+			op, id = "add", pair[1].ID
+		case pair[1] == nil:
+			op, id = "delete", pair[0].ID
+		default:
+			id = pair[1].ID
+		}
+		if err := tm.logEvent(op, id, pair[0], pair[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logDeleted logs the deletions and the reference updates they caused
+func (tm *TaskManager) logDeleted(deleted []*task.Task, unlinked [][2]*task.Task) error {
+	for _, pair := range unlinked {
+		if err := tm.logEvent("update", pair[1].ID, pair[0], pair[1]); err != nil {
+			return err
+		}
+	}
+	for _, t := range deleted {
+		if err := tm.logEvent("delete", t.ID, t, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddDependency records that a task is blocked by another task
+func (tm *TaskManager) AddDependency(ctx context.Context, id, blockerID string) error {
+	if id == blockerID {
+		return fmt.Errorf("a task cannot depend on itself")
+	}
+
 	t, err := tm.storage.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get task: %w", err)
 	}
+	if _, err := tm.storage.GetByID(ctx, blockerID); err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
 
-	if err := t.Update(title, description, priority, dueDate); err != nil {
-		return fmt.Errorf("failed to update task: %w", err)
+	before := snapshot(t)
+	if !t.AddDependency(blockerID) {
+		return nil
+	}
+	if err := tm.storage.Update(ctx, id, t); err != nil {
+		return err
+	}
+	return tm.logEvent("update", id, before, t)
+}
+
+// Link marks two tasks as related to each other
+func (tm *TaskManager) Link(ctx context.Context, idA, idB string) (err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	if idA == idB {
+		return fmt.Errorf("cannot link a task to itself")
 	}
 
-	return tm.storage.Update(ctx, id, t)
+	a, err := tm.storage.GetByID(ctx, idA)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	b, err := tm.storage.GetByID(ctx, idB)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	beforeA, beforeB := snapshot(a), snapshot(b)
+	if a.AddRelated(b.ID) {
+		if err := tm.updateLogged(ctx, beforeA, a); err != nil {
+			return err
+		}
+	}
+	if b.AddRelated(a.ID) {
+		if err := tm.updateLogged(ctx, beforeB, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-// Show displays a single task by ID
-func (tm *TaskManager) Show(ctx context.Context, id string) error {
-	t, err := tm.storage.GetByID(ctx, id)
+// Unlink removes the relationship between two tasks
+func (tm *TaskManager) Unlink(ctx context.Context, idA, idB string) (err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	a, err := tm.storage.GetByID(ctx, idA)
 	if err != nil {
 		return fmt.Errorf("failed to get task: %w", err)
 	}
+	b, err := tm.storage.GetByID(ctx, idB)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	beforeA, beforeB := snapshot(a), snapshot(b)
+	changedA := a.RemoveRelated(b.ID)
+	changedB := b.RemoveRelated(a.ID)
+	if !changedA && !changedB {
+		return fmt.Errorf("tasks %s and %s are not linked", idA, idB)
+	}
 
-	fmt.Printf("\n📝 Task Details\n")
-	fmt.Println(strings.Repeat("=", 30))
-	tm.displayTask(t)
-	fmt.Println()
+	if changedA {
+		if err := tm.updateLogged(ctx, beforeA, a); err != nil {
+			return err
+		}
+	}
+	if changedB {
+		if err := tm.updateLogged(ctx, beforeB, b); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-// Stats displays task statistics
-func (tm *TaskManager) Stats(ctx context.Context) error {
+// updateLogged stores a changed task and logs it as an update from before
+func (tm *TaskManager) updateLogged(ctx context.Context, before, t *task.Task) error {
+	if err := tm.storage.Update(ctx, t.ID, t); err != nil {
+		return err
+	}
+	return tm.logEvent("update", t.ID, before, t)
+}
+
+// Purge permanently removes all completed tasks and returns how many were removed
+func (tm *TaskManager) Purge(ctx context.Context, force bool) (n int, err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
 	tasks, err := tm.storage.Load(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to load tasks: %w", err)
+		return 0, fmt.Errorf("failed to load tasks: %w", err)
 	}
 
-	var total, completed, overdue, dueToday, dueSoon int
-	priorityCount := make(map[task.Priority]int)
+	if err := tm.guardBulkDestructive(tasks, force); err != nil {
+		return 0, err
+	}
 
+	remaining := make([]*task.Task, 0, len(tasks))
+	var purgedTasks []*task.Task
 	for _, t := range tasks {
-		total++
 		if t.Completed {
-			completed++
-		} else {
-			if t.IsOverdue() {
-				overdue++
+			purgedTasks = append(purgedTasks, t)
+			continue
+		}
+		remaining = append(remaining, t)
+	}
+
+	purged := len(purgedTasks)
+	if purged == 0 {
+		fmt.Fprintln(tm.out, "No completed tasks to purge.")
+		return 0, nil
+	}
+
+	if err := tm.storage.Save(ctx, remaining); err != nil {
+		return 0, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	if err := tm.logDeleted(purgedTasks, nil); err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintf(tm.out, "%s Purged %d completed tasks\n", tm.Icons().Deleted, purged)
+	return purged, nil
+}
+
+// Update modifies an existing task. Nil tags keep the current ones.
+func (tm *TaskManager) Update(ctx context.Context, id, title, description string, priority task.Priority, dueDate time.Time, tags []string) error {
+	return tm.UpdateFields(ctx, id, TaskUpdate{
+		Title:       &title,
+		Description: &description,
+		Priority:    &priority,
+		DueDate:     &dueDate,
+		Tags:        tags,
+	})
+}
+
+// TaskUpdate lists the fields UpdateFields changes; nil leaves a field as
+// it is
+type TaskUpdate struct {
+	Title       *string
+	Description *string
+	Priority    *task.Priority
+	DueDate     *time.Time // the zero time clears the due date
+	StartDate   *time.Time // the zero time clears the start date
+	Tags        []string
+}
+
+// UpdateFields changes the fields set in u and keeps the rest of the task.
+// The new fields are validated together and saved once, as one undoable
+// change, so a new due date is checked against a new start date.
+func (tm *TaskManager) UpdateFields(ctx context.Context, id string, u TaskUpdate) error {
+	if u.Title != nil {
+		if err := tm.checkTitle(*u.Title); err != nil {
+			return err
+		}
+	}
+
+	t, err := tm.resolveID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	id = t.ID
+
+	title, description, priority, dueDate := t.Title, t.Description, t.Priority, t.DueDate
+	if u.Title != nil {
+		title = *u.Title
+	}
+	if u.Description != nil {
+		description = *u.Description
+	}
+	if u.Priority != nil {
+		priority = *u.Priority
+	}
+	if u.DueDate != nil {
+		dueDate = *u.DueDate
+	}
+
+	before := snapshot(t)
+	if u.StartDate != nil {
+		t.StartDate = *u.StartDate
+	}
+	if err := t.Update(title, description, priority, dueDate, u.Tags); err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	if err := tm.storage.Update(ctx, id, t); err != nil {
+		return err
+	}
+	return tm.logEvent("update", id, before, t)
+}
+
+// SetPriority changes only the priority of one task
+func (tm *TaskManager) SetPriority(ctx context.Context, id string, priority task.Priority) error {
+	t, err := tm.resolveID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	id = t.ID
+
+	before := snapshot(t)
+	t.Priority = priority
+	t.UpdatedAt = time.Now()
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid task: %w", err)
+	}
+
+	if err := tm.storage.Update(ctx, id, t); err != nil {
+		return err
+	}
+	return tm.logEvent("update", id, before, t)
+}
+
+// Show displays a single task by ID
+func (tm *TaskManager) Show(ctx context.Context, id string) error {
+	t, err := tm.resolveID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if tm.jsonOutput {
+		return tm.writeJSON(newTaskView(t, time.Now()))
+	}
+
+	icons := tm.Icons()
+
+	fmt.Fprintf(tm.out, "\n%s %s\n", icons.Details, tm.paint(ansiBold, "Task Details"))
+	fmt.Fprintln(tm.out, strings.Repeat("=", 30))
+	tm.displayTask(t, "")
+
+	// Blocking tasks
+	if len(t.DependsOn) > 0 {
+		fmt.Fprintf(tm.out, "   %s Depends on:\n", icons.DependsOn)
+		for _, blockerID := range t.DependsOn {
+			blocker, err := tm.storage.GetByID(ctx, blockerID)
+			if err != nil {
+				fmt.Fprintf(tm.out, "      - %s (missing)\n", blockerID)
+				continue
 			}
-			if t.IsDueToday() {
-				dueToday++
+			fmt.Fprintf(tm.out, "      - %s (%s)\n", blocker.Title, blocker.ID)
+		}
+	}
+
+	// Direct subtasks
+	all, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+	if children := childrenOf(all)[t.ID]; len(children) > 0 {
+		fmt.Fprintf(tm.out, "   %s Subtasks:\n", icons.Subtasks)
+		for _, child := range children {
+			state := icons.Pending
+			if child.Completed {
+				state = icons.Done
 			}
-			if t.IsDueSoon() {
-				dueSoon++
+			fmt.Fprintf(tm.out, "      %s %s (%s)\n", state, child.Title, child.ID)
+		}
+	}
+
+	// Related tasks
+	if len(t.RelatedTo) > 0 {
+		fmt.Fprintf(tm.out, "   %s Related:\n", icons.Related)
+		for _, relatedID := range t.RelatedTo {
+			related, err := tm.storage.GetByID(ctx, relatedID)
+			if err != nil {
+				fmt.Fprintf(tm.out, "      - %s (missing)\n", relatedID)
+				continue
 			}
+			fmt.Fprintf(tm.out, "      - %s (%s)\n", related.Title, related.ID)
 		}
-		priorityCount[t.Priority]++
 	}
 
-	fmt.Printf("\n📊 Task Statistics\n")
-	fmt.Println(strings.Repeat("=", 25))
-	fmt.Printf("Total tasks: %d\n", total)
-	fmt.Printf("Completed: %d\n", completed)
-	fmt.Printf("Remaining: %d\n", total-completed)
-	fmt.Printf("Overdue: %d\n", overdue)
-	fmt.Printf("Due today: %d\n", dueToday)
-	fmt.Printf("Due soon (7 days): %d\n", dueSoon)
-	fmt.Println()
-	fmt.Println("By Priority:")
-	for p := task.High; p >= task.Low; p-- {
-		fmt.Printf("  %s: %d\n", p.String(), priorityCount[p])
+	// Notes, newest first
+	if len(t.Notes) > 0 {
+		fmt.Fprintf(tm.out, "   %s Notes:\n", icons.Notes)
+		for _, n := range t.NotesNewestFirst() {
+			fmt.Fprintf(tm.out, "      - %s  %s\n", n.CreatedAt.Format("2006-01-02 15:04"), n.Text)
+		}
 	}
-	fmt.Println()
+	fmt.Fprintln(tm.out)
 
 	return nil
 }
 
+// Stats displays task statistics, or prints them as JSON when the output
+// format is json
+func (tm *TaskManager) Stats(ctx context.Context) error {
+	stats, err := tm.ComputeStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	if tm.jsonOutput {
+		return tm.writeJSON(stats)
+	}
+
+	// Only counts that need attention are colored
+	warn := func(style string, n int) string {
+		if n == 0 {
+			return "0"
+		}
+		return tm.paint(style, fmt.Sprint(n))
+	}
+
+	fmt.Fprintf(tm.out, "\n%s %s\n", tm.Icons().Stats, tm.paint(ansiBold, "Task Statistics"))
+	fmt.Fprintln(tm.out, strings.Repeat("=", 25))
+	fmt.Fprintf(tm.out, "Total tasks: %d\n", stats.Total)
+	fmt.Fprintf(tm.out, "Completed: %s\n", tm.paint(ansiGreen, fmt.Sprint(stats.Completed)))
+	fmt.Fprintf(tm.out, "Completed late: %d\n", stats.CompletedLate)
+	fmt.Fprintf(tm.out, "Remaining: %d\n", stats.Remaining())
+	fmt.Fprintf(tm.out, "Overdue: %s\n", warn(ansiRed, stats.Overdue))
+	fmt.Fprintf(tm.out, "Due today: %s\n", warn(ansiYellow, stats.DueToday))
+	fmt.Fprintf(tm.out, "Due soon (%s): %d\n", formatDays(tm.dueSoon()), stats.DueSoon)
+	fmt.Fprintln(tm.out)
+	fmt.Fprintln(tm.out, "By Priority:")
+	for p := task.Urgent; p >= task.Low; p-- {
+		fmt.Fprintf(tm.out, "  %s: %d\n", tm.config.PriorityLabel(p), stats.ByPriority[p])
+	}
+	fmt.Fprintln(tm.out)
+
+	if stats.Overdue > 0 {
+		fmt.Fprintln(tm.out, "Overdue by Priority:")
+		for p := task.Urgent; p >= task.Low; p-- {
+			fmt.Fprintf(tm.out, "  %s: %d\n", tm.config.PriorityLabel(p), stats.OverdueByPriority[p])
+		}
+		fmt.Fprintln(tm.out)
+	}
+
+	if tags := stats.TagsByPendingCount(); len(tags) > 0 {
+		fmt.Fprintln(tm.out, "Pending by Tag:")
+		for _, tag := range tags {
+			fmt.Fprintf(tm.out, "  %s: %d\n", tag, stats.PendingByTag[tag])
+		}
+		fmt.Fprintln(tm.out)
+	}
+
+	return nil
+}
+
+// ExportOptions tweaks the content of an export
+type ExportOptions struct {
+	Summary           bool // prepend aggregate counts (JSON envelope or CSV comment)
+	KeepEmptySections bool // markdown and html: write sections even with no tasks
+	PriorityNumeric   bool // CSV: write priorities as numbers, higher is more important
+	Append            bool // json merges into the file by ID; jsonl adds lines
+}
+
+// csvPriority renders a priority for the CSV Priority column. Numbers start
+// at 1 for Low so that no priority is written as 0. Labels are written as
+// they are; the CSV writer quotes any comma in them.
+func (tm *TaskManager) csvPriority(p task.Priority, numeric bool) string {
+	if numeric {
+		return strconv.Itoa(int(p) + 1)
+	}
+	return tm.config.PriorityLabel(p)
+}
+
 // ExportTasks exports tasks to different formats
-func (tm *TaskManager) ExportTasks(ctx context.Context, format string, filename string) error {
+func (tm *TaskManager) ExportTasks(ctx context.Context, format string, filename string, opts ExportOptions) error {
 	tasks, err := tm.storage.Load(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load tasks: %w", err)
 	}
 
-	switch strings.ToLower(format) {
-	case "json":
-		return tm.exportJSON(tasks, filename)
-	case "csv":
-		return tm.exportCSV(tasks, filename)
-	case "markdown", "md":
-		return tm.exportMarkdown(tasks, filename)
-	default:
-		return fmt.Errorf("unsupported export format: %s", format)
+	return tm.exportFormat(tasks, format, filename, opts)
+}
+
+// exportFormat writes tasks to filename in the given format
+func (tm *TaskManager) exportFormat(tasks []*task.Task, format, filename string, opts ExportOptions) error {
+	if opts.Append {
+		if err := checkAppendFormat(format); err != nil {
+			return err
+		}
+
+		// Appending reads the existing file, so it can't go through an Exporter
+		switch strings.ToLower(format) {
+		case "json":
+			return tm.appendJSON(tasks, filename, opts)
+		case "jsonl":
+			return tm.appendJSONL(tasks, filename)
+		}
+	}
+
+	e, err := tm.exporters(opts).Lookup(format)
+	if err != nil {
+		return err
 	}
+	return storage.ExportFile(e, filename, tasks)
 }
 
-// ConcurrentExport exports tasks to multiple formats concurrently
-func (tm *TaskManager) ConcurrentExport(ctx context.Context, formats []string, baseFilename string) error {
+// ExportByTag writes one file per tag into dir, named <tag>.<format>. Tags
+// that make the same file name, such as "a/b" and "a_b", get a numbered
+// suffix. At most maxOpen files are open at once; a value <= 0 uses the CPU
+// count. Tags not yet started when ctx is cancelled are skipped and
+// reported with the context error.
+func (tm *TaskManager) ExportByTag(ctx context.Context, format, dir string, maxOpen int) error {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	// Group tasks by tag
+	byTag := make(map[string][]*task.Task)
+	for _, t := range tasks {
+		for _, tag := range t.Tags {
+			byTag[tag] = append(byTag[tag], t)
+		}
+	}
+
+	if len(byTag) == 0 {
+		fmt.Fprintln(tm.out, "No tagged tasks to export.")
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	filenames := tagFilenames(slices.Sorted(maps.Keys(byTag)))
+	for tag, name := range filenames {
+		if name != tagFilename(tag) {
+			fmt.Fprintf(tm.out, "Tag %q is written to %s, as another tag has its file name\n", tag, name)
+		}
+	}
+
+	if maxOpen <= 0 {
+		maxOpen = runtime.NumCPU()
+	}
+	workers := min(maxOpen, len(byTag))
+
+	type exportResult struct {
+		tag string
+		err error
+	}
+
+	jobs := make(chan string, len(byTag))
+	for tag := range byTag {
+		jobs <- tag
+	}
+	close(jobs)
+
+	results := make(chan exportResult, len(byTag))
+
+	// Start the worker pool; each worker has one file open at a time
+	for i := 0; i < workers; i++ {
+		go func() {
+			for tag := range jobs {
+				if err := ctx.Err(); err != nil {
+					results <- exportResult{tag: tag, err: err}
+					continue
+				}
+				filename := filepath.Join(dir, filenames[tag]+"."+strings.ToLower(format))
+				results <- exportResult{tag: tag, err: tm.exportFormat(byTag[tag], format, filename, ExportOptions{})}
+			}
+		}()
+	}
+
+	// Collect results
+	var errors []string
+	for i := 0; i < len(byTag); i++ {
+		result := <-results
+		if result.err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", result.tag, result.err))
+		}
+	}
+
+	if len(errors) > 0 {
+		sort.Strings(errors)
+		return fmt.Errorf("export errors: %s", strings.Join(errors, "; "))
+	}
+
+	fmt.Fprintf(tm.out, "%s Exported %d tags to %s\n", tm.Icons().Success, len(byTag), dir)
+	return nil
+}
+
+// tagFilename makes a tag safe to use as a file name
+func tagFilename(tag string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(tag)
+}
+
+// tagFilenames maps each of the sorted tags to a distinct file name, adding
+// -2, -3 and so on when tagFilename gives one already taken. Names compare
+// case-insensitively so they stay distinct on case-insensitive file systems.
+func tagFilenames(tags []string) map[string]string {
+	names := make(map[string]string, len(tags))
+	taken := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		base := tagFilename(tag)
+		name := base
+		for n := 2; taken[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken[strings.ToLower(name)] = true
+		names[tag] = name
+	}
+	return names
+}
+
+// ConcurrentExport exports tasks to multiple formats concurrently, writing
+// <baseFilename>.<format> files into outputDir (created if needed, "" for
+// the working directory). At most workers exports run at once; a value <= 0
+// uses the CPU count. Formats not yet started when ctx is cancelled are
+// skipped and reported with the context error.
+func (tm *TaskManager) ConcurrentExport(ctx context.Context, formats []string, outputDir, baseFilename string, workers int) error {
 	if len(formats) == 0 {
 		return fmt.Errorf("no formats specified")
 	}
 
+	// Resolve every path before writing anything, so one bad name aborts
+	// the whole export
+	if outputDir == "" {
+		outputDir = "."
+	}
+	paths := make(map[string]string, len(formats))
+	for _, format := range formats {
+		path, err := exportPath(outputDir, baseFilename, format)
+		if err != nil {
+			return err
+		}
+		paths[format] = path
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
+	}
+
 	tasks, err := tm.storage.Load(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load tasks: %w", err)
 	}
 
-	// Create channels for results
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(formats))
+
 	type exportResult struct {
 		format string
 		err    error
 	}
 
+	jobs := make(chan string, len(formats))
+	for _, format := range formats {
+		jobs <- format
+	}
+	close(jobs)
+
 	results := make(chan exportResult, len(formats))
 
-	// Start export goroutines
-	for _, format := range formats {
-		go func(formatName string) {
-			filename := baseFilename + "." + formatName
-			var err error
-			switch strings.ToLower(formatName) {
-			case "json":
-				err = tm.exportJSON(tasks, filename)
-			case "csv":
-				err = tm.exportCSV(tasks, filename)
-			case "markdown", "md":
-				err = tm.exportMarkdown(tasks, filename)
-			default:
-				err = fmt.Errorf("unsupported export format: %s", formatName)
+	// Workers report success themselves so the message is out before they
+	// pick up the next format
+	var mu sync.Mutex
+	for i := 0; i < workers; i++ {
+		go func() {
+			for format := range jobs {
+				if err := ctx.Err(); err != nil {
+					results <- exportResult{format: format, err: err}
+					continue
+				}
+				err := tm.exportFormat(tasks, format, paths[format], ExportOptions{})
+				if err == nil {
+					mu.Lock()
+					fmt.Fprintf(tm.out, "%s Exported to %s\n", tm.Icons().Success, paths[format])
+					mu.Unlock()
+				}
+				results <- exportResult{format: format, err: err}
 			}
-			results <- exportResult{format: formatName, err: err}
-		}(format)
+		}()
 	}
 
 	// Collect results
@@ -270,126 +1257,165 @@ func (tm *TaskManager) ConcurrentExport(ctx context.Context, formats []string, b
 		result := <-results
 		if result.err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", result.format, result.err))
-		} else {
-			fmt.Printf("✅ Exported to %s.%s\n", baseFilename, result.format)
 		}
 	}
 
 	if len(errors) > 0 {
+		sort.Strings(errors)
 		return fmt.Errorf("export errors: %s", strings.Join(errors, "; "))
 	}
 
 	return nil
 }
 
-// displayTask displays a single task in a formatted way
-func (tm *TaskManager) displayTask(t *task.Task) {
-	// Status icon and title
-	status := "⏳"
-	if t.Completed {
-		status = "✅"
-	} else if t.IsOverdue() {
-		status = "🚨"
-	} else if t.IsDueToday() {
-		status = "📅"
-	} else if t.IsDueSoon() {
-		status = "⏰"
+// exportPath joins dir with <base>.<format>, rejecting names that would
+// resolve outside dir
+func exportPath(dir, base, format string) (string, error) {
+	name := base + "." + format
+	if base == "" || base == "." || base == ".." || strings.ContainsAny(name, `/\`) || filepath.IsAbs(name) {
+		return "", fmt.Errorf("invalid export name %q: use a plain file name and --output-dir for the location", name)
 	}
+	return filepath.Join(dir, name), nil
+}
 
-	// Priority indicator
-	priorityIcon := ""
-	switch t.Priority {
-	case task.High:
-		priorityIcon = "🔴"
-	case task.Medium:
-		priorityIcon = "🟡"
-	case task.Low:
-		priorityIcon = "🟢"
+// displayTask displays a single task in a formatted way, highlighting
+// occurrences of the search term when one is given
+func (tm *TaskManager) displayTask(t *task.Task, searchTerm string) {
+	mark := highlight
+	if tm.color {
+		mark = highlightANSI
 	}
 
-	fmt.Printf("%s %s %s\n", status, priorityIcon, t.Title)
+	icons := tm.Icons()
+
+	// Status icon, priority indicator and title; finished tasks are dimmed
+	titleStyle := ansiBold
+	if t.Completed {
+		titleStyle = ansiDim
+	}
+	fmt.Fprintf(tm.out, "%s %s %s\n", icons.StatusIcon(t), icons.PriorityIcon(t.Priority), tm.paint(titleStyle, mark(t.Title, searchTerm)))
 
 	if t.Description != "" {
-		fmt.Printf("   📝 %s\n", t.Description)
+		fmt.Fprintf(tm.out, "   %s %s\n", icons.Description, mark(t.Description, searchTerm))
 	}
 
+	fmt.Fprintf(tm.out, "   %s Priority: %s\n", icons.Priority, tm.config.PriorityLabel(t.Priority))
+
 	if t.Tags != nil {
-		fmt.Printf("   🏷️  %v\n", t.Tags)
+		fmt.Fprintf(tm.out, "   %s %v\n", icons.Tags, t.Tags)
 	}
 
 	// Due date
 	if !t.DueDate.IsZero() {
 		dueStr := t.DueDate.Format("2006-01-02 15:04")
 		if t.IsOverdue() {
-			fmt.Printf("   ⏰ Due: %s (OVERDUE)\n", dueStr)
+			fmt.Fprintf(tm.out, "   %s %s\n", icons.Due, tm.paint(ansiRed, "Due: "+dueStr+" (OVERDUE)"))
 		} else if t.IsDueToday() {
-			fmt.Printf("   ⏰ Due: %s (TODAY)\n", dueStr)
+			fmt.Fprintf(tm.out, "   %s %s\n", icons.Due, tm.paint(ansiYellow, "Due: "+dueStr+" (TODAY)"))
 		} else {
-			fmt.Printf("   ⏰ Due: %s\n", dueStr)
+			fmt.Fprintf(tm.out, "   %s Due: %s\n", icons.Due, dueStr)
 		}
 	}
 
+	if !t.StartDate.IsZero() {
+		fmt.Fprintf(tm.out, "   %s Starts: %s\n", icons.Due, t.StartDate.Format("2006-01-02 15:04"))
+	}
+
+	if t.Recurrence != nil {
+		fmt.Fprintf(tm.out, "   %s Repeats: %s\n", icons.Repeats, t.Recurrence)
+	}
+	if t.ParentID != "" {
+		fmt.Fprintf(tm.out, "   %s Parent: %s\n", icons.Subtasks, t.ParentID)
+	}
+	if t.EstimateMinutes > 0 {
+		fmt.Fprintf(tm.out, "   %s Estimate: %s\n", icons.Estimate, formatMinutes(t.EstimateMinutes))
+	}
+
 	// ID and timestamps
-	fmt.Printf("   🆔 ID: %s\n", t.ID)
-	fmt.Printf("   📅 Created: %s\n", t.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Fprintf(tm.out, "   %s ID: %s\n", icons.ID, t.ID)
+	fmt.Fprintf(tm.out, "   %s Created: %s\n", icons.Created, t.CreatedAt.Format("2006-01-02 15:04"))
 	if t.UpdatedAt.After(t.CreatedAt) {
-		fmt.Printf("   🔄 Updated: %s\n", t.UpdatedAt.Format("2006-01-02 15:04"))
+		fmt.Fprintf(tm.out, "   %s Updated: %s\n", icons.Updated, t.UpdatedAt.Format("2006-01-02 15:04"))
+	}
+	if !t.CompletedAt.IsZero() {
+		late := ""
+		if t.WasLate {
+			late = " (LATE)"
+		}
+		fmt.Fprintf(tm.out, "   %s %s\n", icons.Finished, tm.paint(ansiGreen, "Completed: "+t.CompletedAt.Format("2006-01-02 15:04")+late))
 	}
 }
 
-// exportJSON exports tasks to JSON format
-func (tm *TaskManager) exportJSON(tasks []*task.Task, filename string) error {
-	data, err := json.MarshalIndent(tasks, "", "  ")
+// writeJSONExport exports tasks to JSON format, wrapped with a summary if requested
+func (tm *TaskManager) writeJSONExport(w io.Writer, tasks []*task.Task, opts ExportOptions) error {
+	var payload any = tasks
+	if opts.Summary {
+		payload = struct {
+			Summary StatsResult  `json:"summary"`
+			Tasks   []*task.Task `json:"tasks"`
+		}{computeStats(tasks, tm.dueSoon()), tasks}
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
-
-	return os.WriteFile(filename, data, 0644)
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
 }
 
-// exportCSV exports tasks to CSV format
-func (tm *TaskManager) exportCSV(tasks []*task.Task, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %w", err)
+// writeCSV exports tasks to CSV format
+func (tm *TaskManager) writeCSV(out io.Writer, tasks []*task.Task, opts ExportOptions) error {
+	// Write summary as a leading comment line
+	if opts.Summary {
+		stats := computeStats(tasks, tm.dueSoon())
+		fmt.Fprintf(out, "# total=%d completed=%d overdue=%d due_today=%d due_soon=%d\n",
+			stats.Total, stats.Completed, stats.Overdue, stats.DueToday, stats.DueSoon)
 	}
-	defer file.Close()
 
-	// Write CSV header
-	fmt.Fprintln(file, "ID,Title,Description,Priority,Completed,Due Date,Created,Updated")
+	// Fields with commas, quotes or line breaks are quoted by the writer
+	w := csv.NewWriter(out)
+	w.Write([]string{"ID", "Title", "Description", "Priority", "Completed", "Due Date", "Created", "Updated", "Tags"})
 
 	// Write task data
 	for _, t := range tasks {
 		dueDate := ""
 		if !t.DueDate.IsZero() {
-			dueDate = t.DueDate.Format("2006-01-02 15:04")
+			dueDate = t.DueDate.Format(csvDateFormat)
 		}
-		fmt.Fprintf(file, "%s,%s,%s,%s,%t,%s,%s,%s\n",
+		w.Write([]string{
 			t.ID,
-			strings.ReplaceAll(t.Title, ",", ";"), // Escape commas
-			strings.ReplaceAll(t.Description, ",", ";"),
-			t.Priority.String(),
-			t.Completed,
+			t.Title,
+			t.Description,
+			tm.csvPriority(t.Priority, opts.PriorityNumeric),
+			strconv.FormatBool(t.Completed),
 			dueDate,
-			t.CreatedAt.Format("2006-01-02 15:04"),
-			t.UpdatedAt.Format("2006-01-02 15:04"),
-		)
+			t.CreatedAt.Format(csvDateFormat),
+			t.UpdatedAt.Format(csvDateFormat),
+			strings.Join(t.Tags, ";"),
+		})
 	}
 
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
 	return nil
 }
 
-// exportMarkdown exports tasks to Markdown format
-func (tm *TaskManager) exportMarkdown(tasks []*task.Task, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create Markdown file: %w", err)
-	}
-	defer file.Close()
-
+// writeMarkdown exports tasks to Markdown format
+func (tm *TaskManager) writeMarkdown(w io.Writer, tasks []*task.Task, opts ExportOptions) error {
 	// Write header
-	fmt.Fprintf(file, "# Task Export\n\n")
-	fmt.Fprintf(file, "Generated on: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "# Task Export\n\n")
+	fmt.Fprintf(w, "Generated on: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	// Write progress summary
+	stats := computeStats(tasks, tm.dueSoon())
+	fmt.Fprintf(w, "## Summary\n\n")
+	fmt.Fprintf(w, "**Total:** %d | **Completed:** %d | **Overdue:** %d | **Progress:** %d%%\n\n",
+		stats.Total, stats.Completed, stats.Overdue, stats.CompletionPercent())
 
 	// Group tasks by completion status
 	completed := make([]*task.Task, 0)
@@ -403,20 +1429,24 @@ func (tm *TaskManager) exportMarkdown(tasks []*task.Task, filename string) error
 		}
 	}
 
+	if len(tasks) == 0 {
+		fmt.Fprintf(w, "_No tasks._\n\n")
+	}
+
 	// Write pending tasks
-	if len(pending) > 0 {
-		fmt.Fprintf(file, "## Pending Tasks (%d)\n\n", len(pending))
+	if len(pending) > 0 || opts.KeepEmptySections {
+		fmt.Fprintf(w, "## Pending Tasks (%d)\n\n", len(pending))
 		for _, t := range pending {
-			tm.writeMarkdownTask(file, t)
+			tm.writeMarkdownTask(w, t)
 		}
-		fmt.Fprintln(file)
+		fmt.Fprintln(w)
 	}
 
 	// Write completed tasks
-	if len(completed) > 0 {
-		fmt.Fprintf(file, "## Completed Tasks (%d)\n\n", len(completed))
+	if len(completed) > 0 || opts.KeepEmptySections {
+		fmt.Fprintf(w, "## Completed Tasks (%d)\n\n", len(completed))
 		for _, t := range completed {
-			tm.writeMarkdownTask(file, t)
+			tm.writeMarkdownTask(w, t)
 		}
 	}
 
@@ -424,40 +1454,46 @@ func (tm *TaskManager) exportMarkdown(tasks []*task.Task, filename string) error
 }
 
 // writeMarkdownTask writes a single task in Markdown format
-func (tm *TaskManager) writeMarkdownTask(file *os.File, t *task.Task) {
+func (tm *TaskManager) writeMarkdownTask(w io.Writer, t *task.Task) {
+	icons := tm.Icons()
+
 	// Task header
-	status := "❌"
+	status := icons.Incomplete
 	if t.Completed {
-		status = "✅"
-	}
-
-	priorityEmoji := ""
-	switch t.Priority {
-	case task.High:
-		priorityEmoji = "🔴"
-	case task.Medium:
-		priorityEmoji = "🟡"
-	case task.Low:
-		priorityEmoji = "🟢"
+		status = icons.Done
 	}
 
-	fmt.Fprintf(file, "### %s %s %s\n\n", status, priorityEmoji, t.Title)
+	fmt.Fprintf(w, "### %s %s %s\n\n", status, icons.PriorityIcon(t.Priority), t.Title)
 
 	// Description
 	if t.Description != "" {
-		fmt.Fprintf(file, "**Description:** %s\n\n", t.Description)
+		fmt.Fprintf(w, "**Description:** %s\n\n", t.Description)
 	}
 
+	fmt.Fprintf(w, "**Priority:** %s\n\n", tm.config.PriorityLabel(t.Priority))
+
 	// Due date
 	if !t.DueDate.IsZero() {
-		fmt.Fprintf(file, "**Due:** %s\n\n", t.DueDate.Format("2006-01-02 15:04"))
+		fmt.Fprintf(w, "**Due:** %s\n\n", t.DueDate.Format("2006-01-02 15:04"))
+	}
+
+	if len(t.Tags) > 0 {
+		fmt.Fprintf(w, "**Tags:** %s\n\n", strings.Join(t.Tags, ", "))
+	}
+
+	if len(t.Notes) > 0 {
+		fmt.Fprintf(w, "**Notes:**\n\n")
+		for _, n := range t.NotesNewestFirst() {
+			fmt.Fprintf(w, "- %s: %s\n", n.CreatedAt.Format("2006-01-02 15:04"), n.Text)
+		}
+		fmt.Fprintln(w)
 	}
 
 	// Metadata
-	fmt.Fprintf(file, "**ID:** `%s`  \n", t.ID)
-	fmt.Fprintf(file, "**Created:** %s  \n", t.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Fprintf(w, "**ID:** `%s`  \n", t.ID)
+	fmt.Fprintf(w, "**Created:** %s  \n", t.CreatedAt.Format("2006-01-02 15:04"))
 	if t.UpdatedAt.After(t.CreatedAt) {
-		fmt.Fprintf(file, "**Updated:** %s  \n", t.UpdatedAt.Format("2006-01-02 15:04"))
+		fmt.Fprintf(w, "**Updated:** %s  \n", t.UpdatedAt.Format("2006-01-02 15:04"))
 	}
-	fmt.Fprintln(file)
+	fmt.Fprintln(w)
 }
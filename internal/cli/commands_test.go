@@ -1,14 +1,48 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"go-fun/internal/config"
+	"go-fun/internal/filter"
 	"go-fun/internal/storage"
 	"go-fun/internal/task"
 )
 
+func TestTaskManagerAddStrictTitles(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	ctx := context.Background()
+
+	if err := tm.Add(ctx, "Loose title  ", "", task.Medium, time.Time{}, nil); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	tm.SetStrictTitles(true)
+	if err := tm.Add(ctx, "Line\nbreak", "", task.Medium, time.Time{}, nil); err == nil {
+		t.Error("Expected strict titles to reject a newline")
+	}
+
+	tasks, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "Loose title" {
+		t.Errorf("Expected only the trimmed task to be stored, got %+v", tasks)
+	}
+}
+
 func TestTaskManagerAdd(t *testing.T) {
 	storage := storage.NewInMemoryStorage()
 	tm := NewTaskManager(storage)
@@ -19,7 +53,7 @@ func TestTaskManagerAdd(t *testing.T) {
 	priority := task.High
 	dueDate := time.Now().Add(24 * time.Hour)
 
-	err := tm.Add(ctx, title, description, priority, dueDate)
+	err := tm.Add(ctx, title, description, priority, dueDate, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error adding task: %v", err)
 	}
@@ -85,6 +119,52 @@ func TestTaskManagerComplete(t *testing.T) {
 	}
 }
 
+func TestTaskManagerCompleteRecurring(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	due := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+	recurring := task.NewTask("Pay rent", "Every month", task.High, due, nil)
+	recurring.Recurrence = &task.Recurrence{Interval: task.Monthly}
+	if err := tm.AddTask(ctx, recurring); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	if err := tm.Complete(ctx, recurring.ID); err != nil {
+		t.Fatalf("Unexpected error completing task: %v", err)
+	}
+
+	tasks, _ := storage.Load(ctx)
+	if len(tasks) != 2 {
+		t.Fatalf("Expected the next occurrence to be added, got %d tasks", len(tasks))
+	}
+
+	var next *task.Task
+	for _, tt := range tasks {
+		if tt.ID != recurring.ID {
+			next = tt
+		} else if !tt.Completed {
+			t.Error("Expected the original occurrence to be completed")
+		}
+	}
+	if next.Completed || next.Title != "Pay rent" {
+		t.Errorf("Unexpected next occurrence: %+v", next)
+	}
+	if want := time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC); !next.DueDate.Equal(want) {
+		t.Errorf("Expected next due %v, got %v", want, next.DueDate)
+	}
+
+	// Completing an already completed task does not spawn again
+	if err := tm.Complete(ctx, recurring.ID); err != nil {
+		t.Fatalf("Unexpected error completing task again: %v", err)
+	}
+	if tasks, _ := storage.Load(ctx); len(tasks) != 2 {
+		t.Errorf("Expected no further occurrences, got %d tasks", len(tasks))
+	}
+}
+
 func TestTaskManagerUncomplete(t *testing.T) {
 	storage := storage.NewInMemoryStorage()
 	tm := NewTaskManager(storage)
@@ -121,6 +201,18 @@ func TestTaskManagerUncomplete(t *testing.T) {
 	if retrievedTask.Completed {
 		t.Error("Expected task to be uncompleted")
 	}
+
+	// A unique ID prefix is enough, as for complete
+	retrievedTask.Complete()
+	if err := storage.Update(ctx, testTask.ID, retrievedTask); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+	if err := tm.Uncomplete(ctx, "test"); err != nil {
+		t.Fatalf("Unexpected error uncompleting by prefix: %v", err)
+	}
+	if retrievedTask, _ = storage.GetByID(ctx, testTask.ID); retrievedTask.Completed {
+		t.Error("Expected task to be uncompleted by prefix")
+	}
 }
 
 func TestTaskManagerDelete(t *testing.T) {
@@ -188,7 +280,7 @@ func TestTaskManagerUpdate(t *testing.T) {
 	newPriority := task.High
 	newDueDate := time.Now().Add(48 * time.Hour)
 
-	err = tm.Update(ctx, testTask.ID, newTitle, newDescription, newPriority, newDueDate)
+	err = tm.Update(ctx, testTask.ID, newTitle, newDescription, newPriority, newDueDate, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error updating task: %v", err)
 	}
@@ -210,6 +302,15 @@ func TestTaskManagerUpdate(t *testing.T) {
 	if retrievedTask.Priority != newPriority {
 		t.Errorf("Expected priority %v, got %v", newPriority, retrievedTask.Priority)
 	}
+
+	// Duplicate and mixed-case tags collapse on update
+	if err := tm.Update(ctx, testTask.ID, newTitle, newDescription, newPriority, newDueDate, []string{"Work", "urgent", "work "}); err != nil {
+		t.Fatalf("Unexpected error updating tags: %v", err)
+	}
+	retrievedTask, _ = storage.GetByID(ctx, testTask.ID)
+	if !reflect.DeepEqual(retrievedTask.Tags, []string{"urgent", "work"}) {
+		t.Errorf("Expected tags [urgent work], got %v", retrievedTask.Tags)
+	}
 }
 
 func TestTaskManagerShow(t *testing.T) {
@@ -240,6 +341,84 @@ func TestTaskManagerShow(t *testing.T) {
 	}
 }
 
+func TestTaskManagerUpdateFieldsKeepsOmitted(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	ctx := context.Background()
+
+	due := time.Date(2025, 6, 1, 17, 0, 0, 0, time.UTC)
+	if err := store.Add(ctx, &task.Task{
+		ID: "report", Title: "Report", Description: "Numbers and charts", Priority: task.High,
+		DueDate: due, Tags: []string{"work"}, CreatedAt: due, UpdatedAt: due,
+	}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	title := "Quarterly report"
+	if err := tm.UpdateFields(ctx, "rep", TaskUpdate{Title: &title}); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+	got, _ := store.GetByID(ctx, "report")
+	if got.Title != title || got.Description != "Numbers and charts" || got.Priority != task.High ||
+		!got.DueDate.Equal(due) || !reflect.DeepEqual(got.Tags, []string{"work"}) {
+		t.Errorf("Expected only the title to change, got %+v", got)
+	}
+
+	// The zero time clears the due date, and an empty description is kept as given
+	empty, cleared, low := "", time.Time{}, task.Low
+	if err := tm.UpdateFields(ctx, "report", TaskUpdate{Description: &empty, DueDate: &cleared, Priority: &low}); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+	got, _ = store.GetByID(ctx, "report")
+	if got.Title != title || got.Description != "" || got.Priority != task.Low || !got.DueDate.IsZero() {
+		t.Errorf("Expected description, due date and priority to change, got %+v", got)
+	}
+}
+
+func TestTaskManagerUpdateFieldsStartDate(t *testing.T) {
+	tm, store := newUndoManager(t)
+	ctx := context.Background()
+
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	due := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	if err := store.Add(ctx, &task.Task{
+		ID: "taxes", Title: "File taxes", StartDate: start, DueDate: due, CreatedAt: start, UpdatedAt: start,
+	}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	// Moving the due date before the old start is fine when the start
+	// moves with it
+	newStart, newDue := time.Date(2029, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2029, 6, 5, 0, 0, 0, 0, time.UTC)
+	if err := tm.UpdateFields(ctx, "taxes", TaskUpdate{StartDate: &newStart, DueDate: &newDue}); err != nil {
+		t.Fatalf("Unexpected error updating dates together: %v", err)
+	}
+	got, _ := store.GetByID(ctx, "taxes")
+	if !got.StartDate.Equal(newStart) || !got.DueDate.Equal(newDue) {
+		t.Errorf("Expected both dates to change, got start %v due %v", got.StartDate, got.DueDate)
+	}
+
+	// A start after the due date changes nothing
+	late := time.Date(2029, 7, 1, 0, 0, 0, 0, time.UTC)
+	title := "Renamed"
+	if err := tm.UpdateFields(ctx, "taxes", TaskUpdate{Title: &title, StartDate: &late}); err == nil {
+		t.Fatal("Expected a start date after the due date to be rejected")
+	}
+	got, _ = store.GetByID(ctx, "taxes")
+	if got.Title != "File taxes" || !got.StartDate.Equal(newStart) {
+		t.Errorf("Expected a rejected update to leave the task alone, got %+v", got)
+	}
+
+	// One undo reverts both dates
+	if _, err := tm.Undo(ctx); err != nil {
+		t.Fatalf("Unexpected error undoing: %v", err)
+	}
+	got, _ = store.GetByID(ctx, "taxes")
+	if !got.StartDate.Equal(start) || !got.DueDate.Equal(due) {
+		t.Errorf("Expected undo to restore both dates, got start %v due %v", got.StartDate, got.DueDate)
+	}
+}
+
 func TestTaskManagerStats(t *testing.T) {
 	storage := storage.NewInMemoryStorage()
 	tm := NewTaskManager(storage)
@@ -290,6 +469,130 @@ func TestTaskManagerStats(t *testing.T) {
 	}
 }
 
+func TestTaskManagerStatsDueSoonWindow(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	if err := tm.Add(ctx, "Far off", "", task.Medium, time.Now().Add(10*24*time.Hour), nil); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	stats, err := tm.ComputeStats(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error computing stats: %v", err)
+	}
+	if stats.DueSoon != 0 {
+		t.Errorf("Expected no tasks due soon with the default window, got %d", stats.DueSoon)
+	}
+
+	tm.SetDueSoonWindow(14 * 24 * time.Hour)
+	if err := tm.Stats(ctx); err != nil {
+		t.Fatalf("Unexpected error getting stats: %v", err)
+	}
+	if !strings.Contains(out.String(), "Due soon (14 days): 1") {
+		t.Errorf("Expected the 14 day window in the output, got:\n%s", out.String())
+	}
+}
+
+func TestTaskManagerStatsJSON(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "overdue", Title: "Overdue", Priority: task.Urgent, DueDate: now.Add(-72 * time.Hour)},
+		{ID: "soon", Title: "Soon", Priority: task.High, DueDate: now.Add(72 * time.Hour)},
+		{ID: "later", Title: "Later", Priority: task.High, DueDate: now.Add(30 * 24 * time.Hour)},
+		{ID: "late", Title: "Late", Priority: task.Low, Completed: true, WasLate: true},
+		{ID: "done", Title: "Done", Priority: task.Medium, Completed: true},
+	} {
+		tt.CreatedAt, tt.UpdatedAt = now, now
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	if err := tm.SetOutputFormat(OutputJSON); err != nil {
+		t.Fatalf("Unexpected error setting output format: %v", err)
+	}
+	if err := tm.Stats(ctx); err != nil {
+		t.Fatalf("Unexpected error getting stats: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Expected JSON output, got %v:\n%s", err, out.String())
+	}
+	want := map[string]any{
+		"total":          5.0,
+		"completed":      2.0,
+		"completed_late": 1.0,
+		"overdue":        1.0,
+		"due_today":      0.0,
+		"due_soon":       1.0,
+		"by_priority":    map[string]any{"urgent": 1.0, "high": 2.0, "medium": 1.0, "low": 1.0},
+		"pending_by_tag": map[string]any{},
+		// Only the urgent task is overdue
+		"overdue_by_priority": map[string]any{"urgent": 1.0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected stats %v, got %v", want, got)
+	}
+}
+
+func TestTaskManagerStatsBreakdown(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	now := time.Now()
+	past := now.Add(-48 * time.Hour)
+	for _, tt := range []*task.Task{
+		{ID: "a", Title: "Invoice", Priority: task.High, Tags: []string{"work", "finance"}, DueDate: past},
+		{ID: "b", Title: "Slides", Priority: task.High, Tags: []string{"work"}, DueDate: past},
+		{ID: "c", Title: "Standup", Priority: task.Low, Tags: []string{"work"}},
+		{ID: "d", Title: "Groceries", Priority: task.Urgent, Tags: []string{"home"}, DueDate: past},
+		{ID: "e", Title: "Old report", Priority: task.High, Tags: []string{"work"}, DueDate: past, Completed: true},
+		{ID: "f", Title: "Untagged", Priority: task.Medium},
+	} {
+		tt.CreatedAt, tt.UpdatedAt = now, now
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	stats, err := tm.ComputeStats(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error computing stats: %v", err)
+	}
+	if want := map[string]int{"work": 3, "finance": 1, "home": 1}; !reflect.DeepEqual(stats.PendingByTag, want) {
+		t.Errorf("Expected pending by tag %v, got %v", want, stats.PendingByTag)
+	}
+	if want := map[task.Priority]int{task.High: 2, task.Urgent: 1}; !reflect.DeepEqual(stats.OverdueByPriority, want) {
+		t.Errorf("Expected overdue by priority %v, got %v", want, stats.OverdueByPriority)
+	}
+	if got, want := stats.TagsByPendingCount(), []string{"work", "finance", "home"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected tags ordered %v, got %v", want, got)
+	}
+
+	if err := tm.Stats(ctx); err != nil {
+		t.Fatalf("Unexpected error getting stats: %v", err)
+	}
+	for _, want := range []string{"Overdue by Priority:\n  Urgent: 1\n  High: 2\n", "Pending by Tag:\n  work: 3\n  finance: 1\n  home: 1\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, out.String())
+		}
+	}
+}
+
 func TestTaskManagerErrorHandling(t *testing.T) {
 	storage := storage.NewInMemoryStorage()
 	tm := NewTaskManager(storage)
@@ -314,7 +617,7 @@ func TestTaskManagerErrorHandling(t *testing.T) {
 	}
 
 	// Test updating non-existent task
-	err = tm.Update(ctx, "non-existent", "Title", "Description", task.Medium, time.Now())
+	err = tm.Update(ctx, "non-existent", "Title", "Description", task.Medium, time.Now(), nil)
 	if err == nil {
 		t.Error("Expected error when updating non-existent task")
 	}
@@ -326,6 +629,1330 @@ func TestTaskManagerErrorHandling(t *testing.T) {
 	}
 }
 
+func TestTaskManagerPriorityLabels(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	ctx := context.Background()
+
+	cfg := config.Default()
+	cfg.SetPriorityLabel(task.High, "P1")
+	cfg.SetPriorityLabel(task.Low, "P3")
+	tm.SetConfig(cfg)
+
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+
+	testTask := &task.Task{
+		ID:          "test-1",
+		Title:       "Labelled Task",
+		Description: "Test Description",
+		Priority:    task.High,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := storage.Add(ctx, testTask); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	if err := tm.List(ctx, ListOptions{}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+	if !strings.Contains(out.String(), "Priority: P1") {
+		t.Errorf("Expected list output to contain custom label, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := tm.Stats(ctx); err != nil {
+		t.Fatalf("Unexpected error getting stats: %v", err)
+	}
+	if !strings.Contains(out.String(), "P1: 1") || !strings.Contains(out.String(), "P3: 0") {
+		t.Errorf("Expected stats output to use custom labels, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Medium: 0") {
+		t.Errorf("Expected unlabelled priority to keep default name, got:\n%s", out.String())
+	}
+
+	tempDir, err := os.MkdirTemp("", "go-fun-labels-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	csvPath := filepath.Join(tempDir, "tasks.csv")
+	if err := tm.ExportTasks(ctx, "csv", csvPath, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting CSV: %v", err)
+	}
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading CSV: %v", err)
+	}
+	if !strings.Contains(string(data), ",P1,") {
+		t.Errorf("Expected CSV export to use custom label, got:\n%s", data)
+	}
+
+	// The stored enum is unchanged and the label parses back to it
+	parsed, err := cfg.ParsePriority("p1")
+	if err != nil || parsed != task.High {
+		t.Errorf("Expected label to parse back to High, got %v (err %v)", parsed, err)
+	}
+}
+
+func TestExportMarkdownSummary(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	ctx := context.Background()
+
+	now := time.Now()
+	tasks := []*task.Task{
+		{ID: "test-1", Title: "Done", Priority: task.High, Completed: true, CreatedAt: now, UpdatedAt: now},
+		{ID: "test-2", Title: "Late", Priority: task.Medium, DueDate: now.Add(-48 * time.Hour), CreatedAt: now, UpdatedAt: now},
+		{ID: "test-3", Title: "Open", Priority: task.Low, CreatedAt: now, UpdatedAt: now},
+	}
+	for _, tt := range tasks {
+		if err := storage.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "go-fun-markdown-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	mdPath := filepath.Join(tempDir, "tasks.md")
+	if err := tm.ExportTasks(ctx, "markdown", mdPath, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting markdown: %v", err)
+	}
+	data, err := os.ReadFile(mdPath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading markdown: %v", err)
+	}
+	content := string(data)
+
+	summary := "**Total:** 3 | **Completed:** 1 | **Overdue:** 1 | **Progress:** 33%"
+	summaryIdx := strings.Index(content, summary)
+	if summaryIdx == -1 {
+		t.Fatalf("Expected summary line %q, got:\n%s", summary, content)
+	}
+	if pendingIdx := strings.Index(content, "## Pending Tasks"); pendingIdx < summaryIdx {
+		t.Errorf("Expected summary to precede pending section")
+	}
+	if completedIdx := strings.Index(content, "## Completed Tasks"); completedIdx < summaryIdx {
+		t.Errorf("Expected summary to precede completed section")
+	}
+}
+
+func TestExportMarkdownEmptySections(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-markdown-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	export := func(t *testing.T, tasks []*task.Task, opts ExportOptions) string {
+		t.Helper()
+		store := storage.NewInMemoryStorage()
+		for _, tt := range tasks {
+			if err := store.Add(ctx, tt); err != nil {
+				t.Fatalf("Unexpected error adding task: %v", err)
+			}
+		}
+		mdPath := filepath.Join(tempDir, "tasks.md")
+		if err := NewTaskManager(store).ExportTasks(ctx, "markdown", mdPath, opts); err != nil {
+			t.Fatalf("Unexpected error exporting markdown: %v", err)
+		}
+		data, err := os.ReadFile(mdPath)
+		if err != nil {
+			t.Fatalf("Unexpected error reading markdown: %v", err)
+		}
+		return string(data)
+	}
+
+	allCompleted := []*task.Task{
+		{ID: "test-1", Title: "Done", Priority: task.High, Completed: true, CreatedAt: now, UpdatedAt: now},
+		{ID: "test-2", Title: "Also done", Priority: task.Low, Completed: true, CreatedAt: now, UpdatedAt: now},
+	}
+
+	content := export(t, allCompleted, ExportOptions{})
+	if strings.Contains(content, "## Pending Tasks") {
+		t.Errorf("Expected no pending section, got:\n%s", content)
+	}
+	if !strings.Contains(content, "## Completed Tasks (2)") {
+		t.Errorf("Expected completed section, got:\n%s", content)
+	}
+
+	content = export(t, allCompleted, ExportOptions{KeepEmptySections: true})
+	if !strings.Contains(content, "## Pending Tasks (0)") {
+		t.Errorf("Expected empty pending section when kept, got:\n%s", content)
+	}
+
+	content = export(t, nil, ExportOptions{})
+	if !strings.Contains(content, "No tasks") {
+		t.Errorf("Expected a no-tasks note, got:\n%s", content)
+	}
+	if strings.Contains(content, "## Pending Tasks") || strings.Contains(content, "## Completed Tasks") {
+		t.Errorf("Expected no task sections in an empty export, got:\n%s", content)
+	}
+}
+
+func TestExportTags(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-export-tags-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	now := time.Now()
+	store := storage.NewInMemoryStorage()
+	for _, tt := range []*task.Task{
+		{ID: "tagged", Title: "Tagged", Priority: task.High, Tags: []string{"home", "urgent"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "plain", Title: "Plain", Priority: task.Low, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+	tm := NewTaskManager(store)
+
+	export := func(format string) string {
+		t.Helper()
+		path := filepath.Join(tempDir, "tasks."+format)
+		if err := tm.ExportTasks(ctx, format, path, ExportOptions{}); err != nil {
+			t.Fatalf("Unexpected error exporting %s: %v", format, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Unexpected error reading %s: %v", format, err)
+		}
+		return string(data)
+	}
+
+	lines := strings.Split(strings.TrimSpace(export("csv")), "\n")
+	if !strings.HasSuffix(lines[0], ",Tags") {
+		t.Errorf("Expected a Tags column in the CSV header, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "tagged,") || !strings.HasSuffix(lines[1], ",home;urgent") {
+		t.Errorf("Expected tags joined by semicolons, got %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "plain,") || !strings.HasSuffix(lines[2], ",") {
+		t.Errorf("Expected an empty tags cell, got %q", lines[2])
+	}
+
+	md := export("md")
+	if !strings.Contains(md, "**Tags:** home, urgent") {
+		t.Errorf("Expected a tags line in markdown, got:\n%s", md)
+	}
+	if strings.Count(md, "**Tags:**") != 1 || strings.Contains(md, "[]") {
+		t.Errorf("Expected untagged tasks to omit the tags line, got:\n%s", md)
+	}
+
+	var exported []*task.Task
+	if err := json.Unmarshal([]byte(export("json")), &exported); err != nil {
+		t.Fatalf("Unexpected error parsing JSON export: %v", err)
+	}
+	if len(exported) != 2 || !reflect.DeepEqual(exported[0].Tags, []string{"home", "urgent"}) || exported[1].Tags != nil {
+		t.Errorf("Expected JSON export to carry tags, got %+v", exported)
+	}
+}
+
+func TestExportCSVPriorityNumeric(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-priority-numeric-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	now := time.Now()
+	store := storage.NewInMemoryStorage()
+	for _, tt := range []*task.Task{
+		{ID: "high", Title: "High", Priority: task.High, CreatedAt: now, UpdatedAt: now},
+		{ID: "medium", Title: "Medium", Priority: task.Medium, CreatedAt: now, UpdatedAt: now},
+		{ID: "low", Title: "Low", Priority: task.Low, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+	tm := NewTaskManager(store)
+
+	priorities := func(opts ExportOptions) map[string]string {
+		t.Helper()
+		path := filepath.Join(tempDir, "tasks.csv")
+		if err := tm.ExportTasks(ctx, "csv", path, opts); err != nil {
+			t.Fatalf("Unexpected error exporting CSV: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Unexpected error reading CSV: %v", err)
+		}
+		got := make(map[string]string)
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n")[1:] {
+			fields := strings.Split(line, ",")
+			got[fields[0]] = fields[3]
+		}
+		return got
+	}
+
+	expected := map[string]string{"high": "3", "medium": "2", "low": "1"}
+	if got := priorities(ExportOptions{PriorityNumeric: true}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected numeric priorities %v, got %v", expected, got)
+	}
+
+	expected = map[string]string{"high": "High", "medium": "Medium", "low": "Low"}
+	if got := priorities(ExportOptions{}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected priority names by default %v, got %v", expected, got)
+	}
+
+	// Numeric exports import back to the same levels
+	if err := tm.ExportTasks(ctx, "csv", filepath.Join(tempDir, "numeric.csv"), ExportOptions{PriorityNumeric: true}); err != nil {
+		t.Fatalf("Unexpected error exporting CSV: %v", err)
+	}
+	importer := NewTaskManager(storage.NewInMemoryStorage())
+	importer.SetOutput(&bytes.Buffer{})
+	if _, err := importer.ImportTasks(ctx, "csv", filepath.Join(tempDir, "numeric.csv"), MergeSkip); err != nil {
+		t.Fatalf("Unexpected error importing CSV: %v", err)
+	}
+	if got, err := importer.storage.GetByID(ctx, "high"); err != nil || got.Priority != task.High {
+		t.Errorf("Expected numeric 3 to import as High, got %v (err %v)", got, err)
+	}
+}
+
+func TestExportCSVQuoting(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-csv-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Minute)
+	tricky := &task.Task{
+		ID:          "tricky",
+		Title:       "Call \"Ann\", then\nBob",
+		Description: "Agenda: budget, hiring; \"misc\"\nsecond line",
+		Priority:    task.High,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Tags:        []string{"a,b", "work"},
+	}
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	path := filepath.Join(tempDir, "tasks.csv")
+	if err := tm.exportFormat([]*task.Task{tricky}, "csv", path, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting CSV: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening CSV: %v", err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected a header and one row, got %d records", len(records))
+	}
+	row := records[1]
+	if row[1] != tricky.Title || row[2] != tricky.Description || row[8] != "a,b;work" {
+		t.Errorf("Expected fields to survive unchanged, got %q", row)
+	}
+
+	// And the importer reads them back; the title's line break is normalized
+	importer := NewTaskManager(storage.NewInMemoryStorage())
+	importer.SetOutput(&bytes.Buffer{})
+	result, err := importer.ImportTasks(ctx, "csv", path, MergeSkip)
+	if err != nil {
+		t.Fatalf("Unexpected error importing CSV: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("Expected no failed rows, got %d", result.Failed)
+	}
+	got, err := importer.storage.GetByID(ctx, "tricky")
+	if err != nil {
+		t.Fatalf("Unexpected error getting imported task: %v", err)
+	}
+	if got.Title != "Call \"Ann\", then Bob" || got.Description != tricky.Description {
+		t.Errorf("Expected title and description to round-trip, got %q / %q", got.Title, got.Description)
+	}
+}
+
+func TestConcurrentExportOutputDir(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-export-all-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	store := storage.NewInMemoryStorage()
+	if err := store.Add(ctx, task.NewTask("Export me", "Desc", task.Medium, time.Time{}, nil)); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+
+	outDir := filepath.Join(tempDir, "nested", "exports")
+	if err := tm.ConcurrentExport(ctx, []string{"json", "csv"}, outDir, "backup", 0); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+	for _, name := range []string{"backup.json", "backup.csv"} {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+			t.Errorf("Expected %s in the output directory: %v", name, err)
+		}
+	}
+
+	for _, base := range []string{"../etc", "/tmp/backup", "..", "sub/backup"} {
+		if err := tm.ConcurrentExport(ctx, []string{"json"}, outDir, base, 0); err == nil {
+			t.Errorf("Expected base %q to be rejected", base)
+		}
+	}
+	if err := tm.ConcurrentExport(ctx, []string{"../json"}, outDir, "backup", 0); err == nil {
+		t.Error("Expected a format containing a path to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "nested", "etc.json")); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written outside the output directory")
+	}
+}
+
+// cancelWriter cancels a context on the first write
+type cancelWriter struct {
+	cancel context.CancelFunc
+}
+
+func (w cancelWriter) Write(p []byte) (int, error) {
+	w.cancel()
+	return len(p), nil
+}
+
+func TestConcurrentExportCancel(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-export-cancel-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	store := storage.NewInMemoryStorage()
+	if err := store.Add(context.Background(), task.NewTask("Export me", "Desc", task.Medium, time.Time{}, nil)); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	// With one worker the formats run in order, and the success message for
+	// the first cancels the export before the rest start
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tm := NewTaskManager(store)
+	tm.SetOutput(cancelWriter{cancel: cancel})
+
+	err = tm.ConcurrentExport(ctx, []string{"json", "csv", "markdown"}, tempDir, "backup", 1)
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Fatalf("Expected a cancellation error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "backup.json")); err != nil {
+		t.Errorf("Expected the first export to finish: %v", err)
+	}
+	for _, name := range []string{"backup.csv", "backup.markdown"} {
+		if _, err := os.Stat(filepath.Join(tempDir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s not to be written after cancellation", name)
+		}
+	}
+}
+
+func TestTaskManagerListOnlyIDs(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	ctx := context.Background()
+
+	now := time.Now()
+	tasks := []*task.Task{
+		{ID: "high-1", Title: "First high", Priority: task.High, DueDate: now.Add(time.Hour), CreatedAt: now, UpdatedAt: now},
+		{ID: "medium-1", Title: "Medium", Priority: task.Medium, CreatedAt: now, UpdatedAt: now},
+		{ID: "high-2", Title: "Second high", Priority: task.High, CreatedAt: now, UpdatedAt: now},
+		{ID: "low-1", Title: "Low", Priority: task.Low, CreatedAt: now, UpdatedAt: now},
+	}
+	for _, tt := range tasks {
+		if err := storage.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+
+	high := filter.PriorityFilter{Level: task.High}
+	if err := tm.List(ctx, ListOptions{Priority: &high, OnlyIDs: true}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+
+	expected := "high-1\nhigh-2\n"
+	if out.String() != expected {
+		t.Errorf("Expected output %q, got %q", expected, out.String())
+	}
+
+	// No matches prints nothing rather than a decorated message
+	out.Reset()
+	if err := tm.List(ctx, ListOptions{Search: "nothing matches", OnlyIDs: true}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no output, got %q", out.String())
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tasks := make([]*task.Task, 5)
+	for i := range tasks {
+		tasks[i] = &task.Task{ID: string(rune('a' + i))}
+	}
+	ids := func(tasks []*task.Task) string {
+		out := ""
+		for _, t := range tasks {
+			out += t.ID
+		}
+		return out
+	}
+
+	tests := []struct {
+		offset, limit int
+		want          string
+	}{
+		{0, 0, "abcde"},
+		{0, -1, "abcde"},
+		{0, 2, "ab"},
+		{2, 2, "cd"},
+		{4, 2, "e"},
+		{5, 2, ""},
+		{9, 0, ""},
+		{0, 10, "abcde"},
+		{3, 0, "de"},
+	}
+	for _, tt := range tests {
+		if got := ids(paginate(tasks, tt.offset, tt.limit)); got != tt.want {
+			t.Errorf("paginate(offset=%d, limit=%d) = %q, expected %q", tt.offset, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestTaskManagerListLimitOffset(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	ctx := context.Background()
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		tt := &task.Task{ID: fmt.Sprintf("t%d", i), Title: fmt.Sprintf("Task %d", i), Priority: task.Priority(i % 4), CreatedAt: now.Add(time.Duration(i) * time.Minute), UpdatedAt: now}
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+
+	// Applied after sorting: oldest first, second page of two
+	if err := tm.List(ctx, ListOptions{Sort: "created", Limit: 2, Offset: 2, OnlyIDs: true}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+	if expected := "t2\nt3\n"; out.String() != expected {
+		t.Errorf("Expected output %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	if err := tm.List(ctx, ListOptions{Sort: "created", Limit: 2, Offset: 4}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+	if !strings.Contains(out.String(), "Showing 5-5 of 5") || !strings.Contains(out.String(), "Task 4") {
+		t.Errorf("Expected the last page footer, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := tm.List(ctx, ListOptions{Offset: 10}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+	if !strings.Contains(out.String(), "Showing none of 5") || strings.Contains(out.String(), "Task 0") {
+		t.Errorf("Expected an empty page past the end, got:\n%s", out.String())
+	}
+
+	// No paging flags, no footer
+	out.Reset()
+	if err := tm.List(ctx, ListOptions{Limit: -1}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+	if strings.Contains(out.String(), "Showing") || strings.Count(out.String(), "ID: t") != 5 {
+		t.Errorf("Expected every task and no footer, got:\n%s", out.String())
+	}
+}
+
+func TestTaskManagerListExplain(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "match", Title: "Write report", Priority: task.High, DueDate: now.Add(48 * time.Hour), Tags: []string{"work"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "low", Title: "Write poem", Priority: task.Low, DueDate: now.Add(48 * time.Hour), CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := storage.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	p, err := filter.NewPriorityFilter(">=high")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing priority: %v", err)
+	}
+	opts := ListOptions{Priority: &p, Due: "week", Search: "write", Tag: "work", Explain: true}
+	if err := tm.List(ctx, opts); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+
+	want := "Matched: [priority:>=high search:write tag:work due:next7d]"
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %q in output, got:\n%s", want, out.String())
+	}
+	if strings.Contains(out.String(), "Write poem") {
+		t.Error("Expected the low priority task to be filtered out")
+	}
+
+	// Without filters every task is shown and says so
+	out.Reset()
+	if err := tm.List(ctx, ListOptions{Explain: true}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+	if strings.Count(out.String(), "Matched: [no filters]") != 2 {
+		t.Errorf("Expected both tasks to report no filters, got:\n%s", out.String())
+	}
+}
+
+func TestFilterTasksWeekday(t *testing.T) {
+	now := time.Now()
+	friday := time.Date(2025, 6, 13, 17, 0, 0, 0, time.UTC)
+	tasks := []*task.Task{
+		{ID: "fri-high", Title: "Ship release", Priority: task.High, DueDate: friday, CreatedAt: now, UpdatedAt: now},
+		{ID: "fri-low", Title: "Tidy desk", Priority: task.Low, DueDate: friday.AddDate(0, 0, 7), CreatedAt: now, UpdatedAt: now},
+		{ID: "thu", Title: "Standup notes", Priority: task.High, DueDate: friday.AddDate(0, 0, -1), CreatedAt: now, UpdatedAt: now},
+		{ID: "undated", Title: "Someday", Priority: task.High, CreatedAt: now, UpdatedAt: now},
+	}
+
+	ids := func(tasks []*task.Task) []string {
+		out := make([]string, 0, len(tasks))
+		for _, t := range tasks {
+			out = append(out, t.ID)
+		}
+		return out
+	}
+
+	got, err := filterTasks(tasks, ListOptions{Weekday: "friday"})
+	if err != nil {
+		t.Fatalf("Unexpected error filtering tasks: %v", err)
+	}
+	if want := []string{"fri-high", "fri-low"}; !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("Expected %v, got %v", want, ids(got))
+	}
+
+	// Combines with other filters
+	high := filter.PriorityFilter{Level: task.High}
+	got, err = filterTasks(tasks, ListOptions{Weekday: "Fri", Priority: &high})
+	if err != nil {
+		t.Fatalf("Unexpected error filtering tasks: %v", err)
+	}
+	if want := []string{"fri-high"}; !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("Expected %v, got %v", want, ids(got))
+	}
+
+	if _, err := filterTasks(tasks, ListOptions{Weekday: "fr"}); err == nil {
+		t.Error("Expected error for ambiguous weekday")
+	}
+}
+
+func TestFilterTasksScheduled(t *testing.T) {
+	now := time.Now()
+	tasks := []*task.Task{
+		{ID: "now", Title: "Actionable", CreatedAt: now, UpdatedAt: now},
+		{ID: "started", Title: "Started", StartDate: now.Add(-time.Hour), CreatedAt: now, UpdatedAt: now},
+		{ID: "later", Title: "Not yet", StartDate: now.Add(48 * time.Hour), CreatedAt: now, UpdatedAt: now},
+	}
+
+	ids := func(tasks []*task.Task) []string {
+		out := make([]string, 0, len(tasks))
+		for _, t := range tasks {
+			out = append(out, t.ID)
+		}
+		return out
+	}
+
+	got, err := filterTasks(tasks, ListOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error filtering tasks: %v", err)
+	}
+	if want := []string{"now", "started"}; !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("Expected %v, got %v", want, ids(got))
+	}
+
+	got, err = filterTasks(tasks, ListOptions{Scheduled: true})
+	if err != nil {
+		t.Fatalf("Unexpected error filtering tasks: %v", err)
+	}
+	if want := []string{"now", "started", "later"}; !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("Expected %v, got %v", want, ids(got))
+	}
+}
+
+func TestFilterTasksPriorityRange(t *testing.T) {
+	now := time.Now()
+	tasks := []*task.Task{
+		{ID: "low", Title: "Low", Priority: task.Low, CreatedAt: now, UpdatedAt: now},
+		{ID: "medium", Title: "Medium", Priority: task.Medium, CreatedAt: now, UpdatedAt: now},
+		{ID: "high", Title: "High", Priority: task.High, CreatedAt: now, UpdatedAt: now},
+	}
+
+	ids := func(tasks []*task.Task) []string {
+		out := make([]string, 0, len(tasks))
+		for _, t := range tasks {
+			out = append(out, t.ID)
+		}
+		return out
+	}
+
+	minMedium, err := filter.ParsePriorityRangeFilter("medium", "", task.ParsePriority)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing range: %v", err)
+	}
+	got, err := filterTasks(tasks, ListOptions{PriorityRange: &minMedium})
+	if err != nil {
+		t.Fatalf("Unexpected error filtering tasks: %v", err)
+	}
+	if want := []string{"medium", "high"}; !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("Expected %v, got %v", want, ids(got))
+	}
+
+	// Combines with an exact -p filter
+	exact := filter.PriorityFilter{Level: task.High}
+	got, err = filterTasks(tasks, ListOptions{PriorityRange: &minMedium, Priority: &exact})
+	if err != nil {
+		t.Fatalf("Unexpected error filtering tasks: %v", err)
+	}
+	if want := []string{"high"}; !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("Expected %v, got %v", want, ids(got))
+	}
+}
+
+func TestFilterTasksSearch(t *testing.T) {
+	now := time.Now()
+	tasks := []*task.Task{
+		{ID: "desc", Title: "Pay invoice", Description: "Ticket BUG-142 from billing", CreatedAt: now, UpdatedAt: now},
+		{ID: "title", Title: "Fix bug in parser", CreatedAt: now, UpdatedAt: now},
+		{ID: "tag", Title: "Water plants", Tags: []string{"home"}, CreatedAt: now, UpdatedAt: now},
+	}
+
+	ids := func(tasks []*task.Task) []string {
+		out := make([]string, 0, len(tasks))
+		for _, t := range tasks {
+			out = append(out, t.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		want []string
+	}{
+		{"substring", ListOptions{Search: "bug"}, []string{"desc", "title"}},
+		{"regex matching description only", ListOptions{Search: `bug-\d+`, Regex: true}, []string{"desc"}},
+		{"regex is not a substring", ListOptions{Search: `bug-\d+`}, []string{}},
+		{"tags ignored by default", ListOptions{Search: "home"}, []string{}},
+		{"tags when asked", ListOptions{Search: "^home$", Regex: true, SearchTags: true}, []string{"tag"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterTasks(tasks, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error filtering tasks: %v", err)
+			}
+			if !reflect.DeepEqual(ids(got), tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, ids(got))
+			}
+		})
+	}
+
+	_, err := filterTasks(tasks, ListOptions{Search: "bug(", Regex: true})
+	if err == nil || !strings.Contains(err.Error(), "invalid search regex") {
+		t.Errorf("Expected invalid regex error, got %v", err)
+	}
+}
+
+func TestTaskManagerPurge(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	now := time.Now()
+	tasks := []*task.Task{
+		{ID: "test-1", Title: "Done", Completed: true, CreatedAt: now, UpdatedAt: now},
+		{ID: "test-2", Title: "Open", CreatedAt: now, UpdatedAt: now},
+	}
+	for _, tt := range tasks {
+		if err := storage.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	purged, err := tm.Purge(ctx, false)
+	if err != nil {
+		t.Fatalf("Unexpected error purging: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 purged task, got %d", purged)
+	}
+
+	remaining, _ := storage.Load(ctx)
+	if len(remaining) != 1 || remaining[0].ID != "test-2" {
+		t.Errorf("Expected only the pending task to remain, got %v", remaining)
+	}
+}
+
+func TestTaskManagerPurgeSuspiciousStore(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-safety-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// A "null" document parses to zero tasks without error
+	filePath := filepath.Join(tempDir, "tasks.json")
+	if err := os.WriteFile(filePath, []byte("null"), 0644); err != nil {
+		t.Fatalf("Failed to write tasks file: %v", err)
+	}
+
+	tm := NewTaskManager(storage.NewJSONFileStorage(filePath))
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	if _, err := tm.Purge(ctx, false); !errors.Is(err, ErrSuspiciousStore) {
+		t.Fatalf("Expected ErrSuspiciousStore, got %v", err)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read tasks file: %v", err)
+	}
+	if string(data) != "null" {
+		t.Errorf("Expected file to be untouched, got %q", data)
+	}
+
+	if _, err := tm.Purge(ctx, true); err != nil {
+		t.Errorf("Expected --force to bypass the guard, got %v", err)
+	}
+}
+
+func TestTaskManagerExportByTag(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	// Every task carries its own tag plus a shared one
+	numTags := 50
+	now := time.Now()
+	for i := 0; i < numTags; i++ {
+		testTask := &task.Task{
+			ID:        fmt.Sprintf("test-%d", i),
+			Title:     fmt.Sprintf("Task %d", i),
+			CreatedAt: now,
+			UpdatedAt: now,
+			Tags:      []string{fmt.Sprintf("tag-%d", i), "shared"},
+		}
+		if err := storage.Add(ctx, testTask); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "go-fun-by-tag-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := tm.ExportByTag(ctx, "json", tempDir, 2); err != nil {
+		t.Fatalf("Unexpected error exporting by tag: %v", err)
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to read export directory: %v", err)
+	}
+	if len(entries) != numTags+1 {
+		t.Errorf("Expected %d files, got %d", numTags+1, len(entries))
+	}
+
+	for _, tc := range []struct {
+		tag   string
+		count int
+	}{
+		{"tag-7", 1},
+		{"shared", numTags},
+	} {
+		data, err := os.ReadFile(filepath.Join(tempDir, tc.tag+".json"))
+		if err != nil {
+			t.Fatalf("Failed to read export for %s: %v", tc.tag, err)
+		}
+		var exported []*task.Task
+		if err := json.Unmarshal(data, &exported); err != nil {
+			t.Fatalf("Failed to parse export for %s: %v", tc.tag, err)
+		}
+		if len(exported) != tc.count {
+			t.Errorf("Expected %d tasks in %s export, got %d", tc.count, tc.tag, len(exported))
+		}
+	}
+}
+
+func TestTaskManagerExportByTagCollisions(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	now := time.Now()
+	for i, tag := range []string{"a/b", "a_b"} {
+		testTask := &task.Task{
+			ID:        fmt.Sprintf("test-%d", i),
+			Title:     fmt.Sprintf("Task %d", i),
+			CreatedAt: now,
+			UpdatedAt: now,
+			Tags:      []string{tag},
+		}
+		if err := storage.Add(ctx, testTask); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	tempDir := t.TempDir()
+	if err := tm.ExportByTag(ctx, "json", tempDir, 2); err != nil {
+		t.Fatalf("Unexpected error exporting by tag: %v", err)
+	}
+
+	// Both tags map to a_b, so the second sorted one gets a suffix
+	for name, id := range map[string]string{"a_b.json": "test-0", "a_b-2.json": "test-1"} {
+		data, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read export %s: %v", name, err)
+		}
+		var exported []*task.Task
+		if err := json.Unmarshal(data, &exported); err != nil {
+			t.Fatalf("Failed to parse export %s: %v", name, err)
+		}
+		if len(exported) != 1 || exported[0].ID != id {
+			t.Errorf("Expected %s to hold only %s, got %+v", name, id, exported)
+		}
+	}
+
+	// A cancelled export writes nothing
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	cancelledDir := t.TempDir()
+	err := tm.ExportByTag(cancelled, "json", cancelledDir, 1)
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("Expected a cancellation error, got %v", err)
+	}
+	if entries, _ := os.ReadDir(cancelledDir); len(entries) != 0 {
+		t.Errorf("Expected no files after cancellation, got %d", len(entries))
+	}
+}
+
+func TestTaskManagerLink(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "test-1", Title: "Write report", CreatedAt: now, UpdatedAt: now},
+		{ID: "test-2", Title: "Gather data", CreatedAt: now, UpdatedAt: now},
+		{ID: "test-3", Title: "Unrelated", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := storage.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	if err := tm.Link(ctx, "test-1", "test-2"); err != nil {
+		t.Fatalf("Unexpected error linking tasks: %v", err)
+	}
+	if err := tm.Link(ctx, "test-1", "test-1"); err == nil {
+		t.Error("Expected error linking a task to itself")
+	}
+
+	a, _ := storage.GetByID(ctx, "test-1")
+	b, _ := storage.GetByID(ctx, "test-2")
+	if len(a.RelatedTo) != 1 || a.RelatedTo[0] != "test-2" {
+		t.Errorf("Expected test-1 to relate to test-2, got %v", a.RelatedTo)
+	}
+	if len(b.RelatedTo) != 1 || b.RelatedTo[0] != "test-1" {
+		t.Errorf("Expected test-2 to relate to test-1, got %v", b.RelatedTo)
+	}
+
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	if err := tm.Show(ctx, "test-1"); err != nil {
+		t.Fatalf("Unexpected error showing task: %v", err)
+	}
+	if !strings.Contains(out.String(), "Gather data (test-2)") {
+		t.Errorf("Expected show output to list related task, got:\n%s", out.String())
+	}
+
+	if err := tm.Delete(ctx, "test-2"); err != nil {
+		t.Fatalf("Unexpected error deleting task: %v", err)
+	}
+	a, _ = storage.GetByID(ctx, "test-1")
+	if len(a.RelatedTo) != 0 {
+		t.Errorf("Expected link to be cleaned up after delete, got %v", a.RelatedTo)
+	}
+
+	if err := tm.Unlink(ctx, "test-1", "test-3"); err == nil {
+		t.Error("Expected error unlinking tasks that are not linked")
+	}
+}
+
+func TestTaskManagerDeleteCleansDependencies(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "blocker", Title: "Blocker", CreatedAt: now, UpdatedAt: now},
+		{ID: "other", Title: "Other blocker", CreatedAt: now, UpdatedAt: now},
+		{ID: "dependent-1", Title: "Dependent 1", CreatedAt: now, UpdatedAt: now},
+		{ID: "dependent-2", Title: "Dependent 2", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := storage.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	for _, dep := range []struct{ id, blocker string }{
+		{"dependent-1", "blocker"},
+		{"dependent-1", "other"},
+		{"dependent-2", "blocker"},
+	} {
+		if err := tm.AddDependency(ctx, dep.id, dep.blocker); err != nil {
+			t.Fatalf("Unexpected error adding dependency: %v", err)
+		}
+	}
+	if err := tm.Link(ctx, "dependent-2", "blocker"); err != nil {
+		t.Fatalf("Unexpected error linking tasks: %v", err)
+	}
+
+	if err := tm.Delete(ctx, "blocker"); err != nil {
+		t.Fatalf("Unexpected error deleting task: %v", err)
+	}
+
+	d1, _ := storage.GetByID(ctx, "dependent-1")
+	if len(d1.DependsOn) != 1 || d1.DependsOn[0] != "other" {
+		t.Errorf("Expected dependent-1 to depend only on other, got %v", d1.DependsOn)
+	}
+	d2, _ := storage.GetByID(ctx, "dependent-2")
+	if len(d2.DependsOn) != 0 || len(d2.RelatedTo) != 0 {
+		t.Errorf("Expected dependent-2 references to be removed, got deps %v links %v", d2.DependsOn, d2.RelatedTo)
+	}
+
+	if err := tm.AddDependency(ctx, "other", "other"); err == nil {
+		t.Error("Expected error for self-dependency")
+	}
+}
+
+func TestTaskManagerUpsert(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	ctx := context.Background()
+
+	created := time.Now().Add(-24 * time.Hour)
+	existing := &task.Task{ID: "test-1", Title: "Original", CreatedAt: created, UpdatedAt: created}
+	if err := storage.Add(ctx, existing); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	// Existing ID updates in place
+	isNew, err := tm.Upsert(ctx, &task.Task{ID: "test-1", Title: "Synced", UpdatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("Unexpected error upserting existing task: %v", err)
+	}
+	if isNew {
+		t.Error("Expected upsert of existing ID to report an update")
+	}
+
+	// New ID inserts
+	isNew, err = tm.Upsert(ctx, &task.Task{ID: "test-2", Title: "Fresh", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("Unexpected error upserting new task: %v", err)
+	}
+	if !isNew {
+		t.Error("Expected upsert of new ID to report an insert")
+	}
+
+	tasks, _ := storage.Load(ctx)
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+	}
+	updated, _ := storage.GetByID(ctx, "test-1")
+	if updated.Title != "Synced" {
+		t.Errorf("Expected title Synced, got %s", updated.Title)
+	}
+	if !updated.CreatedAt.Equal(created) {
+		t.Errorf("Expected creation time to be preserved")
+	}
+
+	// Invalid tasks are rejected without touching the store
+	if _, err := tm.Upsert(ctx, &task.Task{ID: "test-3"}); err == nil {
+		t.Error("Expected error upserting invalid task")
+	}
+}
+
+func TestTaskManagerAddFromJSONUpsert(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	now := time.Now()
+	if err := storage.Add(ctx, &task.Task{ID: "test-1", Title: "Original", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	input := `[{"id": "test-1", "title": "Updated"}, {"title": "Without ID"}]`
+
+	if err := tm.AddFromJSON(ctx, strings.NewReader(input), false); err == nil {
+		t.Error("Expected duplicate ID to fail without upsert")
+	}
+
+	storage.Save(ctx, []*task.Task{{ID: "test-1", Title: "Original", CreatedAt: now, UpdatedAt: now}})
+	if err := tm.AddFromJSON(ctx, strings.NewReader(input), true); err != nil {
+		t.Fatalf("Unexpected error adding with upsert: %v", err)
+	}
+
+	tasks, _ := storage.Load(ctx)
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+	}
+	if tasks[0].Title != "Updated" {
+		t.Errorf("Expected first task to be updated, got %s", tasks[0].Title)
+	}
+	if tasks[1].ID == "" {
+		t.Error("Expected task without ID to be assigned one")
+	}
+}
+
+// countingStorage counts loads and saves to check round trips
+type countingStorage struct {
+	*storage.InMemoryStorage
+	loads, saves int
+}
+
+func (s *countingStorage) Load(ctx context.Context) ([]*task.Task, error) {
+	s.loads++
+	return s.InMemoryStorage.Load(ctx)
+}
+
+func (s *countingStorage) Save(ctx context.Context, tasks []*task.Task) error {
+	s.saves++
+	return s.InMemoryStorage.Save(ctx, tasks)
+}
+
+func TestTaskManagerAddFromJSONSingleRoundTrip(t *testing.T) {
+	store := &countingStorage{InMemoryStorage: storage.NewInMemoryStorage()}
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	tm.SetUndoLog(filepath.Join(t.TempDir(), "undo.jsonl"))
+	ctx := context.Background()
+
+	now := time.Now()
+	if err := store.InMemoryStorage.Add(ctx, &task.Task{ID: "test-1", Title: "Original", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	input := `[{"id": "test-1", "title": "Updated"}, {"id": "test-2", "title": "New", "tags": [" Work ", "work", "Home"]}, {"id": "test-3", "title": "Child", "parent_id": "test-2"}]`
+	if err := tm.AddFromJSON(ctx, strings.NewReader(input), true); err != nil {
+		t.Fatalf("Unexpected error adding with upsert: %v", err)
+	}
+	if store.loads != 1 || store.saves != 1 {
+		t.Errorf("Expected one load and one save, got %d and %d", store.loads, store.saves)
+	}
+
+	added, err := store.GetByID(ctx, "test-2")
+	if err != nil {
+		t.Fatalf("Unexpected error getting task: %v", err)
+	}
+	if !reflect.DeepEqual(added.Tags, []string{"home", "work"}) {
+		t.Errorf("Expected normalized tags, got %v", added.Tags)
+	}
+
+	// The whole batch is undone as one operation
+	if _, err := tm.Undo(ctx); err != nil {
+		t.Fatalf("Unexpected error undoing: %v", err)
+	}
+	tasks, _ := store.InMemoryStorage.Load(ctx)
+	if len(tasks) != 1 || tasks[0].Title != "Original" {
+		t.Errorf("Expected only the original task after undo, got %+v", tasks)
+	}
+}
+
+func TestTaskManagerListHighlightsSearch(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	ctx := context.Background()
+
+	now := time.Now()
+	if err := storage.Add(ctx, &task.Task{ID: "test-1", Title: "Learn Go", Description: "Read the tour", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	if err := tm.List(ctx, ListOptions{Search: "go"}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+	if !strings.Contains(out.String(), "Learn *Go*") {
+		t.Errorf("Expected highlighted title, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Read the tour") {
+		t.Errorf("Expected non-matching description unchanged, got:\n%s", out.String())
+	}
+}
+
+// slowStorage delays every Load to simulate a large or remote store
+type slowStorage struct {
+	*storage.InMemoryStorage
+	delay time.Duration
+}
+
+func (s *slowStorage) Load(ctx context.Context) ([]*task.Task, error) {
+	time.Sleep(s.delay)
+	return s.InMemoryStorage.Load(ctx)
+}
+
+func TestTaskManagerAddFromJSONTimeout(t *testing.T) {
+	store := &slowStorage{InMemoryStorage: storage.NewInMemoryStorage(), delay: 50 * time.Millisecond}
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+
+	var input strings.Builder
+	input.WriteString("[")
+	for i := 0; i < 100; i++ {
+		if i > 0 {
+			input.WriteString(",")
+		}
+		fmt.Fprintf(&input, `{"id": "test-%d", "title": "Task %d"}`, i, i)
+	}
+	input.WriteString("]")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := tm.AddFromJSON(ctx, strings.NewReader(input.String()), false)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	tasks, _ := store.Load(context.Background())
+	if len(tasks) != 0 {
+		t.Errorf("Expected a stopped import to save nothing, got %d tasks", len(tasks))
+	}
+}
+
+func TestExportSummary(t *testing.T) {
+	storage := storage.NewInMemoryStorage()
+	tm := NewTaskManager(storage)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "test-1", Title: "Done", Priority: task.High, Completed: true, CreatedAt: now, UpdatedAt: now},
+		{ID: "test-2", Title: "Late", Priority: task.High, DueDate: now.Add(-time.Hour), CreatedAt: now, UpdatedAt: now},
+		{ID: "test-3", Title: "Open", Priority: task.Low, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := storage.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "go-fun-summary-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Default JSON stays a bare array
+	plainPath := filepath.Join(tempDir, "plain.json")
+	if err := tm.ExportTasks(ctx, "json", plainPath, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting JSON: %v", err)
+	}
+	data, _ := os.ReadFile(plainPath)
+	var bare []*task.Task
+	if err := json.Unmarshal(data, &bare); err != nil {
+		t.Fatalf("Expected default export to be a bare array: %v", err)
+	}
+	if len(bare) != 3 {
+		t.Errorf("Expected 3 tasks, got %d", len(bare))
+	}
+
+	// With summary, JSON is wrapped
+	wrappedPath := filepath.Join(tempDir, "wrapped.json")
+	if err := tm.ExportTasks(ctx, "json", wrappedPath, ExportOptions{Summary: true}); err != nil {
+		t.Fatalf("Unexpected error exporting JSON: %v", err)
+	}
+	data, _ = os.ReadFile(wrappedPath)
+	var wrapped struct {
+		Summary struct {
+			Total      int            `json:"total"`
+			Completed  int            `json:"completed"`
+			Overdue    int            `json:"overdue"`
+			ByPriority map[string]int `json:"by_priority"`
+		} `json:"summary"`
+		Tasks []*task.Task `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		t.Fatalf("Failed to parse wrapped export: %v", err)
+	}
+	if wrapped.Summary.Total != 3 || wrapped.Summary.Completed != 1 || wrapped.Summary.Overdue != 1 {
+		t.Errorf("Unexpected summary: %+v", wrapped.Summary)
+	}
+	if wrapped.Summary.ByPriority["high"] != 2 || wrapped.Summary.ByPriority["low"] != 1 {
+		t.Errorf("Unexpected priority breakdown: %v", wrapped.Summary.ByPriority)
+	}
+	if len(wrapped.Tasks) != 3 {
+		t.Errorf("Expected 3 wrapped tasks, got %d", len(wrapped.Tasks))
+	}
+
+	// CSV gets a leading comment only when asked
+	csvPath := filepath.Join(tempDir, "tasks.csv")
+	if err := tm.ExportTasks(ctx, "csv", csvPath, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting CSV: %v", err)
+	}
+	data, _ = os.ReadFile(csvPath)
+	if !strings.HasPrefix(string(data), "ID,") {
+		t.Errorf("Expected default CSV to start with the header, got %q", data)
+	}
+	if err := tm.ExportTasks(ctx, "csv", csvPath, ExportOptions{Summary: true}); err != nil {
+		t.Fatalf("Unexpected error exporting CSV: %v", err)
+	}
+	data, _ = os.ReadFile(csvPath)
+	if !strings.HasPrefix(string(data), "# total=3 completed=1 overdue=1 ") {
+		t.Errorf("Expected CSV summary comment, got %q", data)
+	}
+}
+
 // Benchmark tests
 func BenchmarkTaskManagerAdd(b *testing.B) {
 	storage := storage.NewInMemoryStorage()
@@ -339,7 +1966,7 @@ func BenchmarkTaskManagerAdd(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		tm.Add(ctx, title, description, priority, dueDate)
+		tm.Add(ctx, title, description, priority, dueDate, nil)
 	}
 }
 
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrAborted is returned when a destructive command's confirmation prompt is
+// declined or gets no answer, so scripts see a failure instead of a no-op
+var ErrAborted = errors.New("aborted")
+
+// SetInput replaces the reader prompts and Shell read from, which defaults
+// to stdin. Everything reading input shares one buffer, so a prompt inside
+// a shell command gets the next line rather than one already buffered.
+func (tm *TaskManager) SetInput(r io.Reader) {
+	if br, ok := r.(*bufio.Reader); ok {
+		tm.in = br
+		return
+	}
+	tm.in = bufio.NewReader(r)
+}
+
+// SetAssumeYes makes every confirmation prompt succeed without asking
+func (tm *TaskManager) SetAssumeYes(enabled bool) {
+	tm.assumeYes = enabled
+}
+
+// Confirm asks a yes/no question and reports whether the answer was yes.
+// Anything other than "y" or "yes", including end of input, counts as no.
+func (tm *TaskManager) Confirm(prompt string) (bool, error) {
+	if tm.assumeYes {
+		return true, nil
+	}
+
+	fmt.Fprintf(tm.out, "%s [y/N]: ", prompt)
+
+	line, err := tm.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// ConfirmOrAbort is Confirm for commands that must not carry on silently:
+// anything but yes, including end of input, returns ErrAborted
+func (tm *TaskManager) ConfirmOrAbort(prompt string) error {
+	ok, err := tm.Confirm(prompt)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-fun/internal/storage"
+)
+
+func TestTaskManagerConfirm(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		assumeYes bool
+		want      bool
+	}{
+		{"yes", "y\n", false, true},
+		{"full word", "YES\n", false, true},
+		{"no", "n\n", false, false},
+		{"blank line", "\n", false, false},
+		{"empty reader", "", false, false},
+		{"assume yes with empty reader", "", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tm := NewTaskManager(storage.NewInMemoryStorage())
+			tm.SetOutput(&out)
+			tm.SetInput(strings.NewReader(tt.input))
+			tm.SetAssumeYes(tt.assumeYes)
+
+			got, err := tm.Confirm("Delete task?")
+			if err != nil {
+				t.Fatalf("Unexpected error confirming: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Confirm() = %v, want %v", got, tt.want)
+			}
+
+			prompted := strings.Contains(out.String(), "Delete task? [y/N]")
+			if prompted == tt.assumeYes {
+				t.Errorf("Expected prompt shown = %v, output %q", !tt.assumeYes, out.String())
+			}
+		})
+	}
+}
+
+func TestTaskManagerConfirmOrAbort(t *testing.T) {
+	for _, input := range []string{"n\n", ""} {
+		tm := NewTaskManager(storage.NewInMemoryStorage())
+		tm.SetOutput(&bytes.Buffer{})
+		tm.SetInput(strings.NewReader(input))
+		if err := tm.ConfirmOrAbort("Purge?"); !errors.Is(err, ErrAborted) {
+			t.Errorf("Input %q: expected ErrAborted, got %v", input, err)
+		}
+	}
+
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	tm.SetOutput(&bytes.Buffer{})
+	tm.SetInput(strings.NewReader("y\n"))
+	if err := tm.ConfirmOrAbort("Purge?"); err != nil {
+		t.Errorf("Expected yes to go ahead, got %v", err)
+	}
+}
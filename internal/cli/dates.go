@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDate parses an absolute date, a keyword like "tomorrow", or an offset
+// from now such as "3d", "1w", "2h" or a bare number of days
+func ParseDate(dateStr string, now time.Time) (time.Time, error) {
+	// Handle special cases first
+	switch strings.ToLower(dateStr) {
+	case "today":
+		return now.Truncate(24 * time.Hour), nil
+	case "tomorrow":
+		return now.Add(24 * time.Hour).Truncate(24 * time.Hour), nil
+	}
+
+	// Try different date formats
+	formats := []string{
+		"2006-01-02",
+		"2006-01-02 15:04",
+		"2006-01-02 15:04:05",
+		"01/02/2006",
+		"01/02/2006 15:04",
+	}
+
+	for _, format := range formats {
+		if t, err := time.Parse(format, dateStr); err == nil {
+			return t, nil
+		}
+	}
+
+	if offset, err := ParseOffset(dateStr); err == nil {
+		return now.Add(offset), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse date: %s", dateStr)
+}
+
+// endOfDay returns the last second of t's day in t's location
+func endOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 23, 59, 59, 0, t.Location())
+}
+
+// ParseOffset parses a relative offset: "14d" days, "2w" weeks, a Go duration
+// like "2h30m", or a bare number of days. A leading "+" or "-" is allowed.
+func ParseOffset(s string) (time.Duration, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "+")
+
+	// Handle "d" and "w" suffixes for days and weeks
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(trimmed, suffix) {
+			if n, err := strconv.Atoi(strings.TrimSuffix(trimmed, suffix)); err == nil {
+				return time.Duration(n) * unit, nil
+			}
+		}
+	}
+
+	// Try standard duration parsing
+	if duration, err := time.ParseDuration(trimmed); err == nil {
+		return duration, nil
+	}
+
+	// Try parsing as days (e.g., "3" means 3 days)
+	if days, err := strconv.Atoi(trimmed); err == nil {
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	return 0, fmt.Errorf("unable to parse offset: %s", s)
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseOffset(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"14d", 14 * day, false},
+		{"+14d", 14 * day, false},
+		{"-1d", -day, false},
+		{"2w", 14 * day, false},
+		{"3", 3 * day, false},
+		{"2h30m", 2*time.Hour + 30*time.Minute, false},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseOffset(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOffset(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOffset(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2025-07-01", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"07/01/2025", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"tomorrow", time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)},
+		{"3d", now.Add(72 * time.Hour)},
+		{"1w", now.Add(7 * 24 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDate(tt.input, now)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) unexpected error: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseDate("someday", now); err == nil {
+		t.Error("Expected error for unparseable date")
+	}
+}
@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// fingerprint identifies a task's content for duplicate detection: the title
+// lowercased with whitespace collapsed, plus the due day
+func fingerprint(t *task.Task) string {
+	title := strings.Join(strings.Fields(strings.ToLower(t.Title)), " ")
+	due := ""
+	if !t.DueDate.IsZero() {
+		due = t.DueDate.Format(time.DateOnly)
+	}
+	return title + "\x00" + due
+}
+
+// findDuplicates groups pending tasks sharing a fingerprint. Each group is
+// ordered oldest first; groups keep the order of their first task.
+func findDuplicates(tasks []*task.Task) [][]*task.Task {
+	groups := make(map[string][]*task.Task)
+	var order []string
+	for _, t := range tasks {
+		if t.Completed {
+			continue
+		}
+		key := fingerprint(t)
+		if _, seen := groups[key]; !seen {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], t)
+	}
+
+	var dupes [][]*task.Task
+	for _, key := range order {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].CreatedAt.Before(group[j].CreatedAt)
+		})
+		dupes = append(dupes, group)
+	}
+	return dupes
+}
+
+// mergeInto folds the tags and descriptions of drop into keep, removes drop
+// from tasks and points references to the dropped tasks at keep instead. It
+// returns the remaining tasks and the changed ones as event pairs of before
+// and after.
+func mergeInto(tasks []*task.Task, keep *task.Task, drop []*task.Task) ([]*task.Task, [][2]*task.Task) {
+	now := time.Now()
+	changed := [][2]*task.Task{{snapshot(keep), keep}}
+
+	dropped := make(map[string]bool, len(drop))
+	for _, d := range drop {
+		dropped[d.ID] = true
+		// Tags that would break the tag limits are left behind
+		for _, tag := range d.Tags {
+			if merged := task.NormalizeTags(append(slices.Clone(keep.Tags), tag)); task.ValidateTags(merged) == nil {
+				keep.Tags = merged
+			}
+		}
+		// Keep distinct descriptions on one line, so edit can show them,
+		// while they fit the length limit
+		if d.Description != "" && !strings.Contains(keep.Description, d.Description) {
+			merged := d.Description
+			if keep.Description != "" {
+				merged = keep.Description + "; " + d.Description
+			}
+			if len(merged) <= 500 {
+				keep.Description = merged
+			}
+		}
+	}
+	keep.UpdatedAt = now
+
+	remaining := make([]*task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if dropped[t.ID] {
+			continue
+		}
+		remaining = append(remaining, t)
+
+		before := snapshot(t)
+		repointed := false
+		if dropped[t.ParentID] {
+			t.ParentID = keep.ID
+			repointed = true
+		}
+		for id := range dropped {
+			if t.RemoveDependency(id) {
+				repointed = true
+				if t.ID != keep.ID {
+					t.AddDependency(keep.ID)
+				}
+			}
+			if t.RemoveRelated(id) {
+				repointed = true
+				if t.ID != keep.ID {
+					t.AddRelated(keep.ID)
+				}
+			}
+		}
+		if repointed && t != keep {
+			t.UpdatedAt = now
+			changed = append(changed, [2]*task.Task{before, t})
+		}
+	}
+	return remaining, changed
+}
+
+// Dedupe finds pending tasks with the same normalized title and due day and
+// merges each group into one survivor. With auto, or when prompts are
+// answered automatically, the oldest task is kept; otherwise the user picks
+// per group and may skip it. It returns the number of tasks removed.
+func (tm *TaskManager) Dedupe(ctx context.Context, auto bool) (n int, err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	groups := findDuplicates(tasks)
+	if len(groups) == 0 {
+		fmt.Fprintln(tm.out, "No duplicate tasks found.")
+		return 0, nil
+	}
+
+	removed := 0
+	var merged []*task.Task
+	var updated [][2]*task.Task
+	firstSeen := make(map[string]bool)
+	for i, group := range groups {
+		fmt.Fprintf(tm.out, "\nDuplicate group %d of %d:\n", i+1, len(groups))
+		for j, t := range group {
+			fmt.Fprintf(tm.out, "  %d) %s (%s, created %s)\n", j+1, t.Title, t.ID, t.CreatedAt.Format("2006-01-02 15:04"))
+		}
+
+		keep := 0
+		if !auto && !tm.assumeYes {
+			choice, err := promptKeep(tm.out, tm.in, len(group))
+			if err != nil {
+				return 0, err
+			}
+			if choice < 0 {
+				fmt.Fprintln(tm.out, "Skipped.")
+				continue
+			}
+			keep = choice
+		}
+
+		survivor := group[keep]
+		drop := append(append([]*task.Task{}, group[:keep]...), group[keep+1:]...)
+		var changed [][2]*task.Task
+		tasks, changed = mergeInto(tasks, survivor, drop)
+		// A task changed by several groups is logged once, from its
+		// state before the first
+		for _, pair := range changed {
+			if !firstSeen[pair[1].ID] {
+				firstSeen[pair[1].ID] = true
+				updated = append(updated, pair)
+			}
+		}
+		removed += len(drop)
+		merged = append(merged, drop...)
+		fmt.Fprintf(tm.out, "Kept %s, merged %d duplicates\n", survivor.ID, len(drop))
+	}
+
+	if removed == 0 {
+		return 0, nil
+	}
+	if err := tm.storage.Save(ctx, tasks); err != nil {
+		return 0, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	if err := tm.logDeleted(merged, updated); err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintf(tm.out, "%s Removed %d duplicate tasks\n", tm.Icons().Success, removed)
+	return removed, nil
+}
+
+// promptKeep asks which of n tasks to keep and returns its index, or -1 to
+// skip the group. An empty answer keeps the first (oldest) task.
+func promptKeep(out io.Writer, in *bufio.Reader, n int) (int, error) {
+	for {
+		fmt.Fprintf(out, "Keep which? [1-%d, s to skip] (default 1): ", n)
+		line, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return 0, fmt.Errorf("failed to read choice: %w", err)
+		}
+
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if choice, convErr := strconv.Atoi(answer); convErr == nil && choice >= 1 && choice <= n {
+			return choice - 1, nil
+		}
+		switch {
+		case answer == "s" || answer == "skip" || err == io.EOF:
+			// End of input skips rather than merging unattended
+			return -1, nil
+		case answer == "":
+			return 0, nil
+		}
+		fmt.Fprintf(out, "Please enter a number between 1 and %d.\n", n)
+	}
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	base := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2025, 6, 10, 17, 0, 0, 0, time.UTC)
+	tasks := []*task.Task{
+		{ID: "newer", Title: "Buy  Milk", DueDate: due, CreatedAt: base.Add(time.Hour)},
+		{ID: "other-day", Title: "Buy milk", DueDate: due.AddDate(0, 0, 1), CreatedAt: base},
+		{ID: "older", Title: "buy milk", DueDate: due.Add(-8 * time.Hour), CreatedAt: base},
+		{ID: "done", Title: "Buy milk", DueDate: due, Completed: true, CreatedAt: base},
+		{ID: "solo", Title: "Call mum", CreatedAt: base},
+	}
+
+	groups := findDuplicates(tasks)
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 duplicate group, got %d", len(groups))
+	}
+	var ids []string
+	for _, d := range groups[0] {
+		ids = append(ids, d.ID)
+	}
+	if want := []string{"older", "newer"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Expected group %v oldest first, got %v", want, ids)
+	}
+}
+
+func TestTaskManagerDedupeAuto(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	base := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	for _, tt := range []*task.Task{
+		{ID: "a", Title: "Write report", Description: "Q3", Tags: []string{"work"}, CreatedAt: base, UpdatedAt: base},
+		{ID: "b", Title: "write report", Description: "Use the new template", Tags: []string{"work", "urgent"}, CreatedAt: base.Add(time.Hour), UpdatedAt: base},
+		{ID: "dep", Title: "Send report", DependsOn: []string{"b"}, CreatedAt: base, UpdatedAt: base},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	removed, err := tm.Dedupe(ctx, true)
+	if err != nil {
+		t.Fatalf("Unexpected error deduping: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 task removed, got %d", removed)
+	}
+
+	if _, err := store.GetByID(ctx, "b"); err == nil {
+		t.Error("Expected the newer duplicate to be deleted")
+	}
+	kept, err := store.GetByID(ctx, "a")
+	if err != nil {
+		t.Fatalf("Unexpected error getting survivor: %v", err)
+	}
+	if want := []string{"urgent", "work"}; !reflect.DeepEqual(kept.Tags, want) {
+		t.Errorf("Expected merged tags %v, got %v", want, kept.Tags)
+	}
+	if kept.Description != "Q3; Use the new template" {
+		t.Errorf("Expected merged description, got %q", kept.Description)
+	}
+
+	dep, err := store.GetByID(ctx, "dep")
+	if err != nil {
+		t.Fatalf("Unexpected error getting dependent task: %v", err)
+	}
+	if !reflect.DeepEqual(dep.DependsOn, []string{"a"}) {
+		t.Errorf("Expected dependency moved to survivor, got %v", dep.DependsOn)
+	}
+}
+
+func TestTaskManagerDedupeUndo(t *testing.T) {
+	tm, store := newUndoManager(t)
+	ctx := context.Background()
+
+	base := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	for _, tt := range []*task.Task{
+		{ID: "a", Title: "Write report", Description: "Q3", Tags: []string{"work"}, CreatedAt: base, UpdatedAt: base},
+		{ID: "b", Title: "write report", Description: "Use the new template", Tags: []string{"urgent"}, CreatedAt: base.Add(time.Hour), UpdatedAt: base},
+		{ID: "dep", Title: "Send report", DependsOn: []string{"b"}, CreatedAt: base, UpdatedAt: base},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	if _, err := tm.Dedupe(ctx, true); err != nil {
+		t.Fatalf("Unexpected error deduping: %v", err)
+	}
+	if undone, err := tm.Undo(ctx); err != nil || !undone {
+		t.Fatalf("Expected dedupe to be undone, got %v, %v", undone, err)
+	}
+
+	kept, err := store.GetByID(ctx, "a")
+	if err != nil {
+		t.Fatalf("Unexpected error getting survivor: %v", err)
+	}
+	if !reflect.DeepEqual(kept.Tags, []string{"work"}) || kept.Description != "Q3" {
+		t.Errorf("Expected survivor restored, got tags %v and description %q", kept.Tags, kept.Description)
+	}
+	if _, err := store.GetByID(ctx, "b"); err != nil {
+		t.Errorf("Expected merged duplicate restored: %v", err)
+	}
+	dep, err := store.GetByID(ctx, "dep")
+	if err != nil {
+		t.Fatalf("Unexpected error getting dependent task: %v", err)
+	}
+	if !reflect.DeepEqual(dep.DependsOn, []string{"b"}) {
+		t.Errorf("Expected dependency restored, got %v", dep.DependsOn)
+	}
+}
+
+func TestTaskManagerDedupeSkipsInvalidTags(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	var tags []string
+	for i := range 20 {
+		tags = append(tags, fmt.Sprintf("tag%02d", i))
+	}
+	base := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	for _, tt := range []*task.Task{
+		{ID: "a", Title: "Write report", Tags: tags, CreatedAt: base, UpdatedAt: base},
+		{ID: "b", Title: "Write report", Tags: []string{"extra"}, CreatedAt: base.Add(time.Hour), UpdatedAt: base},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	if _, err := tm.Dedupe(ctx, true); err != nil {
+		t.Fatalf("Unexpected error deduping: %v", err)
+	}
+	kept, err := store.GetByID(ctx, "a")
+	if err != nil {
+		t.Fatalf("Unexpected error getting survivor: %v", err)
+	}
+	if err := task.ValidateTags(kept.Tags); err != nil {
+		t.Errorf("Expected merged tags within limits, got %d: %v", len(kept.Tags), err)
+	}
+}
+
+func TestTaskManagerDedupeInteractive(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	// Keep the second of the first group, skip the second group
+	tm.SetInput(strings.NewReader("2\ns\n"))
+	ctx := context.Background()
+
+	base := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	for _, tt := range []*task.Task{
+		{ID: "a1", Title: "Alpha", CreatedAt: base, UpdatedAt: base},
+		{ID: "a2", Title: "Alpha", CreatedAt: base.Add(time.Hour), UpdatedAt: base},
+		{ID: "b1", Title: "Beta", CreatedAt: base, UpdatedAt: base},
+		{ID: "b2", Title: "Beta", CreatedAt: base.Add(time.Hour), UpdatedAt: base},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	removed, err := tm.Dedupe(ctx, false)
+	if err != nil {
+		t.Fatalf("Unexpected error deduping: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 task removed, got %d", removed)
+	}
+
+	tasks, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	var ids []string
+	for _, tt := range tasks {
+		ids = append(ids, tt.ID)
+	}
+	if want := []string{"a2", "b1", "b2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Expected %v to remain, got %v", want, ids)
+	}
+}
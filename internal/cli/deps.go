@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-fun/internal/task"
+)
+
+// DepProblem is a DependsOn entry that is broken: it names a missing task,
+// or it is the edge closing a cycle
+type DepProblem struct {
+	TaskID    string
+	DependsOn string
+	Cycle     []string // the IDs around the cycle, first repeated last; nil when dangling
+}
+
+// String describes the problem in one line
+func (p DepProblem) String() string {
+	if p.Cycle == nil {
+		return fmt.Sprintf("%s depends on missing task %s", p.TaskID, p.DependsOn)
+	}
+	return "cycle: " + strings.Join(p.Cycle, " -> ")
+}
+
+// Deps checks every task's dependencies for dangling IDs and cycles and
+// prints what it finds. With fix it removes each broken edge, which for a
+// cycle is the last edge found closing it, and saves once.
+func (tm *TaskManager) Deps(ctx context.Context, fix bool) (problems []DepProblem, err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	problems = findDepProblems(tasks)
+	if len(problems) == 0 {
+		fmt.Fprintf(tm.out, "%s Dependencies are consistent\n", tm.Icons().Success)
+		return nil, nil
+	}
+
+	if !fix {
+		for _, p := range problems {
+			fmt.Fprintf(tm.out, "%s %s\n", tm.Icons().DependsOn, p)
+		}
+		return problems, nil
+	}
+
+	changed := fixDepProblems(tasks, problems)
+	if err := tm.storage.Save(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	for _, pair := range changed {
+		if err := tm.logEvent("update", pair[1].ID, pair[0], pair[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range problems {
+		fmt.Fprintf(tm.out, "%s Removed %s -> %s: %s\n", tm.Icons().Success, p.TaskID, p.DependsOn, p)
+	}
+	return problems, nil
+}
+
+// findDepProblems lists dangling dependencies, then the edges closing each
+// cycle. A depth-first walk in stored order finds the cycles, so removing
+// every reported edge leaves the graph acyclic.
+func findDepProblems(tasks []*task.Task) []DepProblem {
+	byID := make(map[string]*task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	var problems []DepProblem
+	for _, t := range tasks {
+		for _, dep := range t.DependsOn {
+			if byID[dep] == nil {
+				problems = append(problems, DepProblem{TaskID: t.ID, DependsOn: dep})
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(tasks))
+	var path []string
+
+	var visit func(t *task.Task)
+	visit = func(t *task.Task) {
+		state[t.ID] = visiting
+		path = append(path, t.ID)
+		for _, dep := range t.DependsOn {
+			next := byID[dep]
+			if next == nil {
+				continue
+			}
+			switch state[dep] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				start := len(path) - 1
+				for path[start] != dep {
+					start--
+				}
+				cycle := append(append([]string(nil), path[start:]...), dep)
+				problems = append(problems, DepProblem{TaskID: t.ID, DependsOn: dep, Cycle: cycle})
+			}
+		}
+		path = path[:len(path)-1]
+		state[t.ID] = visited
+	}
+
+	for _, t := range tasks {
+		if state[t.ID] == unvisited {
+			visit(t)
+		}
+	}
+	return problems
+}
+
+// fixDepProblems removes each problem's edge and returns the changed tasks
+// as before/after pairs
+func fixDepProblems(tasks []*task.Task, problems []DepProblem) [][2]*task.Task {
+	byID := make(map[string]*task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	var changed [][2]*task.Task
+	seen := make(map[string]bool)
+	for _, p := range problems {
+		t := byID[p.TaskID]
+		before := snapshot(t)
+		if t.RemoveDependency(p.DependsOn) && !seen[t.ID] {
+			seen[t.ID] = true
+			changed = append(changed, [2]*task.Task{before, t})
+		}
+	}
+	return changed
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestFindDepProblems(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "a", Title: "A", DependsOn: []string{"b"}},
+		{ID: "b", Title: "B", DependsOn: []string{"c", "gone"}},
+		{ID: "c", Title: "C", DependsOn: []string{"a"}},
+		{ID: "d", Title: "D", DependsOn: []string{"c"}},
+	}
+
+	problems := findDepProblems(tasks)
+	expected := []DepProblem{
+		{TaskID: "b", DependsOn: "gone"},
+		{TaskID: "c", DependsOn: "a", Cycle: []string{"a", "b", "c", "a"}},
+	}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, problems)
+	}
+
+	if problems := findDepProblems([]*task.Task{{ID: "a", DependsOn: []string{"b"}}, {ID: "b"}}); len(problems) != 0 {
+		t.Errorf("Expected no problems for a chain, got %+v", problems)
+	}
+}
+
+func TestTaskManagerDepsFix(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	for _, tt := range []*task.Task{
+		{ID: "a", Title: "A", DependsOn: []string{"b", "gone"}},
+		{ID: "b", Title: "B", DependsOn: []string{"a"}},
+		{ID: "self", Title: "Self", DependsOn: []string{"self"}},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	// Checking reports without changing anything
+	problems, err := tm.Deps(ctx, false)
+	if err != nil {
+		t.Fatalf("Unexpected error checking: %v", err)
+	}
+	if len(problems) != 3 {
+		t.Fatalf("Expected 3 problems, got %+v", problems)
+	}
+	if a, _ := store.GetByID(ctx, "a"); len(a.DependsOn) != 2 {
+		t.Errorf("Expected check to leave dependencies alone, got %v", a.DependsOn)
+	}
+
+	if _, err := tm.Deps(ctx, true); err != nil {
+		t.Fatalf("Unexpected error fixing: %v", err)
+	}
+
+	expected := map[string][]string{"a": {"b"}, "b": nil, "self": nil}
+	for id, want := range expected {
+		got, _ := store.GetByID(ctx, id)
+		if !reflect.DeepEqual(got.DependsOn, want) {
+			t.Errorf("%s: expected dependencies %v, got %v", id, want, got.DependsOn)
+		}
+	}
+
+	problems, err = tm.Deps(ctx, false)
+	if err != nil {
+		t.Fatalf("Unexpected error re-checking: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("Expected no problems after fixing, got %+v", problems)
+	}
+}
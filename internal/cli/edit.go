@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// editDateFormat is how due dates appear in the edit buffer
+const editDateFormat = "2006-01-02 15:04"
+
+// FieldChange is one field that differs between two versions of a task
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// diffTasks lists the user-editable fields that differ between two tasks
+func (tm *TaskManager) diffTasks(before, after *task.Task) []FieldChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"title", before.Title, after.Title},
+		{"description", before.Description, after.Description},
+		{"priority", tm.config.PriorityLabel(before.Priority), tm.config.PriorityLabel(after.Priority)},
+		{"due", formatEditDate(before.DueDate), formatEditDate(after.DueDate)},
+	}
+
+	var changes []FieldChange
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, FieldChange{Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+	return changes
+}
+
+// formatEditable renders a task as the "key: value" buffer shown in the editor
+func (tm *TaskManager) formatEditable(t *task.Task) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Editing %s. Lines starting with # are ignored.\n", t.ID)
+	fmt.Fprintf(&b, "title: %s\n", t.Title)
+	fmt.Fprintf(&b, "description: %s\n", t.Description)
+	fmt.Fprintf(&b, "priority: %s\n", tm.config.PriorityLabel(t.Priority))
+	fmt.Fprintf(&b, "due: %s\n", formatEditDate(t.DueDate))
+	return b.String()
+}
+
+// parseEditable reads an edit buffer back into a copy of the original task
+func (tm *TaskManager) parseEditable(original *task.Task, text string) (*task.Task, error) {
+	edited := snapshot(original)
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid line %q: expected key: value", line)
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			edited.Title = value
+		case "description":
+			edited.Description = value
+		case "priority":
+			p, err := tm.config.ParsePriority(value)
+			if err != nil {
+				return nil, err
+			}
+			edited.Priority = p
+		case "due":
+			if value == "" {
+				edited.DueDate = time.Time{}
+				continue
+			}
+			// Keep the original instant when the text is unchanged, since
+			// the buffer drops seconds and time zone
+			if value == formatEditDate(original.DueDate) {
+				edited.DueDate = original.DueDate
+				continue
+			}
+			due, err := ParseDate(value, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			edited.DueDate = due
+		default:
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read edited task: %w", err)
+	}
+
+	if err := edited.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid task: %w", err)
+	}
+	return edited, nil
+}
+
+// Edit writes a task to a temporary file, lets editFile change it, and
+// applies the result via Update. With preview, the field changes are shown
+// and must be confirmed first.
+func (tm *TaskManager) Edit(ctx context.Context, id string, editFile func(path string) error, preview bool) error {
+	original, err := tm.storage.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	original = snapshot(original)
+
+	f, err := os.CreateTemp("", "go-fun-edit-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to create edit file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	_, err = f.WriteString(tm.formatEditable(original))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write edit file: %w", err)
+	}
+
+	if err := editFile(path); err != nil {
+		return fmt.Errorf("failed to run editor: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read edit file: %w", err)
+	}
+
+	edited, err := tm.parseEditable(original, string(data))
+	if err != nil {
+		return err
+	}
+
+	changes := tm.diffTasks(original, edited)
+	if len(changes) == 0 {
+		fmt.Fprintln(tm.out, "No changes.")
+		return nil
+	}
+
+	if preview {
+		for _, c := range changes {
+			fmt.Fprintf(tm.out, "  %s: %q → %q\n", c.Field, c.Old, c.New)
+		}
+		ok, err := tm.Confirm("Apply these changes?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(tm.out, "Aborted.")
+			return nil
+		}
+	}
+
+	if err := tm.Update(ctx, id, edited.Title, edited.Description, edited.Priority, edited.DueDate, nil); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(tm.out, "%s Updated %s\n", tm.Icons().Success, id)
+	return nil
+}
+
+// formatEditDate renders a due date for the edit buffer, empty when unset
+func formatEditDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(editDateFormat)
+}
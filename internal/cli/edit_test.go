@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerDiffTasks(t *testing.T) {
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+
+	due := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	before := &task.Task{ID: "t1", Title: "Draft", Description: "Same", Priority: task.Low}
+	after := &task.Task{ID: "t1", Title: "Final", Description: "Same", Priority: task.High, DueDate: due}
+
+	want := []FieldChange{
+		{Field: "title", Old: "Draft", New: "Final"},
+		{Field: "priority", Old: "Low", New: "High"},
+		{Field: "due", Old: "", New: "2025-07-01 09:00"},
+	}
+	if got := tm.diffTasks(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffTasks() = %+v, want %+v", got, want)
+	}
+
+	if got := tm.diffTasks(before, before); len(got) != 0 {
+		t.Errorf("Expected no changes for identical tasks, got %+v", got)
+	}
+}
+
+func TestTaskManagerParseEditable(t *testing.T) {
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	original := task.NewTask("Draft", "Notes", task.Medium, time.Time{}, []string{"work"})
+
+	edited, err := tm.parseEditable(original, tm.formatEditable(original))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing unchanged buffer: %v", err)
+	}
+	if changes := tm.diffTasks(original, edited); len(changes) != 0 {
+		t.Errorf("Expected unchanged buffer to round-trip, got %+v", changes)
+	}
+
+	edited, err = tm.parseEditable(original, "title: Final\npriority: high\ndue: 2025-07-01\n")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing edited buffer: %v", err)
+	}
+	if edited.Title != "Final" || edited.Priority != task.High || edited.DueDate.Day() != 1 {
+		t.Errorf("Unexpected edited task: %+v", edited)
+	}
+	if original.Title != "Draft" {
+		t.Error("Expected original task to be left untouched")
+	}
+
+	if _, err := tm.parseEditable(original, "colour: blue\n"); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if _, err := tm.parseEditable(original, "title:\n"); err == nil {
+		t.Error("Expected error for empty title")
+	}
+}
+
+func TestTaskManagerEditPreview(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	original := task.NewTask("Draft", "Notes", task.Medium, time.Time{}, nil)
+	if err := store.Add(ctx, original); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	retitle := func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(strings.Replace(string(data), "title: Draft", "title: Final", 1)), 0644)
+	}
+
+	// Declining the preview leaves the task unchanged
+	tm.SetInput(strings.NewReader("n\n"))
+	if err := tm.Edit(ctx, original.ID, retitle, true); err != nil {
+		t.Fatalf("Unexpected error editing task: %v", err)
+	}
+	if !strings.Contains(out.String(), `title: "Draft" → "Final"`) {
+		t.Errorf("Expected diff in preview output, got %q", out.String())
+	}
+	if got, _ := store.GetByID(ctx, original.ID); got.Title != "Draft" {
+		t.Errorf("Expected declined edit to keep title, got %q", got.Title)
+	}
+
+	// Confirming applies it
+	tm.SetInput(strings.NewReader("y\n"))
+	if err := tm.Edit(ctx, original.ID, retitle, true); err != nil {
+		t.Fatalf("Unexpected error editing task: %v", err)
+	}
+	if got, _ := store.GetByID(ctx, original.ID); got.Title != "Final" {
+		t.Errorf("Expected confirmed edit to set title, got %q", got.Title)
+	}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// Event is one line of the audit log. Before and After hold the task as it
+// was and as it became; Add has no Before and Delete has no After.
+type Event struct {
+	Timestamp time.Time  `json:"ts"`
+	Op        string     `json:"op"`
+	ID        string     `json:"id"`
+	Before    *task.Task `json:"before,omitempty"`
+	After     *task.Task `json:"after,omitempty"`
+}
+
+// logEvent appends an event to the configured event log, if any, and to the
+// undo log. The event log is write-only: nothing in the tool reads it back.
+func (tm *TaskManager) logEvent(op, id string, before, after *task.Task) error {
+	e := Event{
+		Timestamp: time.Now(),
+		Op:        op,
+		ID:        id,
+		Before:    before,
+		After:     after,
+	}
+	if err := tm.trackEvent(e); err != nil {
+		return err
+	}
+
+	if tm.config == nil || tm.config.EventLog == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	path := tm.config.EventLog
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for event log: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write event log %s: %w", path, err)
+	}
+	return nil
+}
+
+// snapshot copies a task so later mutations do not leak into a logged Before
+func snapshot(t *task.Task) *task.Task {
+	c := *t
+	c.Tags = slices.Clone(t.Tags)
+	c.RelatedTo = slices.Clone(t.RelatedTo)
+	c.DependsOn = slices.Clone(t.DependsOn)
+	c.Notes = slices.Clone(t.Notes)
+	return &c
+}
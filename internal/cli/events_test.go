@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/config"
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerEventLog(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-events-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	logPath := filepath.Join(tempDir, "events.jsonl")
+
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	tm.SetConfig(&config.Config{EventLog: logPath})
+	ctx := context.Background()
+
+	existing := task.NewTask("Write report", "", task.Medium, time.Time{}, nil)
+	if err := store.Add(ctx, existing); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	if err := tm.Complete(ctx, existing.ID); err != nil {
+		t.Fatalf("Unexpected error completing task: %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading event log: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 event line, got %d: %q", len(lines), data)
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(lines[0]), &event); err != nil {
+		t.Fatalf("Event line is not valid JSON: %v", err)
+	}
+
+	if event.Op != "complete" || event.ID != existing.ID {
+		t.Errorf("Unexpected event op/id: %s %s", event.Op, event.ID)
+	}
+	if event.Timestamp.IsZero() {
+		t.Error("Expected event timestamp to be set")
+	}
+	if event.Before == nil || event.Before.Completed {
+		t.Errorf("Expected before snapshot of the pending task, got %+v", event.Before)
+	}
+	if event.After == nil || !event.After.Completed {
+		t.Errorf("Expected after snapshot of the completed task, got %+v", event.After)
+	}
+
+	if err := tm.Delete(ctx, existing.ID); err != nil {
+		t.Fatalf("Unexpected error deleting task: %v", err)
+	}
+
+	data, _ = os.ReadFile(logPath)
+	if n := strings.Count(string(data), "\n"); n != 2 {
+		t.Errorf("Expected delete to append a second line, got %d lines", n)
+	}
+}
+
+func TestTaskManagerEventLogDisabled(t *testing.T) {
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	tm.SetOutput(&bytes.Buffer{})
+
+	if err := tm.Add(context.Background(), "No log", "", task.Low, time.Time{}, nil); err != nil {
+		t.Fatalf("Unexpected error adding task without an event log: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"io"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+// exporterFunc adapts a write function to storage.Exporter
+type exporterFunc struct {
+	name  string
+	write func(w io.Writer, tasks []*task.Task) error
+}
+
+// Name implements storage.Exporter
+func (e exporterFunc) Name() string { return e.name }
+
+// Export implements storage.Exporter
+func (e exporterFunc) Export(w io.Writer, tasks []*task.Task) error {
+	return e.write(w, tasks)
+}
+
+// exporters returns the export formats, bound to tm's settings and opts.
+// A new format only needs a write function and a line here.
+func (tm *TaskManager) exporters(opts ExportOptions) *storage.ExporterRegistry {
+	withOpts := func(write func(io.Writer, []*task.Task, ExportOptions) error) func(io.Writer, []*task.Task) error {
+		return func(w io.Writer, tasks []*task.Task) error { return write(w, tasks, opts) }
+	}
+
+	r := storage.NewExporterRegistry()
+	r.Register(exporterFunc{"json", withOpts(tm.writeJSONExport)})
+	r.Register(exporterFunc{"jsonl", writeJSONL})
+	r.Register(exporterFunc{"csv", withOpts(tm.writeCSV)})
+	r.Register(exporterFunc{"markdown", withOpts(tm.writeMarkdown)}, "md")
+	r.Register(exporterFunc{"ics", writeICS})
+	r.Register(exporterFunc{"html", withOpts(tm.writeHTML)})
+	r.Register(exporterFunc{"yaml", writeYAML}, "yml")
+	return r
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerExporters(t *testing.T) {
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	now := time.Now()
+	tasks := []*task.Task{
+		{ID: "a", Title: "Plan", Priority: task.High, CreatedAt: now, UpdatedAt: now},
+		{ID: "b", Title: "Ship", Completed: true, CompletedAt: now, CreatedAt: now, UpdatedAt: now},
+	}
+
+	registry := tm.exporters(ExportOptions{})
+	want := []string{"csv", "html", "ics", "json", "jsonl", "markdown", "yaml"}
+	if got := registry.Formats(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected formats %v, got %v", want, got)
+	}
+
+	// Every format writes straight into a buffer, aliases included
+	for _, format := range append(want, "md", "YML") {
+		e, err := registry.Lookup(format)
+		if err != nil {
+			t.Fatalf("Unexpected error looking up %s: %v", format, err)
+		}
+		var buf bytes.Buffer
+		if err := e.Export(&buf, tasks); err != nil {
+			t.Fatalf("Unexpected error exporting %s: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), "Ship") {
+			t.Errorf("Expected %s export to contain the tasks, got:\n%s", format, buf.String())
+		}
+	}
+
+	// Options are bound when the registry is built
+	e, _ := tm.exporters(ExportOptions{Summary: true}).Lookup("csv")
+	var buf bytes.Buffer
+	if err := e.Export(&buf, tasks); err != nil {
+		t.Fatalf("Unexpected error exporting CSV: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "# total=2 completed=1") {
+		t.Errorf("Expected a summary line, got:\n%s", buf.String())
+	}
+
+	err := tm.exportFormat(tasks, "pdf", filepath.Join(t.TempDir(), "tasks.pdf"), ExportOptions{})
+	if err == nil || !strings.Contains(err.Error(), "unsupported export format: pdf") {
+		t.Errorf("Expected an unsupported format error, got %v", err)
+	}
+}
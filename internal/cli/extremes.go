@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// findExtremes returns the pending task created longest ago and the
+// completed task that took longest from creation to completion. Either is
+// nil when no task qualifies; tasks missing the timestamps are skipped.
+func findExtremes(tasks []*task.Task) (oldestPending, slowestCompleted *task.Task) {
+	var slowest time.Duration
+	for _, t := range tasks {
+		if t.CreatedAt.IsZero() {
+			continue
+		}
+		if !t.Completed {
+			if oldestPending == nil || t.CreatedAt.Before(oldestPending.CreatedAt) {
+				oldestPending = t
+			}
+			continue
+		}
+		if t.CompletedAt.IsZero() {
+			continue
+		}
+		if took := t.CompletedAt.Sub(t.CreatedAt); slowestCompleted == nil || took > slowest {
+			slowestCompleted, slowest = t, took
+		}
+	}
+	return oldestPending, slowestCompleted
+}
+
+// Extremes shows the oldest pending task and the slowest completed one
+func (tm *TaskManager) Extremes(ctx context.Context) error {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	oldest, slowest := findExtremes(tasks)
+	now := time.Now()
+
+	fmt.Fprintf(tm.out, "\n%s Task Extremes\n", tm.Icons().Stats)
+	fmt.Fprintln(tm.out, strings.Repeat("=", 30))
+
+	fmt.Fprintln(tm.out, "Oldest pending task:")
+	if oldest == nil {
+		fmt.Fprintln(tm.out, "  none")
+	} else {
+		fmt.Fprintf(tm.out, "  %s (%s), open for %s\n", oldest.Title, oldest.ID, formatAge(now.Sub(oldest.CreatedAt)))
+	}
+
+	fmt.Fprintln(tm.out, "Longest to complete:")
+	if slowest == nil {
+		fmt.Fprintln(tm.out, "  none")
+	} else {
+		fmt.Fprintf(tm.out, "  %s (%s), took %s\n", slowest.Title, slowest.ID, formatAge(slowest.CompletedAt.Sub(slowest.CreatedAt)))
+	}
+	return nil
+}
+
+// formatAge renders a duration in whole days, or hours and minutes when
+// under a day
+func formatAge(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return formatDays(d)
+	}
+	return d.Truncate(time.Minute).String()
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+func TestFindExtremes(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	tasks := []*task.Task{
+		{ID: "recent", Title: "Recent", CreatedAt: base.AddDate(0, 2, 0)},
+		{ID: "ancient", Title: "Ancient", CreatedAt: base},
+		{ID: "quick", Title: "Quick", Completed: true, CreatedAt: base.AddDate(-1, 0, 0), CompletedAt: base.AddDate(-1, 0, 1)},
+		{ID: "slog", Title: "Slog", Completed: true, CreatedAt: base.AddDate(0, 1, 0), CompletedAt: base.AddDate(0, 3, 0)},
+		{ID: "undated", Title: "Undated", Completed: true, CreatedAt: base.AddDate(-2, 0, 0)},
+	}
+
+	oldest, slowest := findExtremes(tasks)
+	if oldest == nil || oldest.ID != "ancient" {
+		t.Errorf("Expected oldest pending task ancient, got %+v", oldest)
+	}
+	// slog was created later than quick but took two months
+	if slowest == nil || slowest.ID != "slog" {
+		t.Errorf("Expected slowest completed task slog, got %+v", slowest)
+	}
+
+	if oldest, slowest := findExtremes(nil); oldest != nil || slowest != nil {
+		t.Errorf("Expected no extremes for no tasks, got %+v, %+v", oldest, slowest)
+	}
+}
@@ -0,0 +1,55 @@
+package cli
+
+import "strings"
+
+// ANSI escape sequences used to highlight search matches
+const (
+	ansiInverse = "\x1b[7m"
+	ansiReset   = "\x1b[0m"
+)
+
+// highlight wraps every case-insensitive occurrence of term in *markers*
+func highlight(text, term string) string {
+	return highlightWith(text, term, "*", "*")
+}
+
+// highlightANSI shows every case-insensitive occurrence of term in inverse video
+func highlightANSI(text, term string) string {
+	return highlightWith(text, term, ansiInverse, ansiReset)
+}
+
+// highlightWith surrounds each case-insensitive match of term with open and close
+func highlightWith(text, term, open, close string) string {
+	if term == "" {
+		return text
+	}
+
+	lowerText := strings.ToLower(text)
+	lowerTerm := strings.ToLower(term)
+
+	// Lowercasing can change byte lengths for some runes; skip rather than mis-slice
+	if len(lowerText) != len(text) || len(lowerTerm) != len(term) {
+		return text
+	}
+
+	var b strings.Builder
+	rest := 0
+	for {
+		idx := strings.Index(lowerText[rest:], lowerTerm)
+		if idx == -1 {
+			break
+		}
+		start := rest + idx
+		end := start + len(term)
+		b.WriteString(text[rest:start])
+		b.WriteString(open)
+		b.WriteString(text[start:end])
+		b.WriteString(close)
+		rest = end
+	}
+	if rest == 0 {
+		return text
+	}
+	b.WriteString(text[rest:])
+	return b.String()
+}
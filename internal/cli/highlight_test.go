@@ -0,0 +1,34 @@
+package cli
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		term string
+		want string
+	}{
+		{"single match", "Learn Go basics", "go", "Learn *Go* basics"},
+		{"case insensitive", "GO go Go", "gO", "*GO* *go* *Go*"},
+		{"no match", "Write tests", "deploy", "Write tests"},
+		{"empty term", "Write tests", "", "Write tests"},
+		{"whole string", "urgent", "URGENT", "*urgent*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.text, tt.term); got != tt.want {
+				t.Errorf("highlight(%q, %q) = %q, want %q", tt.text, tt.term, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightANSI(t *testing.T) {
+	got := highlightANSI("Learn Go", "go")
+	want := "Learn \x1b[7mGo\x1b[0m"
+	if got != want {
+		t.Errorf("highlightANSI = %q, want %q", got, want)
+	}
+}
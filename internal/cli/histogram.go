@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// HistogramBucket counts tasks whose timestamp falls in [Start, Start+period)
+type HistogramBucket struct {
+	Start time.Time
+	Count int
+}
+
+// histogramBarWidth is the length of the longest bar in the chart
+const histogramBarWidth = 40
+
+// buildHistogram counts tasks per day or week over the n periods ending at
+// now, keyed by their creation or completion time
+func buildHistogram(tasks []*task.Task, field, by string, now time.Time, n int) ([]HistogramBucket, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("bucket count must be positive: %d", n)
+	}
+
+	var timestamp func(t *task.Task) time.Time
+	switch field {
+	case "created":
+		timestamp = func(t *task.Task) time.Time { return t.CreatedAt }
+	case "completed":
+		timestamp = func(t *task.Task) time.Time { return t.CompletedAt }
+	default:
+		return nil, fmt.Errorf("invalid histogram field: %q. Use: created, completed", field)
+	}
+
+	// Align the current bucket to the start of the day or ISO week
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	days := 1
+	switch by {
+	case "day":
+	case "week":
+		days = 7
+		offset := (int(start.Weekday()) + 6) % 7 // days since Monday
+		start = start.AddDate(0, 0, -offset)
+	default:
+		return nil, fmt.Errorf("invalid histogram period: %q. Use: day, week", by)
+	}
+
+	buckets := make([]HistogramBucket, n)
+	for i := range buckets {
+		buckets[i].Start = start.AddDate(0, 0, -days*(n-1-i))
+	}
+
+	for _, t := range tasks {
+		ts := timestamp(t)
+		if ts.IsZero() {
+			continue
+		}
+		for i := range buckets {
+			end := buckets[i].Start.AddDate(0, 0, days)
+			if !ts.Before(buckets[i].Start) && ts.Before(end) {
+				buckets[i].Count++
+				break
+			}
+		}
+	}
+
+	return buckets, nil
+}
+
+// Histogram prints a text bar chart of tasks created or completed per period
+func (tm *TaskManager) Histogram(ctx context.Context, field, by string, n int) error {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	buckets, err := buildHistogram(tasks, field, by, time.Now(), n)
+	if err != nil {
+		return err
+	}
+
+	maxCount := 0
+	for _, b := range buckets {
+		if b.Count > maxCount {
+			maxCount = b.Count
+		}
+	}
+
+	fmt.Fprintf(tm.out, "\n%s Tasks %s per %s\n", tm.Icons().Histogram, field, by)
+	fmt.Fprintln(tm.out, strings.Repeat("=", 30))
+	for _, b := range buckets {
+		bar := 0
+		if maxCount > 0 {
+			bar = b.Count * histogramBarWidth / maxCount
+		}
+		fmt.Fprintf(tm.out, "%s | %s %d\n", b.Start.Format("2006-01-02"), strings.Repeat("█", bar), b.Count)
+	}
+	fmt.Fprintln(tm.out)
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+func TestBuildHistogramDaily(t *testing.T) {
+	now := time.Date(2025, 6, 15, 18, 30, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time {
+		return time.Date(2025, 6, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tasks := []*task.Task{
+		{ID: "1", CreatedAt: at(15, 9), CompletedAt: at(15, 17), Completed: true},
+		{ID: "2", CreatedAt: at(15, 0)},
+		{ID: "3", CreatedAt: at(14, 23), CompletedAt: at(15, 1), Completed: true},
+		{ID: "4", CreatedAt: at(13, 12)},
+		{ID: "5", CreatedAt: at(1, 12)}, // outside the range
+	}
+
+	buckets, err := buildHistogram(tasks, "created", "day", now, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error building histogram: %v", err)
+	}
+
+	expected := []struct {
+		start time.Time
+		count int
+	}{
+		{at(13, 0), 1},
+		{at(14, 0), 1},
+		{at(15, 0), 2},
+	}
+
+	if len(buckets) != len(expected) {
+		t.Fatalf("Expected %d buckets, got %d", len(expected), len(buckets))
+	}
+	for i, want := range expected {
+		if !buckets[i].Start.Equal(want.start) {
+			t.Errorf("Bucket %d: expected start %v, got %v", i, want.start, buckets[i].Start)
+		}
+		if buckets[i].Count != want.count {
+			t.Errorf("Bucket %d: expected count %d, got %d", i, want.count, buckets[i].Count)
+		}
+	}
+
+	completed, err := buildHistogram(tasks, "completed", "day", now, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error building histogram: %v", err)
+	}
+	if completed[2].Count != 2 || completed[0].Count != 0 || completed[1].Count != 0 {
+		t.Errorf("Expected both completions on the last day, got %+v", completed)
+	}
+}
+
+func TestBuildHistogramWeekly(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC) // a Sunday
+
+	tasks := []*task.Task{
+		{ID: "1", CreatedAt: time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC)},  // Monday, this week
+		{ID: "2", CreatedAt: time.Date(2025, 6, 8, 23, 0, 0, 0, time.UTC)}, // Sunday, last week
+	}
+
+	buckets, err := buildHistogram(tasks, "created", "week", now, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error building histogram: %v", err)
+	}
+	if !buckets[1].Start.Equal(time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected current week to start on Monday, got %v", buckets[1].Start)
+	}
+	if buckets[0].Count != 1 || buckets[1].Count != 1 {
+		t.Errorf("Expected one task per week, got %+v", buckets)
+	}
+}
+
+func TestBuildHistogramInvalidInput(t *testing.T) {
+	now := time.Now()
+	if _, err := buildHistogram(nil, "updated", "day", now, 7); err == nil {
+		t.Error("Expected error for invalid field")
+	}
+	if _, err := buildHistogram(nil, "created", "month", now, 7); err == nil {
+		t.Error("Expected error for invalid period")
+	}
+	if _, err := buildHistogram(nil, "created", "day", now, 0); err == nil {
+		t.Error("Expected error for zero buckets")
+	}
+}
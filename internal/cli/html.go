@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// htmlReport is the self-contained HTML export. html/template escapes every
+// value, so titles and descriptions may safely contain markup characters.
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Task Export</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.5rem; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+.badge { border-radius: 0.75rem; color: #fff; font-size: 0.8rem; padding: 0.1rem 0.6rem; white-space: nowrap; }
+.priority-urgent { background: #b71c1c; }
+.priority-high { background: #e65100; }
+.priority-medium { background: #f9a825; color: #222; }
+.priority-low { background: #2e7d32; }
+tr.overdue td { background: #fdecea; }
+tr.overdue .due { color: #b71c1c; font-weight: bold; }
+.tag { background: #eee; border-radius: 0.25rem; font-size: 0.8rem; margin-right: 0.25rem; padding: 0 0.3rem; }
+.muted { color: #777; }
+</style>
+</head>
+<body>
+<h1>Task Export</h1>
+<p class="muted">Generated on {{.Generated}}</p>
+<p><strong>Total:</strong> {{.Stats.Total}} | <strong>Completed:</strong> {{.Stats.Completed}} | <strong>Overdue:</strong> {{.Stats.Overdue}} | <strong>Progress:</strong> {{.Stats.CompletionPercent}}%</p>
+{{- if not .Sections}}
+<p class="muted">No tasks.</p>
+{{- end}}
+{{- range .Sections}}
+<h2>{{.Title}} ({{len .Rows}})</h2>
+{{- if .Rows}}
+<table>
+<thead><tr><th>Task</th><th>Priority</th><th>Due</th><th>Tags</th><th>ID</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr{{if .Overdue}} class="overdue"{{end}}>
+<td><strong>{{.Title}}</strong>{{if .Description}}<br><span class="muted">{{.Description}}</span>{{end}}</td>
+<td><span class="badge priority-{{.PriorityClass}}">{{.Priority}}</span></td>
+<td class="due">{{.Due}}{{if .Overdue}} (overdue){{end}}</td>
+<td>{{range .Tags}}<span class="tag">{{.}}</span>{{end}}</td>
+<td class="muted">{{.ID}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p class="muted">No tasks.</p>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// htmlRow is one task as shown in the HTML report
+type htmlRow struct {
+	ID            string
+	Title         string
+	Description   string
+	Priority      string
+	PriorityClass string
+	Due           string
+	Tags          []string
+	Overdue       bool
+}
+
+// htmlSection is a titled group of rows
+type htmlSection struct {
+	Title string
+	Rows  []htmlRow
+}
+
+// writeHTML exports tasks to a standalone HTML report grouped by status
+func (tm *TaskManager) writeHTML(w io.Writer, tasks []*task.Task, opts ExportOptions) error {
+	now := time.Now()
+	pending := htmlSection{Title: "Pending Tasks"}
+	completed := htmlSection{Title: "Completed Tasks"}
+	for _, t := range tasks {
+		row := htmlRow{
+			ID:            t.ID,
+			Title:         t.Title,
+			Description:   t.Description,
+			Priority:      tm.config.PriorityLabel(t.Priority),
+			PriorityClass: strings.ToLower(t.Priority.String()),
+			Tags:          t.Tags,
+			Overdue:       t.IsOverdueAt(now),
+		}
+		if !t.DueDate.IsZero() {
+			row.Due = t.DueDate.Format("2006-01-02")
+		}
+		if t.Completed {
+			completed.Rows = append(completed.Rows, row)
+		} else {
+			pending.Rows = append(pending.Rows, row)
+		}
+	}
+
+	var sections []htmlSection
+	for _, s := range []htmlSection{pending, completed} {
+		if len(s.Rows) > 0 || opts.KeepEmptySections {
+			sections = append(sections, s)
+		}
+	}
+
+	err := htmlReport.Execute(w, struct {
+		Generated string
+		Stats     StatsResult
+		Sections  []htmlSection
+	}{now.Format("2006-01-02 15:04:05"), computeStats(tasks, tm.dueSoon()), sections})
+	if err != nil {
+		return fmt.Errorf("failed to write HTML: %w", err)
+	}
+	return nil
+}
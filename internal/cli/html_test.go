@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestExportHTML(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-html-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "xss", Title: "<script>alert(1)</script>", Description: "a < b & c", Priority: task.Urgent, DueDate: now.Add(-48 * time.Hour), Tags: []string{"<b>"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "done", Title: "Shipped", Priority: task.Low, Completed: true, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	filename := filepath.Join(tempDir, "report.html")
+	if err := tm.ExportTasks(ctx, "html", filename, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting HTML: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error reading export: %v", err)
+	}
+	out := string(data)
+
+	if strings.Contains(out, "<script>") || strings.Contains(out, "<b>") {
+		t.Error("Expected user content to be escaped")
+	}
+	for _, want := range []string{"&lt;script&gt;alert(1)&lt;/script&gt;", "a &lt; b &amp; c", `class="overdue"`, "priority-urgent", "Pending Tasks (1)", "Completed Tasks (1)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+
+	// Parse leniently as HTML and check every element is closed
+	decoder := xml.NewDecoder(strings.NewReader(out))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+	depth, rows := 0, 0
+	for {
+		tok, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected valid HTML, got parse error: %v", err)
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if el.Name.Local == "tr" {
+				rows++
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+	if depth != 0 {
+		t.Errorf("Expected balanced elements, got depth %d", depth)
+	}
+	// One header and one task row per section
+	if rows != 4 {
+		t.Errorf("Expected 4 table rows, got %d", rows)
+	}
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"sort"
+
+	"go-fun/internal/task"
+)
+
+// Icons holds every glyph the CLI decorates output with, so terminals that
+// render emoji poorly can switch to another set
+type Icons struct {
+	// Task status
+	Pending    string
+	Done       string
+	Incomplete string // unchecked box in markdown exports
+	Overdue    string
+	DueToday   string
+	DueSoon    string
+
+	// Priority levels
+	Urgent string
+	High   string
+	Medium string
+	Low    string
+
+	// Detail line prefixes
+	Description string
+	Priority    string
+	Tags        string
+	Due         string
+	Repeats     string
+	Estimate    string
+	ID          string
+	Created     string
+	Updated     string
+	Finished    string
+	DependsOn   string
+	Related     string
+	Subtasks    string
+	Notes       string
+
+	// Headers and notices
+	List      string
+	Details   string
+	Stats     string
+	Histogram string
+	Success   string
+	Starting  string
+	Deleted   string
+	Archived  string
+	Celebrate string
+}
+
+// iconSets are the presets selectable through the icon_set config key
+var iconSets = map[string]Icons{
+	"emoji": {
+		Pending: "⏳", Done: "✅", Incomplete: "❌", Overdue: "🚨", DueToday: "📅", DueSoon: "⏰",
+		Urgent: "🔥", High: "🔴", Medium: "🟡", Low: "🟢",
+		Description: "📝", Priority: "🎯", Tags: "🏷️ ", Due: "⏰", Repeats: "🔁", Estimate: "⌛", ID: "🆔",
+		Created: "📅", Updated: "🔄", Finished: "🏁", DependsOn: "⛔", Related: "🔗", Subtasks: "🧩", Notes: "🗒️ ",
+		List: "📋", Details: "📝", Stats: "📊", Histogram: "📈", Success: "✅", Starting: "🚀",
+		Deleted: "🗑️ ", Archived: "📦", Celebrate: "🎉",
+	},
+	"ascii": {
+		Pending: "[ ]", Done: "[x]", Incomplete: "[ ]", Overdue: "[!]", DueToday: "[*]", DueSoon: "[~]",
+		Urgent: "(U)", High: "(H)", Medium: "(M)", Low: "(L)",
+		Description: "-", Priority: "*", Tags: "#", Due: "@", Repeats: "~", Estimate: "%", ID: "id",
+		Created: "+", Updated: "~", Finished: "x", DependsOn: "!", Related: "&", Subtasks: ">", Notes: "=",
+		List: "==", Details: "==", Stats: "==", Histogram: "==", Success: "OK", Starting: ">>",
+		Deleted: "--", Archived: "->", Celebrate: ":)",
+	},
+	// nerdfont uses Font Awesome glyphs from a patched Nerd Font
+	"nerdfont": {
+		Pending: "\uf10c", Done: "\uf00c", Incomplete: "\uf00d", Overdue: "\uf071", DueToday: "\uf073", DueSoon: "\uf017",
+		Urgent: "\uf0e7", High: "\uf062", Medium: "\uf068", Low: "\uf063",
+		Description: "\uf0f6", Priority: "\uf140", Tags: "\uf02c", Due: "\uf017", Repeats: "\uf021", Estimate: "\uf254", ID: "\uf2c2",
+		Created: "\uf271", Updated: "\uf040", Finished: "\uf11e", DependsOn: "\uf05e", Related: "\uf0c1", Subtasks: "\uf0e8", Notes: "\uf249",
+		List: "\uf03a", Details: "\uf0f6", Stats: "\uf080", Histogram: "\uf080", Success: "\uf00c", Starting: "\uf135",
+		Deleted: "\uf1f8", Archived: "\uf187", Celebrate: "\uf005",
+	},
+}
+
+// defaultIconSet is used when the config names no set or an unknown one
+const defaultIconSet = "emoji"
+
+// IconSetNames lists the available icon presets
+func IconSetNames() []string {
+	names := make([]string, 0, len(iconSets))
+	for name := range iconSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Icons returns the icon set selected in the config
+func (tm *TaskManager) Icons() Icons {
+	if set, ok := iconSets[tm.config.IconSet]; ok {
+		return set
+	}
+	return iconSets[defaultIconSet]
+}
+
+// PriorityIcon returns the glyph for a priority level
+func (i Icons) PriorityIcon(p task.Priority) string {
+	switch p {
+	case task.Urgent:
+		return i.Urgent
+	case task.High:
+		return i.High
+	case task.Medium:
+		return i.Medium
+	case task.Low:
+		return i.Low
+	}
+	return ""
+}
+
+// StatusIcon returns the glyph for a task's completion and due state
+func (i Icons) StatusIcon(t *task.Task) string {
+	switch {
+	case t.Completed:
+		return i.Done
+	case t.IsOverdue():
+		return i.Overdue
+	case t.IsDueToday():
+		return i.DueToday
+	case t.IsDueSoon():
+		return i.DueSoon
+	}
+	return i.Pending
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+	"unicode"
+
+	"go-fun/internal/config"
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestASCIIIconSetListOutput(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetConfig(&config.Config{IconSet: "ascii"})
+	ctx := context.Background()
+
+	now := time.Now()
+	tasks := []*task.Task{
+		{ID: "overdue", Title: "Overdue", Description: "Late", Priority: task.High, DueDate: now.Add(-48 * time.Hour), Tags: []string{"work"}, CreatedAt: now, UpdatedAt: now.Add(time.Minute)},
+		{ID: "today", Title: "Today", Priority: task.Medium, DueDate: now.Add(time.Minute), CreatedAt: now, UpdatedAt: now},
+		{ID: "soon", Title: "Soon", Priority: task.Low, DueDate: now.Add(72 * time.Hour), Recurrence: &task.Recurrence{Interval: task.Weekly}, CreatedAt: now, UpdatedAt: now},
+		{ID: "done", Title: "Done", Priority: task.Low, Completed: true, CompletedAt: now, DependsOn: []string{"today"}, CreatedAt: now, UpdatedAt: now},
+	}
+	for _, tt := range tasks {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+
+	if err := tm.List(ctx, ListOptions{ShowCompleted: true}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+	if err := tm.Show(ctx, "done"); err != nil {
+		t.Fatalf("Unexpected error showing task: %v", err)
+	}
+	if err := tm.Stats(ctx); err != nil {
+		t.Fatalf("Unexpected error showing stats: %v", err)
+	}
+
+	for _, r := range out.String() {
+		if r > unicode.MaxASCII {
+			t.Fatalf("Expected ASCII-only output, found %q in:\n%s", r, out.String())
+		}
+	}
+}
+
+func TestIconSetsComplete(t *testing.T) {
+	for _, name := range IconSetNames() {
+		set := iconSets[name]
+		for p := task.Low; p <= task.High; p++ {
+			if set.PriorityIcon(p) == "" {
+				t.Errorf("Icon set %q has no icon for priority %s", name, p)
+			}
+		}
+		if set.Pending == "" || set.Done == "" || set.Success == "" {
+			t.Errorf("Icon set %q is missing status icons", name)
+		}
+	}
+
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	tm.SetConfig(&config.Config{IconSet: "unknown"})
+	if tm.Icons() != iconSets[defaultIconSet] {
+		t.Error("Expected unknown icon set to fall back to the default")
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-fun/internal/task"
+)
+
+// icsTimeFormat is the UTC date-time form used for iCalendar properties
+const icsTimeFormat = "20060102T150405Z"
+
+// icsPriority maps task priorities onto iCalendar's 1 (highest) to 9 scale
+var icsPriority = map[task.Priority]int{
+	task.Urgent: 1,
+	task.High:   3,
+	task.Medium: 5,
+	task.Low:    9,
+}
+
+// icsEscape escapes text for an iCalendar property value
+var icsEscape = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// writeICS writes tasks as a VCALENDAR with one VTODO per task
+func writeICS(w io.Writer, tasks []*task.Task) error {
+	bw := bufio.NewWriter(w)
+	line := func(format string, args ...any) {
+		writeICSLine(bw, fmt.Sprintf(format, args...))
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//go-fun//tasks//EN")
+	for _, t := range tasks {
+		line("BEGIN:VTODO")
+		line("UID:%s@go-fun", t.ID)
+		line("DTSTAMP:%s", t.UpdatedAt.UTC().Format(icsTimeFormat))
+		line("CREATED:%s", t.CreatedAt.UTC().Format(icsTimeFormat))
+		line("SUMMARY:%s", icsEscape.Replace(t.Title))
+		if t.Description != "" {
+			line("DESCRIPTION:%s", icsEscape.Replace(t.Description))
+		}
+		if !t.DueDate.IsZero() {
+			line("DUE:%s", t.DueDate.UTC().Format(icsTimeFormat))
+		}
+		if p, ok := icsPriority[t.Priority]; ok {
+			line("PRIORITY:%d", p)
+		}
+		if len(t.Tags) > 0 {
+			tags := make([]string, len(t.Tags))
+			for i, tag := range t.Tags {
+				tags[i] = icsEscape.Replace(tag)
+			}
+			line("CATEGORIES:%s", strings.Join(tags, ","))
+		}
+		if t.Completed {
+			line("STATUS:COMPLETED")
+			if !t.CompletedAt.IsZero() {
+				line("COMPLETED:%s", t.CompletedAt.UTC().Format(icsTimeFormat))
+			}
+		} else {
+			line("STATUS:NEEDS-ACTION")
+		}
+		line("END:VTODO")
+	}
+	line("END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// writeICSLine writes one content line, folding it at 75 octets as RFC 5545
+// requires without splitting a UTF-8 sequence
+func writeICSLine(w *bufio.Writer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, leaving 74 octets of content
+		limit = 74
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
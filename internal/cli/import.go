@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// Merge strategies for ImportTasks when an imported ID already exists
+const (
+	MergeSkip      = "skip"      // keep the existing task
+	MergeOverwrite = "overwrite" // replace it with the imported one
+	MergeRename    = "rename"    // add the imported task under a fresh ID
+)
+
+// csvDateFormat is the timestamp layout written by the CSV exporter
+const csvDateFormat = "2006-01-02 15:04"
+
+// ImportResult counts what happened to each imported task
+type ImportResult struct {
+	Added       int
+	Overwritten int
+	Skipped     int
+	Failed      int
+}
+
+// ImportTasks reads tasks written by the json, csv or yaml exporter and merges them
+// into the store in a single save. Tasks failing validation are counted as
+// failed and left out; ID clashes are resolved by mergeStrategy.
+func (tm *TaskManager) ImportTasks(ctx context.Context, format, filename, mergeStrategy string) (result ImportResult, err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	switch mergeStrategy {
+	case MergeSkip, MergeOverwrite, MergeRename:
+	default:
+		return result, fmt.Errorf("invalid merge strategy: %q. Use: skip, overwrite, rename", mergeStrategy)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return result, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	var imported []*task.Task
+	switch strings.ToLower(format) {
+	case "json":
+		imported, err = parseJSONImport(data)
+	case "csv":
+		imported, err = tm.parseCSVImport(data, &result)
+	case "yaml", "yml":
+		imported, err = parseYAMLImport(data)
+	default:
+		return result, fmt.Errorf("unsupported import format: %s", format)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	index := make(map[string]int, len(tasks))
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+
+	var changed [][2]*task.Task
+	for _, t := range imported {
+		t.Tags = task.NormalizeTags(t.Tags)
+		if err := task.ValidateTags(t.Tags); err != nil {
+			result.Failed++
+			continue
+		}
+		if err := t.Validate(); err != nil {
+			result.Failed++
+			continue
+		}
+
+		i, clash := index[t.ID]
+		switch {
+		case !clash:
+			index[t.ID] = len(tasks)
+			tasks = append(tasks, t)
+			changed = append(changed, [2]*task.Task{nil, t})
+			result.Added++
+		case mergeStrategy == MergeSkip:
+			result.Skipped++
+		case mergeStrategy == MergeOverwrite:
+			changed = append(changed, [2]*task.Task{tasks[i], t})
+			tasks[i] = t
+			result.Overwritten++
+		case mergeStrategy == MergeRename:
+			t.ID = task.NewID()
+			index[t.ID] = len(tasks)
+			tasks = append(tasks, t)
+			changed = append(changed, [2]*task.Task{nil, t})
+			result.Added++
+		}
+	}
+
+	if len(changed) > 0 {
+		if err := tm.storage.Save(ctx, tasks); err != nil {
+			return result, fmt.Errorf("failed to save tasks: %w", err)
+		}
+		if err := tm.logChanges(changed); err != nil {
+			return result, err
+		}
+	}
+
+	fmt.Fprintf(tm.out, "%s Imported %s: %d added, %d overwritten, %d skipped, %d failed\n",
+		tm.Icons().Success, filename, result.Added, result.Overwritten, result.Skipped, result.Failed)
+	return result, nil
+}
+
+// parseJSONImport accepts a task array or the {summary, tasks} envelope
+func parseJSONImport(data []byte) ([]*task.Task, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var envelope struct {
+			Tasks []*task.Task `json:"tasks"`
+		}
+		if err := json.Unmarshal(trimmed, &envelope); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		return envelope.Tasks, nil
+	}
+
+	var tasks []*task.Task
+	if err := json.Unmarshal(trimmed, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return tasks, nil
+}
+
+// parseCSVImport reads the CSV exporter's columns, skipping the summary
+// comment. Rows that cannot be parsed are counted as failed.
+func (tm *TaskManager) parseCSVImport(data []byte, result *ImportResult) ([]*task.Task, error) {
+	var tasks []*task.Task
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = -1 // checked per row, old exports lack Tags
+	header := true
+	for {
+		fields, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Failed++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if header {
+			header = false
+			continue
+		}
+
+		t, err := tm.parseCSVRow(fields)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, nil
+}
+
+// parseCSVPriority reads a priority name or label, or the number written by
+// an export with --priority-numeric
+func (tm *TaskManager) parseCSVPriority(s string) (task.Priority, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		p := task.Priority(n - 1)
+		if p < task.Low || p > task.Urgent {
+			return task.Medium, fmt.Errorf("invalid numeric priority: %d", n)
+		}
+		return p, nil
+	}
+	return tm.config.ParsePriority(s)
+}
+
+// parseCSVRow parses one exported row:
+// ID,Title,Description,Priority,Completed,Due Date,Created,Updated,Tags
+// Exports from before the Tags column have 8 columns and are also accepted.
+func (tm *TaskManager) parseCSVRow(fields []string) (*task.Task, error) {
+	if len(fields) != 8 && len(fields) != 9 {
+		return nil, fmt.Errorf("expected 9 columns, got %d", len(fields))
+	}
+
+	priority, err := tm.parseCSVPriority(fields[3])
+	if err != nil {
+		return nil, err
+	}
+	completed, err := strconv.ParseBool(fields[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid completed value: %q", fields[4])
+	}
+
+	var dates [3]time.Time
+	for i, field := range fields[5:8] {
+		if field == "" {
+			continue
+		}
+		d, err := time.ParseInLocation(csvDateFormat, field, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date: %q", field)
+		}
+		dates[i] = d
+	}
+
+	t := &task.Task{
+		ID:          fields[0],
+		Title:       fields[1],
+		Description: fields[2],
+		Priority:    priority,
+		Completed:   completed,
+		DueDate:     dates[0],
+		CreatedAt:   dates[1],
+		UpdatedAt:   dates[2],
+	}
+	if len(fields) == 9 && fields[8] != "" {
+		t.Tags = strings.Split(fields[8], ";")
+	}
+	if t.Completed {
+		// The CSV has no completion time; the last update is the best guess
+		t.CompletedAt = t.UpdatedAt
+		t.WasLate = !t.DueDate.IsZero() && t.CompletedAt.After(t.DueDate)
+	}
+	if t.ID == "" {
+		t.ID = task.NewID()
+	}
+	return t, nil
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-fun/internal/config"
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+// importFixture exports two tasks in format and returns the file path
+func importFixture(t *testing.T, format string) (string, []*task.Task) {
+	t.Helper()
+
+	tempDir, err := os.MkdirTemp("", "go-fun-import-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+
+	created := time.Date(2025, 6, 1, 9, 30, 0, 0, time.Local)
+	tasks := []*task.Task{
+		{ID: "keep-1", Title: "Buy milk, eggs", Description: "Corner shop, not the mall", Priority: task.High,
+			Tags: []string{"errands", "home"}, DueDate: created.AddDate(0, 0, 3), CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+		{ID: "keep-2", Title: "File taxes", Priority: task.Low, Completed: true,
+			CreatedAt: created, UpdatedAt: created.Add(2 * time.Hour), CompletedAt: created.Add(2 * time.Hour)},
+	}
+
+	source := NewTaskManager(storage.NewInMemoryStorage())
+	source.SetOutput(&bytes.Buffer{})
+	for _, tt := range tasks {
+		if err := source.AddTask(context.Background(), tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	path := filepath.Join(tempDir, "tasks."+format)
+	if err := source.ExportTasks(context.Background(), format, path, ExportOptions{Summary: true}); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+	return path, tasks
+}
+
+func TestTaskManagerImportRoundTrip(t *testing.T) {
+	for _, format := range []string{"json", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			path, want := importFixture(t, format)
+
+			store := storage.NewInMemoryStorage()
+			tm := NewTaskManager(store)
+			tm.SetOutput(&bytes.Buffer{})
+			ctx := context.Background()
+
+			result, err := tm.ImportTasks(ctx, format, path, MergeSkip)
+			if err != nil {
+				t.Fatalf("Unexpected error importing: %v", err)
+			}
+			if result.Added != 2 || result.Failed != 0 {
+				t.Fatalf("Unexpected result: %+v", result)
+			}
+
+			for _, w := range want {
+				got, err := store.GetByID(ctx, w.ID)
+				if err != nil {
+					t.Fatalf("Expected %s to be imported: %v", w.ID, err)
+				}
+				if got.Title != w.Title || got.Description != w.Description || got.Priority != w.Priority ||
+					got.Completed != w.Completed || !got.DueDate.Equal(w.DueDate) || !got.CreatedAt.Equal(w.CreatedAt) ||
+					!reflect.DeepEqual(got.Tags, w.Tags) {
+					t.Errorf("Task %s did not round-trip:\n got %+v\nwant %+v", w.ID, got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskManagerImportMergeStrategies(t *testing.T) {
+	path, _ := importFixture(t, "csv")
+	ctx := context.Background()
+
+	setup := func() (*storage.InMemoryStorage, *TaskManager) {
+		store := storage.NewInMemoryStorage()
+		tm := NewTaskManager(store)
+		tm.SetOutput(&bytes.Buffer{})
+		existing := &task.Task{ID: "keep-1", Title: "Existing", Priority: task.Medium, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+		if err := store.Add(ctx, existing); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+		return store, tm
+	}
+
+	store, tm := setup()
+	result, err := tm.ImportTasks(ctx, "csv", path, MergeSkip)
+	if err != nil || result.Added != 1 || result.Skipped != 1 {
+		t.Fatalf("skip: unexpected result %+v, %v", result, err)
+	}
+	if got, _ := store.GetByID(ctx, "keep-1"); got.Title != "Existing" {
+		t.Errorf("skip: expected existing task kept, got %q", got.Title)
+	}
+
+	store, tm = setup()
+	result, err = tm.ImportTasks(ctx, "csv", path, MergeOverwrite)
+	if err != nil || result.Added != 1 || result.Overwritten != 1 {
+		t.Fatalf("overwrite: unexpected result %+v, %v", result, err)
+	}
+	if got, _ := store.GetByID(ctx, "keep-1"); got.Title != "Buy milk, eggs" {
+		t.Errorf("overwrite: expected imported task, got %q", got.Title)
+	}
+
+	store, tm = setup()
+	result, err = tm.ImportTasks(ctx, "csv", path, MergeRename)
+	if err != nil || result.Added != 2 {
+		t.Fatalf("rename: unexpected result %+v, %v", result, err)
+	}
+	if tasks, _ := store.Load(ctx); len(tasks) != 3 {
+		t.Errorf("rename: expected 3 tasks, got %d", len(tasks))
+	}
+
+	if _, err := tm.ImportTasks(ctx, "csv", path, "merge"); err == nil {
+		t.Error("Expected error for an unknown merge strategy")
+	}
+}
+
+func TestTaskManagerImportCountsFailures(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-import-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "bad.csv")
+	content := "ID,Title,Description,Priority,Completed,Due Date,Created,Updated\n" +
+		"ok,Fine,,Medium,false,,2025-06-01 09:00,2025-06-01 09:00\n" +
+		"empty,,,Medium,false,,2025-06-01 09:00,2025-06-01 09:00\n" +
+		"short,Too few columns\n" +
+		"bad,Bad priority,,Urgentish,false,,2025-06-01 09:00,2025-06-01 09:00\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	tm.SetOutput(&bytes.Buffer{})
+	result, err := tm.ImportTasks(context.Background(), "csv", path, MergeSkip)
+	if err != nil {
+		t.Fatalf("Unexpected error importing: %v", err)
+	}
+	if result.Added != 1 || result.Failed != 3 {
+		t.Errorf("Expected 1 added and 3 failed, got %+v", result)
+	}
+}
+
+func TestTaskManagerImportCSVLabelWithComma(t *testing.T) {
+	cfg := config.Default()
+	cfg.SetPriorityLabel(task.High, "Now, please")
+
+	ctx := context.Background()
+	now := time.Now()
+	source := NewTaskManager(storage.NewInMemoryStorage())
+	source.SetConfig(cfg)
+	source.SetOutput(&bytes.Buffer{})
+	if err := source.AddTask(ctx, &task.Task{ID: "labelled", Title: "Labelled", Priority: task.High, CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "tasks.csv")
+	if err := source.ExportTasks(ctx, "csv", path, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading export: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"Now, please"`)) {
+		t.Errorf("Expected the label quoted as written, got:\n%s", data)
+	}
+
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetConfig(cfg)
+	tm.SetOutput(&bytes.Buffer{})
+	result, err := tm.ImportTasks(ctx, "csv", path, MergeSkip)
+	if err != nil {
+		t.Fatalf("Unexpected error importing: %v", err)
+	}
+	if result.Added != 1 || result.Failed != 0 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if got, err := store.GetByID(ctx, "labelled"); err != nil || got.Priority != task.High {
+		t.Errorf("Expected the label to import as High, got %v (err %v)", got, err)
+	}
+}
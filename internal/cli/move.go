@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+// MoveTo transfers a task from the active store to dest and returns its ID
+// there. The ID is kept unless dest already has a task with it, in which case
+// a fresh one is assigned. Dependencies, links and the parent refer to tasks
+// in the source store, so they are dropped from the moved copy. If removing the task from
+// the source fails, the copy is deleted from dest again.
+func (tm *TaskManager) MoveTo(ctx context.Context, dest storage.Storage, id string) (string, error) {
+	t, err := tm.storage.GetByID(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to get task: %w", err)
+	}
+
+	moved := *t
+	moved.DependsOn = nil
+	moved.RelatedTo = nil
+	moved.ParentID = ""
+	if _, err := dest.GetByID(ctx, moved.ID); err == nil {
+		moved.ID = task.NewID()
+	}
+
+	if err := dest.Add(ctx, &moved); err != nil {
+		return "", fmt.Errorf("failed to add task to destination: %w", err)
+	}
+
+	if err := tm.Delete(ctx, id); err != nil {
+		if rbErr := dest.Delete(ctx, moved.ID); rbErr != nil {
+			return "", fmt.Errorf("failed to remove task from source: %w (rollback also failed: %v)", err, rbErr)
+		}
+		return "", fmt.Errorf("failed to remove task from source: %w", err)
+	}
+
+	if moved.ID != id {
+		fmt.Fprintf(tm.out, "%s Moved %s (ID collided, now %s)\n", tm.Icons().Success, id, moved.ID)
+	} else {
+		fmt.Fprintf(tm.out, "%s Moved %s\n", tm.Icons().Success, id)
+	}
+	return moved.ID, nil
+}
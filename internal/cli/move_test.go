@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerMoveTo(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-move-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	source := storage.NewInMemoryStorage()
+	tm := NewTaskManager(source)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "test-1", Title: "Promote me", Tags: []string{"work"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "test-2", Title: "Collides", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := source.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	dest, err := storage.Open(filepath.Join(tempDir, "global.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error opening destination: %v", err)
+	}
+	if err := dest.Add(ctx, &task.Task{ID: "test-2", Title: "Already here", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error seeding destination: %v", err)
+	}
+
+	// ID is preserved when free
+	newID, err := tm.MoveTo(ctx, dest, "test-1")
+	if err != nil {
+		t.Fatalf("Unexpected error moving task: %v", err)
+	}
+	if newID != "test-1" {
+		t.Errorf("Expected ID to be preserved, got %s", newID)
+	}
+	moved, err := dest.GetByID(ctx, "test-1")
+	if err != nil {
+		t.Fatalf("Expected moved task in destination: %v", err)
+	}
+	if moved.Title != "Promote me" || len(moved.Tags) != 1 {
+		t.Errorf("Expected moved task fields to be kept, got %+v", moved)
+	}
+	if _, err := source.GetByID(ctx, "test-1"); err == nil {
+		t.Error("Expected task to be removed from source")
+	}
+
+	// Colliding ID gets a fresh one
+	newID, err = tm.MoveTo(ctx, dest, "test-2")
+	if err != nil {
+		t.Fatalf("Unexpected error moving colliding task: %v", err)
+	}
+	if newID == "test-2" {
+		t.Error("Expected a fresh ID on collision")
+	}
+	destTasks, _ := dest.Load(ctx)
+	if len(destTasks) != 3 {
+		t.Errorf("Expected 3 tasks in destination, got %d", len(destTasks))
+	}
+
+	if _, err := tm.MoveTo(ctx, dest, "missing"); err == nil {
+		t.Error("Expected error moving a missing task")
+	}
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+)
+
+// AddNote appends a dated note to a task
+func (tm *TaskManager) AddNote(ctx context.Context, id, text string) error {
+	t, err := tm.resolveID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	id = t.ID
+
+	before := snapshot(t)
+	if err := t.AddNote(text); err != nil {
+		return fmt.Errorf("invalid note: %w", err)
+	}
+
+	if err := tm.storage.Update(ctx, id, t); err != nil {
+		return err
+	}
+	if err := tm.logEvent("update", id, before, t); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(tm.out, "%s Note added to: %s\n", tm.Icons().Success, t.Title)
+	return nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerAddNote(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	base := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	if err := tm.AddTask(ctx, &task.Task{
+		ID: "report", Title: "Quarterly report", CreatedAt: base, UpdatedAt: base,
+		Notes: []task.Note{{Text: "Collected numbers", CreatedAt: base}},
+	}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	if err := tm.AddNote(ctx, "rep", "Sent draft to finance"); err != nil {
+		t.Fatalf("Unexpected error adding note: %v", err)
+	}
+	if err := tm.AddNote(ctx, "report", " "); err == nil {
+		t.Error("Expected error for an empty note")
+	}
+
+	got, _ := store.GetByID(ctx, "report")
+	if len(got.Notes) != 2 || got.Notes[1].Text != "Sent draft to finance" {
+		t.Fatalf("Expected the note appended, got %+v", got.Notes)
+	}
+
+	out.Reset()
+	if err := tm.Show(ctx, "report"); err != nil {
+		t.Fatalf("Unexpected error showing task: %v", err)
+	}
+	text := out.String()
+	newest, oldest := strings.Index(text, "Sent draft to finance"), strings.Index(text, "Collected numbers")
+	if !strings.Contains(text, "Notes:") || newest < 0 || oldest < 0 || newest > oldest {
+		t.Errorf("Expected notes listed newest first, got:\n%s", text)
+	}
+
+}
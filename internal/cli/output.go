@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// Output formats accepted by SetOutputFormat
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// SetOutputFormat selects how List, Show and Stats print: the decorated
+// text view (default) or JSON for scripting
+func (tm *TaskManager) SetOutputFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "", OutputText:
+		tm.jsonOutput = false
+	case OutputJSON:
+		tm.jsonOutput = true
+	default:
+		return fmt.Errorf("invalid output format: %q. Use: text, json", format)
+	}
+	return nil
+}
+
+// taskView is the JSON form of a task: every stored field plus the due
+// state computed at output time
+type taskView struct {
+	*task.Task
+	IsOverdue  bool `json:"is_overdue"`
+	IsDueToday bool `json:"is_due_today"`
+}
+
+// newTaskView computes the derived fields of t relative to now
+func newTaskView(t *task.Task, now time.Time) taskView {
+	return taskView{
+		Task:       t,
+		IsOverdue:  t.IsOverdueAt(now),
+		IsDueToday: t.IsDueToday(),
+	}
+}
+
+// writeJSON prints v as indented JSON
+func (tm *TaskManager) writeJSON(v any) error {
+	encoder := json.NewEncoder(tm.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
+
+// writeTasksJSON prints tasks as a JSON array, empty rather than null when
+// nothing matched
+func (tm *TaskManager) writeTasksJSON(tasks []*task.Task) error {
+	now := time.Now()
+	views := make([]taskView, len(tasks))
+	for i, t := range tasks {
+		views[i] = newTaskView(t, now)
+	}
+	return tm.writeJSON(views)
+}
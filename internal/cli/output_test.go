@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerListJSON(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	if err := tm.SetOutputFormat("json"); err != nil {
+		t.Fatalf("Unexpected error setting output format: %v", err)
+	}
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "late", Title: "Late", Priority: task.High, DueDate: now.Add(-48 * time.Hour), Tags: []string{"work"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "later", Title: "Later", Priority: task.Low, DueDate: now.Add(72 * time.Hour), CreatedAt: now, UpdatedAt: now},
+		{ID: "done", Title: "Done", Priority: task.Medium, Completed: true, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	if err := tm.List(ctx, ListOptions{}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+
+	var tasks []*task.Task
+	if err := json.Unmarshal(out.Bytes(), &tasks); err != nil {
+		t.Fatalf("Expected list output to parse as []*task.Task: %v\n%s", err, out.String())
+	}
+	if len(tasks) != 2 || tasks[0].ID != "late" || tasks[1].ID != "later" {
+		t.Fatalf("Expected the two pending tasks in priority order, got %+v", tasks)
+	}
+	if tasks[0].Tags[0] != "work" || !tasks[0].DueDate.Equal(now.Add(-48*time.Hour)) {
+		t.Errorf("Expected stored fields to survive, got %+v", tasks[0])
+	}
+
+	var computed []map[string]any
+	if err := json.Unmarshal(out.Bytes(), &computed); err != nil {
+		t.Fatalf("Unexpected error parsing JSON: %v", err)
+	}
+	if computed[0]["is_overdue"] != true || computed[1]["is_overdue"] != false {
+		t.Errorf("Expected is_overdue to be computed, got %v and %v", computed[0]["is_overdue"], computed[1]["is_overdue"])
+	}
+	if _, ok := computed[0]["is_due_today"]; !ok {
+		t.Error("Expected an is_due_today field")
+	}
+	if strings.Contains(out.String(), tm.Icons().List) {
+		t.Errorf("Expected no decorated text in JSON mode, got:\n%s", out.String())
+	}
+
+	// No matches is an empty array, not a message
+	out.Reset()
+	if err := tm.List(ctx, ListOptions{Search: "nothing"}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "[]" {
+		t.Errorf("Expected an empty JSON array, got %q", out.String())
+	}
+}
+
+func TestTaskManagerShowJSON(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	now := time.Now()
+	if err := store.Add(ctx, &task.Task{ID: "one", Title: "One", Priority: task.High, CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	if err := tm.SetOutputFormat("json"); err != nil {
+		t.Fatalf("Unexpected error setting output format: %v", err)
+	}
+	if err := tm.Show(ctx, "one"); err != nil {
+		t.Fatalf("Unexpected error showing task: %v", err)
+	}
+
+	var got task.Task
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Expected show output to parse as a task: %v\n%s", err, out.String())
+	}
+	if got.ID != "one" || got.Priority != task.High {
+		t.Errorf("Unexpected task from JSON: %+v", got)
+	}
+
+	// Switching back restores the text view
+	out.Reset()
+	if err := tm.SetOutputFormat("text"); err != nil {
+		t.Fatalf("Unexpected error setting output format: %v", err)
+	}
+	if err := tm.Show(ctx, "one"); err != nil {
+		t.Fatalf("Unexpected error showing task: %v", err)
+	}
+	if !strings.Contains(out.String(), "Task Details") {
+		t.Errorf("Expected text output, got:\n%s", out.String())
+	}
+
+	if err := tm.SetOutputFormat("yaml"); err == nil {
+		t.Error("Expected an unknown output format to be rejected")
+	}
+}
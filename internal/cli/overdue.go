@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// OverdueBucket groups overdue tasks by how late they are
+type OverdueBucket struct {
+	Label string
+	Tasks []*task.Task
+}
+
+// overdueBuckets defines the lateness ranges, each bounded by its maximum age
+var overdueBuckets = []struct {
+	label  string
+	maxAge time.Duration // zero means unbounded
+}{
+	{"1-7 days", 7 * 24 * time.Hour},
+	{"1-4 weeks", 30 * 24 * time.Hour},
+	{"over a month", 0},
+}
+
+// bucketOverdue places overdue pending tasks into lateness buckets relative
+// to now, each bucket sorted by priority
+func bucketOverdue(tasks []*task.Task, now time.Time) []OverdueBucket {
+	buckets := make([]OverdueBucket, len(overdueBuckets))
+	for i, b := range overdueBuckets {
+		buckets[i].Label = b.label
+	}
+
+	for _, t := range tasks {
+		if !t.IsOverdueAt(now) {
+			continue
+		}
+		late := now.Sub(t.DueDate)
+		for i, b := range overdueBuckets {
+			if b.maxAge == 0 || late <= b.maxAge {
+				buckets[i].Tasks = append(buckets[i].Tasks, t)
+				break
+			}
+		}
+	}
+
+	for _, b := range buckets {
+		sortTasks(b.Tasks)
+	}
+	return buckets
+}
+
+// Overdue displays overdue tasks grouped by how late they are
+func (tm *TaskManager) Overdue(ctx context.Context) error {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	buckets := bucketOverdue(tasks, time.Now())
+
+	total := 0
+	for _, b := range buckets {
+		total += len(b.Tasks)
+	}
+	if total == 0 {
+		fmt.Fprintf(tm.out, "No overdue tasks. %s\n", tm.Icons().Celebrate)
+		return nil
+	}
+
+	fmt.Fprintf(tm.out, "\n%s Overdue Tasks (%d tasks)\n", tm.Icons().Overdue, total)
+	fmt.Fprintln(tm.out, strings.Repeat("=", 50))
+
+	for _, b := range buckets {
+		if len(b.Tasks) == 0 {
+			continue
+		}
+		fmt.Fprintf(tm.out, "\n%s late (%d)\n", b.Label, len(b.Tasks))
+		fmt.Fprintln(tm.out, strings.Repeat("-", 30))
+		for _, t := range b.Tasks {
+			tm.displayTask(t, "")
+			fmt.Fprintln(tm.out)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+func TestBucketOverdue(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tasks := []*task.Task{
+		{ID: "late-2d-low", Title: "Two days", Priority: task.Low, DueDate: now.Add(-2 * day)},
+		{ID: "late-5d-high", Title: "Five days", Priority: task.High, DueDate: now.Add(-5 * day)},
+		{ID: "late-7d", Title: "Exactly a week", Priority: task.Medium, DueDate: now.Add(-7 * day)},
+		{ID: "late-10d", Title: "Ten days", Priority: task.Medium, DueDate: now.Add(-10 * day)},
+		{ID: "late-45d", Title: "Forty-five days", Priority: task.High, DueDate: now.Add(-45 * day)},
+		{ID: "done", Title: "Completed late", Completed: true, DueDate: now.Add(-3 * day)},
+		{ID: "future", Title: "Not due yet", DueDate: now.Add(day)},
+		{ID: "undated", Title: "No due date"},
+	}
+
+	buckets := bucketOverdue(tasks, now)
+
+	expected := map[string][]string{
+		"1-7 days":     {"late-5d-high", "late-7d", "late-2d-low"},
+		"1-4 weeks":    {"late-10d"},
+		"over a month": {"late-45d"},
+	}
+
+	if len(buckets) != len(expected) {
+		t.Fatalf("Expected %d buckets, got %d", len(expected), len(buckets))
+	}
+
+	for _, b := range buckets {
+		want := expected[b.Label]
+		if len(b.Tasks) != len(want) {
+			t.Errorf("Bucket %q: expected %d tasks, got %d", b.Label, len(want), len(b.Tasks))
+			continue
+		}
+		for i, id := range want {
+			if b.Tasks[i].ID != id {
+				t.Errorf("Bucket %q position %d: expected %s, got %s", b.Label, i, id, b.Tasks[i].ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"go-fun/internal/filter"
+	"go-fun/internal/task"
+)
+
+// RandomOptions narrows the tasks Random picks from
+type RandomOptions struct {
+	Priority *filter.PriorityFilter
+	Tag      string
+}
+
+// SetRand replaces the random source used by Random, so tests can seed it
+func (tm *TaskManager) SetRand(rng *rand.Rand) {
+	tm.rng = rng
+}
+
+// pickRandom returns a uniformly chosen pending task matching opts, or nil
+// when none match
+func pickRandom(tasks []*task.Task, opts RandomOptions, rng *rand.Rand) *task.Task {
+	var candidates []*task.Task
+	for _, t := range tasks {
+		if t.Completed {
+			continue
+		}
+		if opts.Priority != nil && !opts.Priority.Matches(t.Priority) {
+			continue
+		}
+		if opts.Tag != "" && !hasTag(t, opts.Tag) {
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[rng.IntN(len(candidates))]
+}
+
+// hasTag reports whether the task carries the given tag
+func hasTag(t *task.Task, tag string) bool {
+	for _, existing := range t.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Random shows one pending task chosen at random
+func (tm *TaskManager) Random(ctx context.Context, opts RandomOptions) error {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	if tm.rng == nil {
+		seed := uint64(time.Now().UnixNano())
+		tm.rng = rand.New(rand.NewPCG(seed, seed>>1))
+	}
+
+	t := pickRandom(tasks, opts, tm.rng)
+	if t == nil {
+		fmt.Fprintln(tm.out, "No pending tasks match.")
+		return nil
+	}
+
+	fmt.Fprintf(tm.out, "\n%s How about this one?\n", tm.Icons().Details)
+	fmt.Fprintln(tm.out, strings.Repeat("=", 30))
+	tm.displayTask(t, "")
+	return nil
+}
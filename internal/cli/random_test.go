@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/filter"
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestPickRandomFiltered(t *testing.T) {
+	done := task.NewTask("Done", "", task.High, time.Time{}, []string{"work"})
+	done.Complete()
+	tasks := []*task.Task{
+		task.NewTask("Low work", "", task.Low, time.Time{}, []string{"work"}),
+		task.NewTask("High home", "", task.High, time.Time{}, []string{"home"}),
+		task.NewTask("High work A", "", task.High, time.Time{}, []string{"work"}),
+		done,
+		task.NewTask("High work B", "", task.Urgent, time.Time{}, []string{"work"}),
+	}
+	high, err := filter.NewPriorityFilter(">=high")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing filter: %v", err)
+	}
+	opts := RandomOptions{Priority: &high, Tag: "work"}
+
+	// Seed 6 draws index 1 of the two matches, so the pick is the second
+	got := pickRandom(tasks, opts, rand.New(rand.NewPCG(6, 7)))
+	if got == nil || got.Title != "High work B" {
+		t.Fatalf("Expected %q, got %+v", "High work B", got)
+	}
+
+	seen := make(map[string]bool)
+	rng := rand.New(rand.NewPCG(3, 4))
+	for range 50 {
+		seen[pickRandom(tasks, opts, rng).Title] = true
+	}
+	if len(seen) != 2 || !seen["High work A"] || !seen["High work B"] {
+		t.Errorf("Expected picks from exactly the two matching tasks, got %v", seen)
+	}
+}
+
+func TestRandomEmpty(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	tm.SetRand(rand.New(rand.NewPCG(1, 2)))
+	ctx := context.Background()
+
+	if err := tm.Random(ctx, RandomOptions{}); err != nil {
+		t.Fatalf("Unexpected error picking from empty store: %v", err)
+	}
+	if !strings.Contains(out.String(), "No pending tasks match") {
+		t.Errorf("Expected empty message, got %q", out.String())
+	}
+
+	if got := pickRandom(nil, RandomOptions{Tag: "work"}, rand.New(rand.NewPCG(1, 2))); got != nil {
+		t.Errorf("Expected nil from empty set, got %+v", got)
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// Notifier alerts the user about a task that is coming due
+type Notifier interface {
+	Notify(ctx context.Context, t *task.Task) error
+}
+
+// CommandNotifier runs an external command once per task, for example
+// osascript on macOS or notify-send on Linux. The task's title and due date
+// are appended as the last two arguments and also set in the environment as
+// GO_FUN_TASK_ID, GO_FUN_TASK_TITLE and GO_FUN_TASK_DUE (RFC 3339).
+type CommandNotifier struct {
+	// Command is split on whitespace like $EDITOR, e.g. "notify-send -u critical"
+	Command string
+}
+
+// Notify runs the command for t
+func (n CommandNotifier) Notify(ctx context.Context, t *task.Task) error {
+	cmd, err := n.command(ctx, t)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run notify command: %w", err)
+	}
+	return nil
+}
+
+// command builds the process Notify runs for t
+func (n CommandNotifier) command(ctx context.Context, t *task.Task) (*exec.Cmd, error) {
+	parts := strings.Fields(n.Command)
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("notify command is empty")
+	}
+
+	due := t.DueDate.Format("2006-01-02 15:04")
+	cmd := exec.CommandContext(ctx, parts[0], append(parts[1:], t.Title, due)...)
+	cmd.Env = append(os.Environ(),
+		"GO_FUN_TASK_ID="+t.ID,
+		"GO_FUN_TASK_TITLE="+t.Title,
+		"GO_FUN_TASK_DUE="+t.DueDate.Format(time.RFC3339),
+	)
+	return cmd, nil
+}
+
+// DueReminders returns the pending tasks due between now and within from
+// now, soonest first. Overdue tasks are left to the overdue command.
+func (tm *TaskManager) DueReminders(ctx context.Context, within time.Duration) ([]*task.Task, error) {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+	return dueWithin(tasks, time.Now(), within), nil
+}
+
+// dueWithin selects the pending tasks due in [now, now+within]
+func dueWithin(tasks []*task.Task, now time.Time, within time.Duration) []*task.Task {
+	end := now.Add(within)
+	var due []*task.Task
+	for _, t := range tasks {
+		if t.Completed || t.DueDate.IsZero() {
+			continue
+		}
+		if !t.DueDate.Before(now) && !t.DueDate.After(end) {
+			due = append(due, t)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].DueDate.Before(due[j].DueDate) })
+	return due
+}
+
+// Remind lists the tasks due within the window and, when notifier is not
+// nil, passes each one to it. A failing notification does not stop the
+// others; the first error is returned once all have been tried.
+func (tm *TaskManager) Remind(ctx context.Context, within time.Duration, notifier Notifier) error {
+	due, err := tm.DueReminders(ctx, within)
+	if err != nil {
+		return err
+	}
+
+	if len(due) == 0 {
+		fmt.Fprintf(tm.out, "Nothing due in the next %s. %s\n", formatWindow(within), tm.Icons().Celebrate)
+		return nil
+	}
+
+	fmt.Fprintf(tm.out, "\n%s Due in the next %s (%d tasks)\n", tm.Icons().DueSoon, formatWindow(within), len(due))
+	fmt.Fprintln(tm.out, strings.Repeat("=", 50))
+	for _, t := range due {
+		tm.displayTask(t, "")
+		fmt.Fprintln(tm.out)
+	}
+
+	if notifier == nil {
+		return nil
+	}
+	var firstErr error
+	for _, t := range due {
+		if err := notifier.Notify(ctx, t); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to notify about %s: %w", t.ID, err)
+			}
+		}
+	}
+	return firstErr
+}
+
+// formatWindow renders a reminder window, in days when it is a whole number
+// of them and otherwise like "2h" or "1h30m"
+func formatWindow(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return formatDays(d)
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
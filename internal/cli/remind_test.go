@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+// recordingNotifier remembers the tasks it was asked to notify about
+type recordingNotifier struct {
+	ids  []string
+	fail string // ID whose notification fails
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, t *task.Task) error {
+	r.ids = append(r.ids, t.ID)
+	if t.ID == r.fail {
+		return errors.New("notifier is down")
+	}
+	return nil
+}
+
+func TestDueWithin(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	tasks := []*task.Task{
+		{ID: "late", DueDate: now.Add(-time.Minute)},
+		{ID: "edge", DueDate: now.Add(2 * time.Hour)},
+		{ID: "soon", DueDate: now.Add(30 * time.Minute)},
+		{ID: "done", DueDate: now.Add(time.Hour), Completed: true},
+		{ID: "later", DueDate: now.Add(2*time.Hour + time.Second)},
+		{ID: "undated"},
+		{ID: "now", DueDate: now},
+	}
+
+	var got []string
+	for _, tt := range dueWithin(tasks, now, 2*time.Hour) {
+		got = append(got, tt.ID)
+	}
+	if want := []string{"now", "soon", "edge"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dueWithin() = %v, expected %v", got, want)
+	}
+}
+
+func TestTaskManagerRemind(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "soon", Title: "Pay rent", DueDate: now.Add(time.Hour)},
+		{ID: "sooner", Title: "Call bank", DueDate: now.Add(10 * time.Minute)},
+		{ID: "far", Title: "Renew passport", DueDate: now.Add(48 * time.Hour)},
+	} {
+		tt.CreatedAt, tt.UpdatedAt = now, now
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	// Every due task is tried even when one notification fails
+	notifier := &recordingNotifier{fail: "sooner"}
+	err := tm.Remind(ctx, 2*time.Hour, notifier)
+	if err == nil || !strings.Contains(err.Error(), "notifier is down") {
+		t.Errorf("Expected the failed notification to be reported, got %v", err)
+	}
+	if want := []string{"sooner", "soon"}; !slices.Equal(notifier.ids, want) {
+		t.Errorf("Expected notifications for %v, got %v", want, notifier.ids)
+	}
+	if !strings.Contains(out.String(), "Due in the next 2h (2 tasks)") {
+		t.Errorf("Expected the window in the header, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := tm.Remind(ctx, time.Minute, nil); err != nil {
+		t.Fatalf("Unexpected error reminding: %v", err)
+	}
+	if !strings.Contains(out.String(), "Nothing due in the next 1m") {
+		t.Errorf("Expected an empty reminder, got:\n%s", out.String())
+	}
+}
+
+func TestCommandNotifierArguments(t *testing.T) {
+	due := time.Date(2025, 3, 10, 17, 30, 0, 0, time.UTC)
+	tt := &task.Task{ID: "task_1", Title: "Submit \"Q1\" report", DueDate: due}
+
+	cmd, err := CommandNotifier{Command: "notify-send  -u critical"}.command(context.Background(), tt)
+	if err != nil {
+		t.Fatalf("Unexpected error building command: %v", err)
+	}
+	if want := []string{"notify-send", "-u", "critical", `Submit "Q1" report`, "2025-03-10 17:30"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("Expected args %q, got %q", want, cmd.Args)
+	}
+	for _, want := range []string{"GO_FUN_TASK_ID=task_1", `GO_FUN_TASK_TITLE=Submit "Q1" report`, "GO_FUN_TASK_DUE=2025-03-10T17:30:00Z"} {
+		if !slices.Contains(cmd.Env, want) {
+			t.Errorf("Expected %s in the environment", want)
+		}
+	}
+
+	if _, err := (CommandNotifier{Command: "  "}).command(context.Background(), tt); err == nil {
+		t.Error("Expected error for an empty command")
+	}
+}
+
+func TestFormatWindow(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		2 * time.Hour:    "2h",
+		90 * time.Minute: "1h30m",
+		30 * time.Second: "30s",
+		48 * time.Hour:   "2 days",
+		36 * time.Hour:   "36h",
+		time.Minute:      "1m",
+	} {
+		if got := formatWindow(d); got != want {
+			t.Errorf("formatWindow(%v) = %q, expected %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// Repeat clones a task count times with due dates spaced one interval apart,
+// starting one interval after the original's due date (or now if undated).
+// The clones are fresh pending tasks saved together.
+func (tm *TaskManager) Repeat(ctx context.Context, id, interval string, count int) (created []*task.Task, err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	if count < 1 {
+		return nil, fmt.Errorf("count must be at least 1, got %d", count)
+	}
+	step := &task.Recurrence{Interval: interval}
+	if err := step.Validate(); err != nil {
+		return nil, err
+	}
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	var original *task.Task
+	ids := make(map[string]bool, len(tasks)+count)
+	for _, t := range tasks {
+		ids[t.ID] = true
+		if t.ID == id {
+			original = t
+		}
+	}
+	if original == nil {
+		return nil, fmt.Errorf("failed to get task: task with ID %s not found", id)
+	}
+
+	from := original.DueDate
+	if from.IsZero() {
+		from = time.Now()
+	}
+
+	clones := make([]*task.Task, 0, count)
+	for i := 1; i <= count; i++ {
+		clone := original.Clone(step.Advance(from, i))
+		// IDs come from the clock, so clones made in a tight loop can collide
+		for ids[clone.ID] {
+			clone.ID = task.NewID()
+		}
+		ids[clone.ID] = true
+		clones = append(clones, clone)
+	}
+
+	if err := tm.storage.Save(ctx, append(tasks, clones...)); err != nil {
+		return nil, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	for _, c := range clones {
+		if err := tm.logEvent("add", c.ID, nil, c); err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Fprintf(tm.out, "%s Created %d copies of %s\n", tm.Icons().Success, len(clones), id)
+	return clones, nil
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerRepeatWeekly(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	monday := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	original := task.NewTask("Team sync", "Weekly", task.Medium, monday, []string{"work"})
+	original.Complete()
+	if err := store.Add(ctx, original); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	clones, err := tm.Repeat(ctx, original.ID, task.Weekly, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error repeating task: %v", err)
+	}
+	if len(clones) != 4 {
+		t.Fatalf("Expected 4 clones, got %d", len(clones))
+	}
+
+	seen := map[string]bool{original.ID: true}
+	for i, c := range clones {
+		want := monday.AddDate(0, 0, 7*(i+1))
+		if !c.DueDate.Equal(want) {
+			t.Errorf("Clone %d: expected due %v, got %v", i, want, c.DueDate)
+		}
+		if c.DueDate.Weekday() != time.Monday {
+			t.Errorf("Clone %d: expected a Monday, got %v", i, c.DueDate.Weekday())
+		}
+		if c.Completed || c.Title != "Team sync" || len(c.Tags) != 1 {
+			t.Errorf("Clone %d: expected a pending copy, got %+v", i, c)
+		}
+		if seen[c.ID] {
+			t.Errorf("Clone %d: duplicate ID %s", i, c.ID)
+		}
+		seen[c.ID] = true
+	}
+
+	if tasks, _ := store.Load(ctx); len(tasks) != 5 {
+		t.Errorf("Expected 5 stored tasks, got %d", len(tasks))
+	}
+
+	if _, err := tm.Repeat(ctx, original.ID, task.Weekly, 0); err == nil {
+		t.Error("Expected error for a zero count")
+	}
+	if _, err := tm.Repeat(ctx, "missing", task.Weekly, 1); err == nil {
+		t.Error("Expected error for a missing task")
+	}
+}
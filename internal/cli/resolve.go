@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go-fun/internal/task"
+)
+
+// AmbiguousError reports that a query matched more than one task
+type AmbiguousError struct {
+	Query      string
+	Candidates []*task.Task
+}
+
+// Error implements the error interface
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("%q matches %d tasks: %s", e.Query, len(e.Candidates), describeTasks(e.Candidates))
+}
+
+// fuzzyMatch reports whether every non-space character of query appears in
+// title in order, ignoring case, so "lrn go" matches "Learn Go basics"
+func fuzzyMatch(query, title string) bool {
+	remaining := []rune(strings.ToLower(title))
+	for _, r := range strings.ToLower(query) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		i := 0
+		for i < len(remaining) && remaining[i] != r {
+			i++
+		}
+		if i == len(remaining) {
+			return false
+		}
+		remaining = remaining[i+1:]
+	}
+	return true
+}
+
+// resolveOne returns the single match, or an error naming the candidates
+// when there are none or several
+func resolveOne(query string, matches []*task.Task) (*task.Task, error) {
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no task matches %q", query)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, &AmbiguousError{Query: query, Candidates: matches}
+	}
+}
+
+// ResolveID returns the full ID of the task a unique ID prefix names
+func (tm *TaskManager) ResolveID(ctx context.Context, prefix string) (string, error) {
+	t, err := tm.resolveID(ctx, prefix)
+	if err != nil {
+		return "", err
+	}
+	return t.ID, nil
+}
+
+// resolveID finds the task whose ID is prefix or, failing that, the only one
+// whose ID starts with it, so short IDs can be typed. Candidates are listed
+// when the prefix is ambiguous.
+func (tm *TaskManager) resolveID(ctx context.Context, prefix string) (*task.Task, error) {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+	return tm.findByIDPrefix(tasks, prefix)
+}
+
+// findByIDPrefix is resolveID on tasks already loaded
+func (tm *TaskManager) findByIDPrefix(tasks []*task.Task, prefix string) (*task.Task, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("task ID cannot be empty")
+	}
+
+	var matches []*task.Task
+	for _, t := range tasks {
+		if t.ID == prefix {
+			return t, nil
+		}
+		if strings.HasPrefix(t.ID, prefix) {
+			matches = append(matches, t)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("task with ID %s not found", prefix)
+	}
+
+	match, err := resolveOne(prefix, matches)
+	if err != nil {
+		tm.printCandidates(err)
+		return nil, err
+	}
+	return match, nil
+}
+
+// printCandidates lists the tasks of an AmbiguousError so the user can pick
+func (tm *TaskManager) printCandidates(err error) {
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		return
+	}
+	fmt.Fprintf(tm.out, "%q matches %d tasks, be more specific:\n", ambiguous.Query, len(ambiguous.Candidates))
+	for _, t := range ambiguous.Candidates {
+		fmt.Fprintf(tm.out, "  %s %s (%s)\n", tm.Icons().StatusIcon(t), t.Title, t.ID)
+	}
+}
+
+// CompleteBy completes the one pending task whose title fuzzy-matches query.
+// Several matches are listed and nothing is changed.
+func (tm *TaskManager) CompleteBy(ctx context.Context, query string) error {
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("search text cannot be empty")
+	}
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	var matches []*task.Task
+	for _, t := range tasks {
+		if !t.Completed && fuzzyMatch(query, t.Title) {
+			matches = append(matches, t)
+		}
+	}
+	sortTasks(matches)
+
+	match, err := resolveOne(query, matches)
+	if err != nil {
+		tm.printCandidates(err)
+		return err
+	}
+
+	if err := tm.Complete(ctx, match.ID); err != nil {
+		return err
+	}
+	fmt.Fprintf(tm.out, "%s Completed: %s (%s)\n", tm.Icons().Success, match.Title, match.ID)
+	return nil
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query, title string
+		expected     bool
+	}{
+		{"learn go", "Learn Go concurrency", true},
+		{"lrn gcon", "Learn Go concurrency", true},
+		{"LEARN", "learn rust", true},
+		{"go learn", "Learn Go concurrency", false}, // order matters
+		{"learnx", "Learn Go", false},
+	}
+
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.query, tt.title); got != tt.expected {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, expected %v", tt.query, tt.title, got, tt.expected)
+		}
+	}
+}
+
+func TestTaskManagerCompleteBy(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "go-1", Title: "Learn Go basics", Priority: task.High, CreatedAt: now, UpdatedAt: now},
+		{ID: "go-2", Title: "Learn Go concurrency", Priority: task.Medium, CreatedAt: now, UpdatedAt: now},
+		{ID: "rust", Title: "Learn Rust", Priority: task.Low, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	// Ambiguous: both Go tasks match, so nothing changes
+	err := tm.CompleteBy(ctx, "learn go")
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected an AmbiguousError, got %v", err)
+	}
+	if len(ambiguous.Candidates) != 2 {
+		t.Errorf("Expected 2 candidates, got %d", len(ambiguous.Candidates))
+	}
+	for _, id := range []string{"go-1", "go-2"} {
+		if !strings.Contains(out.String(), id) {
+			t.Errorf("Expected candidate %s to be listed, got:\n%s", id, out.String())
+		}
+	}
+	tasks, _ := store.Load(ctx)
+	for _, tt := range tasks {
+		if tt.Completed {
+			t.Errorf("Expected an ambiguous match not to complete %s", tt.ID)
+		}
+	}
+
+	// Unique match completes the task
+	if err := tm.CompleteBy(ctx, "lrn go conc"); err != nil {
+		t.Fatalf("Unexpected error completing unique match: %v", err)
+	}
+	if got, _ := store.GetByID(ctx, "go-2"); !got.Completed {
+		t.Error("Expected the matched task to be completed")
+	}
+	if got, _ := store.GetByID(ctx, "go-1"); got.Completed {
+		t.Error("Expected other tasks to stay pending")
+	}
+
+	// Completed tasks no longer count, so the same search is now unique
+	if err := tm.CompleteBy(ctx, "learn go"); err != nil {
+		t.Fatalf("Unexpected error completing remaining match: %v", err)
+	}
+	if got, _ := store.GetByID(ctx, "go-1"); !got.Completed {
+		t.Error("Expected the remaining Go task to be completed")
+	}
+
+	if err := tm.CompleteBy(ctx, "python"); err == nil {
+		t.Error("Expected an error when nothing matches")
+	}
+}
+
+func TestTaskManagerResolveIDPrefix(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "task_1700000000111", Title: "Write report", CreatedAt: now, UpdatedAt: now},
+		{ID: "task_1700000000222", Title: "Review report", CreatedAt: now, UpdatedAt: now},
+		{ID: "task_17", Title: "Short ID", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	// Unique prefix
+	if err := tm.Complete(ctx, "task_17000000001"); err != nil {
+		t.Fatalf("Unexpected error completing by prefix: %v", err)
+	}
+	if got, _ := store.GetByID(ctx, "task_1700000000111"); !got.Completed {
+		t.Error("Expected the task matching the prefix to be completed")
+	}
+
+	// An exact ID wins even when it prefixes others
+	got, err := tm.resolveID(ctx, "task_17")
+	if err != nil {
+		t.Fatalf("Unexpected error resolving exact ID: %v", err)
+	}
+	if got.ID != "task_17" {
+		t.Errorf("Expected task_17, got %s", got.ID)
+	}
+
+	// Ambiguous prefix lists the candidates and changes nothing
+	out.Reset()
+	err = tm.Delete(ctx, "task_170")
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected an AmbiguousError, got %v", err)
+	}
+	if len(ambiguous.Candidates) != 2 {
+		t.Errorf("Expected 2 candidates, got %d", len(ambiguous.Candidates))
+	}
+	if !strings.Contains(out.String(), "task_1700000000222") {
+		t.Errorf("Expected candidates in output, got:\n%s", out.String())
+	}
+	if tasks, _ := store.Load(ctx); len(tasks) != 3 {
+		t.Errorf("Expected nothing deleted, got %d tasks", len(tasks))
+	}
+
+	// No match
+	if err := tm.Show(ctx, "nope"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if err := tm.Update(ctx, "", "Title", "", task.Low, time.Time{}, nil); err == nil {
+		t.Error("Expected an error for an empty ID")
+	}
+}
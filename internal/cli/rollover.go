@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+// Rollover moves completed tasks from the active store into an append-only
+// JSON archive and returns how many were moved. With dryRun it only reports.
+// Undo brings the tasks back to the store but leaves the archive as it is.
+func (tm *TaskManager) Rollover(ctx context.Context, archivePath string, dryRun bool) (n int, err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	var moving, keeping []*task.Task
+	for _, t := range tasks {
+		if t.Completed {
+			moving = append(moving, t)
+		} else {
+			keeping = append(keeping, t)
+		}
+	}
+
+	if len(moving) == 0 {
+		fmt.Fprintln(tm.out, "No completed tasks to roll over.")
+		return 0, nil
+	}
+
+	if dryRun {
+		fmt.Fprintf(tm.out, "Would move %d completed tasks to %s:\n", len(moving), archivePath)
+		for _, t := range moving {
+			fmt.Fprintf(tm.out, "  - %s (%s)\n", t.Title, t.ID)
+		}
+		return len(moving), nil
+	}
+
+	archive := storage.NewJSONFileStorage(archivePath)
+	archived, err := archive.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load archive: %w", err)
+	}
+
+	// Write the archive first so a failure never loses tasks
+	if err := archive.Save(ctx, append(append([]*task.Task{}, archived...), moving...)); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	if keeping == nil {
+		keeping = []*task.Task{}
+	}
+	if err := tm.storage.Save(ctx, keeping); err != nil {
+		// Roll the archive back so tasks are not duplicated
+		if rbErr := archive.Save(ctx, archived); rbErr != nil {
+			return 0, fmt.Errorf("failed to save tasks: %w (archive rollback also failed: %v)", err, rbErr)
+		}
+		return 0, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	if err := tm.logDeleted(moving, nil); err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintf(tm.out, "%s Moved %d completed tasks to %s\n", tm.Icons().Archived, len(moving), archivePath)
+	return len(moving), nil
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerRollover(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-rollover-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	active := storage.NewInMemoryStorage()
+	tm := NewTaskManager(active)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "done-1", Title: "Done 1", Completed: true, CreatedAt: now, UpdatedAt: now},
+		{ID: "open-1", Title: "Open 1", CreatedAt: now, UpdatedAt: now},
+		{ID: "done-2", Title: "Done 2", Completed: true, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := active.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	// Seed the archive to confirm it is appended to, not replaced
+	archivePath := filepath.Join(tempDir, "archive.json")
+	archive := storage.NewJSONFileStorage(archivePath)
+	if err := archive.Save(ctx, []*task.Task{{ID: "old", Title: "Old", Completed: true}}); err != nil {
+		t.Fatalf("Failed to seed archive: %v", err)
+	}
+
+	// Dry run changes nothing
+	moved, err := tm.Rollover(ctx, archivePath, true)
+	if err != nil {
+		t.Fatalf("Unexpected error in dry run: %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("Expected dry run to report 2 tasks, got %d", moved)
+	}
+	if tasks, _ := active.Load(ctx); len(tasks) != 3 {
+		t.Errorf("Expected dry run to leave 3 active tasks, got %d", len(tasks))
+	}
+
+	moved, err = tm.Rollover(ctx, archivePath, false)
+	if err != nil {
+		t.Fatalf("Unexpected error rolling over: %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("Expected 2 tasks moved, got %d", moved)
+	}
+
+	remaining, _ := active.Load(ctx)
+	if len(remaining) != 1 || remaining[0].ID != "open-1" {
+		t.Errorf("Expected only open-1 to remain active, got %v", remaining)
+	}
+
+	archived, err := archive.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading archive: %v", err)
+	}
+	ids := make([]string, 0, len(archived))
+	for _, a := range archived {
+		ids = append(ids, a.ID)
+	}
+	if len(ids) != 3 || ids[0] != "old" || ids[1] != "done-1" || ids[2] != "done-2" {
+		t.Errorf("Expected archive [old done-1 done-2], got %v", ids)
+	}
+}
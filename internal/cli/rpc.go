@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"go-fun/internal/filter"
+	"go-fun/internal/task"
+)
+
+// rpcRequest is a single newline-delimited JSON request
+type rpcRequest struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse answers one request; exactly one of Result or Error is set
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Result any             `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// rpcAddParams are the parameters of the "add" method
+type rpcAddParams struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Priority    string    `json:"priority"`
+	DueDate     time.Time `json:"due_date"`
+	Tags        []string  `json:"tags"`
+}
+
+// rpcListParams are the parameters of the "list" method
+type rpcListParams struct {
+	Completed bool   `json:"completed"`
+	Priority  string `json:"priority"`
+	Search    string `json:"search"`
+	Due       string `json:"due"`
+}
+
+// rpcIDParams are the parameters of methods addressing a single task
+type rpcIDParams struct {
+	ID string `json:"id"`
+}
+
+// maxRPCLineSize bounds a single request line
+const maxRPCLineSize = 1024 * 1024
+
+// ServeRPC reads newline-delimited JSON requests from r and writes one JSON
+// response line per request to w until r is exhausted or ctx is cancelled.
+// A failing request produces an error response without ending the stream.
+func (tm *TaskManager) ServeRPC(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRPCLineSize)
+	encoder := json.NewEncoder(w)
+
+	// Human-readable notices would corrupt the response stream
+	out := tm.out
+	tm.out = io.Discard
+	defer func() { tm.out = out }()
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var resp rpcResponse
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			resp.ID = req.ID
+			result, err := tm.dispatchRPC(ctx, req)
+			if err != nil {
+				resp.Error = err.Error()
+			} else {
+				resp.Result = result
+			}
+		}
+
+		if err := encoder.Encode(resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// dispatchRPC runs a single request against the task manager
+func (tm *TaskManager) dispatchRPC(ctx context.Context, req rpcRequest) (any, error) {
+	switch req.Method {
+	case "add":
+		var params rpcAddParams
+		if err := decodeRPCParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		priority := task.Medium
+		if params.Priority != "" {
+			p, err := tm.config.ParsePriority(params.Priority)
+			if err != nil {
+				return nil, err
+			}
+			priority = p
+		}
+		tags := task.NormalizeTags(params.Tags)
+		if err := task.ValidateTags(tags); err != nil {
+			return nil, err
+		}
+		newTask := task.NewTask(params.Title, params.Description, priority, params.DueDate, tags)
+		if err := tm.AddTask(ctx, newTask); err != nil {
+			return nil, err
+		}
+		return newTask, nil
+
+	case "list":
+		var params rpcListParams
+		if err := decodeRPCParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		opts := ListOptions{ShowCompleted: params.Completed, Search: params.Search, Due: params.Due}
+		if params.Priority != "" {
+			f, err := filter.ParsePriorityFilter(params.Priority, tm.config.ParsePriority)
+			if err != nil {
+				return nil, err
+			}
+			opts.Priority = &f
+		}
+		tasks, err := tm.storage.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tasks: %w", err)
+		}
+		filtered, err := filterTasks(tasks, opts)
+		if err != nil {
+			return nil, err
+		}
+		sortTasks(filtered)
+		return filtered, nil
+
+	case "get", "show", "complete", "uncomplete", "delete":
+		var params rpcIDParams
+		if err := decodeRPCParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.ID == "" {
+			return nil, fmt.Errorf("missing id parameter")
+		}
+
+		var err error
+		switch req.Method {
+		case "complete":
+			err = tm.Complete(ctx, params.ID)
+		case "uncomplete":
+			err = tm.Uncomplete(ctx, params.ID)
+		case "delete":
+			if err := tm.Delete(ctx, params.ID); err != nil {
+				return nil, err
+			}
+			return map[string]string{"deleted": params.ID}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return tm.resolveID(ctx, params.ID)
+
+	default:
+		return nil, fmt.Errorf("unknown method: %q", req.Method)
+	}
+}
+
+// decodeRPCParams unmarshals params into v, treating missing params as empty
+func decodeRPCParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestServeRPC(t *testing.T) {
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	tm.SetStrictTitles(true)
+	ctx := context.Background()
+
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		err := tm.ServeRPC(ctx, reqR, respW)
+		respW.Close()
+		done <- err
+	}()
+
+	responses := bufio.NewScanner(respR)
+	call := func(line string) map[string]json.RawMessage {
+		t.Helper()
+		if _, err := fmt.Fprintln(reqW, line); err != nil {
+			t.Fatalf("Failed to write request: %v", err)
+		}
+		if !responses.Scan() {
+			t.Fatalf("Expected a response to %s", line)
+		}
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(responses.Bytes(), &resp); err != nil {
+			t.Fatalf("Response is not valid JSON: %v", err)
+		}
+		return resp
+	}
+
+	// add
+	resp := call(`{"id": 1, "method": "add", "params": {"title": "Via RPC", "priority": "high", "tags": ["rpc"]}}`)
+	if _, ok := resp["error"]; ok {
+		t.Fatalf("Unexpected error response: %s", resp["error"])
+	}
+	var added task.Task
+	if err := json.Unmarshal(resp["result"], &added); err != nil {
+		t.Fatalf("Failed to decode added task: %v", err)
+	}
+	if added.Title != "Via RPC" || added.Priority != task.High {
+		t.Errorf("Unexpected added task: %+v", added)
+	}
+	if string(resp["id"]) != "1" {
+		t.Errorf("Expected response id 1, got %s", resp["id"])
+	}
+
+	// add goes through the same title checks as the add command
+	resp = call(`{"id": 6, "method": "add", "params": {"title": "Line\nbreak"}}`)
+	if _, ok := resp["error"]; !ok {
+		t.Error("Expected strict titles to reject a newline")
+	}
+
+	// A bad request does not end the stream
+	resp = call(`not json`)
+	if _, ok := resp["error"]; !ok {
+		t.Error("Expected error for malformed request")
+	}
+	resp = call(`{"id": 2, "method": "explode"}`)
+	if _, ok := resp["error"]; !ok {
+		t.Error("Expected error for unknown method")
+	}
+
+	// list
+	resp = call(`{"id": 3, "method": "list", "params": {"priority": "h"}}`)
+	var listed []*task.Task
+	if err := json.Unmarshal(resp["result"], &listed); err != nil {
+		t.Fatalf("Failed to decode list result: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != added.ID {
+		t.Errorf("Expected list to return the added task, got %v", listed)
+	}
+
+	// complete
+	resp = call(fmt.Sprintf(`{"id": 4, "method": "complete", "params": {"id": %q}}`, added.ID))
+	var completed task.Task
+	if err := json.Unmarshal(resp["result"], &completed); err != nil {
+		t.Fatalf("Failed to decode completed task: %v", err)
+	}
+	if !completed.Completed {
+		t.Error("Expected task to be completed")
+	}
+
+	// uncomplete by ID prefix
+	resp = call(fmt.Sprintf(`{"id": 7, "method": "uncomplete", "params": {"id": %q}}`, added.ID[:4]))
+	var uncompleted task.Task
+	if err := json.Unmarshal(resp["result"], &uncompleted); err != nil {
+		t.Fatalf("Failed to decode uncompleted task: %v (%s)", err, resp["error"])
+	}
+	if uncompleted.ID != added.ID || uncompleted.Completed {
+		t.Errorf("Expected the task uncompleted by prefix, got %+v", uncompleted)
+	}
+
+	resp = call(`{"id": 5, "method": "complete", "params": {"id": "missing"}}`)
+	if _, ok := resp["error"]; !ok {
+		t.Error("Expected error completing a missing task")
+	}
+
+	reqW.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error from ServeRPC: %v", err)
+	}
+}
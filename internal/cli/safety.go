@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+// ErrSuspiciousStore is returned when a bulk-destructive operation loaded no
+// tasks even though the backing store still holds data
+var ErrSuspiciousStore = errors.New("store looks inconsistent: loaded 0 tasks but the data file is not empty (use --force to override)")
+
+// guardBulkDestructive refuses to continue when the loaded task set is empty
+// but the store reports data, since acting on it could wipe the wrong thing
+func (tm *TaskManager) guardBulkDestructive(tasks []*task.Task, force bool) error {
+	if force || len(tasks) > 0 {
+		return nil
+	}
+
+	checker, ok := tm.storage.(storage.DataChecker)
+	if !ok {
+		return nil
+	}
+
+	hasData, err := checker.HasData()
+	if err != nil {
+		return fmt.Errorf("failed to inspect store: %w", err)
+	}
+	if hasData {
+		return ErrSuspiciousStore
+	}
+
+	return nil
+}
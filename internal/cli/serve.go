@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// Handler returns the HTTP API. GET /tasks.ics serves the live task set as
+// an iCalendar feed for calendar subscriptions, optionally narrowed with
+// ?tag=.
+func (tm *TaskManager) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks.ics", tm.serveICS)
+	return mux
+}
+
+// serveICS writes the current tasks as a VCALENDAR
+func (tm *TaskManager) serveICS(w http.ResponseWriter, r *http.Request) {
+	tasks, err := tm.storage.Load(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to load tasks: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		tagged := make([]*task.Task, 0, len(tasks))
+		for _, t := range tasks {
+			if hasTag(t, tag) {
+				tagged = append(tagged, t)
+			}
+		}
+		tasks = tagged
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	// Calendar apps poll the feed, so never let a cache serve stale tasks
+	w.Header().Set("Cache-Control", "no-cache")
+	// Once headers are sent a write error can only truncate the body
+	writeICS(w, tasks)
+}
+
+// Serve runs the HTTP API on addr until ctx is done
+func (tm *TaskManager) Serve(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{
+		Handler:           tm.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.Serve(listener) }()
+	fmt.Fprintf(tm.out, "Serving on http://%s/tasks.ics\n", listener.Addr())
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+	return nil
+}
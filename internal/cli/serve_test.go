@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestServeICS(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "a", Title: "Report, draft; v2", Tags: []string{"work"}, DueDate: now.Add(24 * time.Hour), CreatedAt: now, UpdatedAt: now},
+		{ID: "b", Title: "Standup", Tags: []string{"work"}, Completed: true, CompletedAt: now, CreatedAt: now, UpdatedAt: now},
+		{ID: "c", Title: "Groceries", Tags: []string{"home"}, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	server := httptest.NewServer(tm.Handler())
+	defer server.Close()
+
+	get := func(path string) string {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Unexpected error fetching %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected 200 for %s, got %d", path, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+			t.Errorf("Expected text/calendar, got %q", ct)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error reading body: %v", err)
+		}
+		return string(body)
+	}
+
+	body := get("/tasks.ics")
+	if !strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(body, "END:VCALENDAR\r\n") {
+		t.Errorf("Expected a VCALENDAR wrapper, got:\n%s", body)
+	}
+	if n := strings.Count(body, "BEGIN:VTODO\r\n"); n != 3 {
+		t.Errorf("Expected 3 VTODOs, got %d", n)
+	}
+	if n := strings.Count(body, "END:VTODO\r\n"); n != 3 {
+		t.Errorf("Expected 3 closed VTODOs, got %d", n)
+	}
+	if !strings.Contains(body, `SUMMARY:Report\, draft\; v2`) {
+		t.Errorf("Expected escaped summary, got:\n%s", body)
+	}
+	if !strings.Contains(body, "STATUS:COMPLETED") {
+		t.Error("Expected a completed VTODO")
+	}
+
+	tagged := get("/tasks.ics?tag=work")
+	if n := strings.Count(tagged, "BEGIN:VTODO\r\n"); n != 2 {
+		t.Errorf("Expected 2 VTODOs tagged work, got %d", n)
+	}
+	if strings.Contains(tagged, "Groceries") {
+		t.Error("Expected the tag filter to drop untagged tasks")
+	}
+
+	resp, err := http.Post(server.URL+"/tasks.ics", "text/plain", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error posting: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for POST, got %d", resp.StatusCode)
+	}
+}
+
+func TestWriteICSLineFolding(t *testing.T) {
+	var b strings.Builder
+	long := strings.Repeat("é", 60) // 120 octets
+	if err := writeICS(&b, []*task.Task{{ID: "x", Title: long}}); err != nil {
+		t.Fatalf("Unexpected error writing ICS: %v", err)
+	}
+
+	var summary string
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected lines of at most 75 octets, got %d", len(line))
+		}
+		if strings.HasPrefix(line, "SUMMARY:") {
+			summary = line
+		} else if summary != "" && strings.HasPrefix(line, " ") {
+			summary += line[1:]
+		} else if summary != "" {
+			break
+		}
+	}
+	if summary != "SUMMARY:"+long {
+		t.Errorf("Expected unfolded summary to round-trip, got %q", summary)
+	}
+}
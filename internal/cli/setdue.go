@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// SetDueRelative sets the due date of every pending task selected by where
+// ("no-due" or "all") to its base timestamp ("created", "updated" or "now")
+// plus offset, saving once. It returns the number of tasks changed.
+func (tm *TaskManager) SetDueRelative(ctx context.Context, base string, offset time.Duration, where string) (int, error) {
+	now := time.Now()
+
+	var baseTime func(t *task.Task) time.Time
+	switch base {
+	case "created":
+		baseTime = func(t *task.Task) time.Time { return t.CreatedAt }
+	case "updated":
+		baseTime = func(t *task.Task) time.Time { return t.UpdatedAt }
+	case "now":
+		baseTime = func(t *task.Task) time.Time { return now }
+	default:
+		return 0, fmt.Errorf("invalid base field: %q. Use: created, updated, now", base)
+	}
+
+	var selects func(t *task.Task) bool
+	switch where {
+	case "no-due":
+		selects = func(t *task.Task) bool { return t.DueDate.IsZero() }
+	case "all":
+		selects = func(t *task.Task) bool { return true }
+	default:
+		return 0, fmt.Errorf("invalid selection: %q. Use: no-due, all", where)
+	}
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	pending, err := filterTasks(tasks, ListOptions{Scheduled: true})
+	if err != nil {
+		return 0, err
+	}
+
+	var changed [][2]*task.Task
+	for _, t := range pending {
+		b := baseTime(t)
+		if !selects(t) || b.IsZero() {
+			continue
+		}
+		if pair, ok := tm.moveDue(t, b.Add(offset), now); ok {
+			changed = append(changed, pair)
+		}
+	}
+
+	return tm.saveDueChanges(ctx, tasks, changed)
+}
+
+// PlanToday sets the due date of the given pending tasks, or of every
+// overdue task with allOverdue, to the end of today. Unknown IDs are
+// rejected before anything changes. It returns the number of tasks changed.
+func (tm *TaskManager) PlanToday(ctx context.Context, ids []string, allOverdue bool) (int, error) {
+	now := time.Now()
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	var selected []*task.Task
+	if allOverdue {
+		for _, t := range tasks {
+			if t.IsOverdueAt(now) {
+				selected = append(selected, t)
+			}
+		}
+	}
+
+	byID := make(map[string]*task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	for _, id := range ids {
+		t, ok := byID[id]
+		if !ok {
+			return 0, fmt.Errorf("failed to get task: task with ID %s not found", id)
+		}
+		selected = append(selected, t)
+	}
+
+	due := endOfDay(now)
+	var changed [][2]*task.Task
+	for _, t := range selected {
+		if t.Completed || t.DueDate.Equal(due) {
+			continue
+		}
+		if pair, ok := tm.moveDue(t, due, now); ok {
+			changed = append(changed, pair)
+		}
+	}
+
+	return tm.saveDueChanges(ctx, tasks, changed)
+}
+
+// moveDue sets the due date of t and returns it with its prior state. A
+// task the new date would make invalid, such as one starting after it, is
+// left unchanged and reported as skipped.
+func (tm *TaskManager) moveDue(t *task.Task, due, now time.Time) ([2]*task.Task, bool) {
+	before := snapshot(t)
+	t.DueDate = due
+	t.UpdatedAt = now
+	if err := t.Validate(); err != nil {
+		*t = *before
+		fmt.Fprintf(tm.out, "Skipped %s (%s): %v\n", t.Title, t.ID, err)
+		return [2]*task.Task{}, false
+	}
+	return [2]*task.Task{before, t}, true
+}
+
+// saveDueChanges saves tasks after due dates were changed on them, logs
+// the before and after pairs in changed as one operation and reports the
+// count
+func (tm *TaskManager) saveDueChanges(ctx context.Context, tasks []*task.Task, changed [][2]*task.Task) (n int, err error) {
+	if len(changed) == 0 {
+		fmt.Fprintln(tm.out, "No tasks matched.")
+		return 0, nil
+	}
+
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	if err := tm.storage.Save(ctx, tasks); err != nil {
+		return 0, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	for _, pair := range changed {
+		if err := tm.logEvent("update", pair[1].ID, pair[0], pair[1]); err != nil {
+			return 0, err
+		}
+	}
+
+	fmt.Fprintf(tm.out, "%s Set due date on %d tasks\n", tm.Icons().Success, len(changed))
+	return len(changed), nil
+}
+
+// RescheduleOverdue spreads overdue pending tasks across the days from start
+// on, perDay to a day in priority order, and returns the number changed
+func (tm *TaskManager) RescheduleOverdue(ctx context.Context, start time.Time, perDay int) (int, error) {
+	if perDay <= 0 {
+		return 0, fmt.Errorf("tasks per day must be positive, got %d", perDay)
+	}
+
+	now := time.Now()
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	var overdue []*task.Task
+	for _, t := range tasks {
+		if t.IsOverdueAt(now) {
+			overdue = append(overdue, t)
+		}
+	}
+
+	var changed [][2]*task.Task
+	for t, due := range spreadDue(overdue, start, perDay) {
+		if pair, ok := tm.moveDue(t, due, now); ok {
+			changed = append(changed, pair)
+		}
+	}
+
+	return tm.saveDueChanges(ctx, tasks, changed)
+}
+
+// spreadDue assigns tasks, most important and oldest due first, to the end
+// of consecutive days from start, filling each day with perDay tasks
+func spreadDue(tasks []*task.Task, start time.Time, perDay int) map[*task.Task]time.Time {
+	ordered := append([]*task.Task(nil), tasks...)
+	sortTasks(ordered)
+
+	assigned := make(map[*task.Task]time.Time, len(ordered))
+	for i, t := range ordered {
+		assigned[t] = endOfDay(start.AddDate(0, 0, i/perDay))
+	}
+	return assigned
+}
+
+// SetStart sets the start date of one task, or clears it when start is zero
+func (tm *TaskManager) SetStart(ctx context.Context, id string, start time.Time) error {
+	t, err := tm.resolveID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	id = t.ID
+
+	before := snapshot(t)
+	t.StartDate = start
+	t.UpdatedAt = time.Now()
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid task: %w", err)
+	}
+
+	if err := tm.storage.Update(ctx, id, t); err != nil {
+		return err
+	}
+	return tm.logEvent("update", id, before, t)
+}
+
+// SetDueDate changes only the due date of one task, or clears it when due
+// is zero
+func (tm *TaskManager) SetDueDate(ctx context.Context, id string, due time.Time) error {
+	t, err := tm.resolveID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	id = t.ID
+
+	before := snapshot(t)
+	t.DueDate = due
+	t.UpdatedAt = time.Now()
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid task: %w", err)
+	}
+
+	if err := tm.storage.Update(ctx, id, t); err != nil {
+		return err
+	}
+	return tm.logEvent("update", id, before, t)
+}
@@ -0,0 +1,309 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerSetDueRelative(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	created := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	existingDue := time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []*task.Task{
+		{ID: "undated-1", Title: "Undated 1", CreatedAt: created, UpdatedAt: created},
+		{ID: "undated-2", Title: "Undated 2", CreatedAt: created.Add(48 * time.Hour), UpdatedAt: created},
+		{ID: "dated", Title: "Dated", DueDate: existingDue, CreatedAt: created, UpdatedAt: created},
+		{ID: "done", Title: "Done", Completed: true, CreatedAt: created, UpdatedAt: created},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	offset, err := ParseOffset("+14d")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing offset: %v", err)
+	}
+
+	changed, err := tm.SetDueRelative(ctx, "created", offset, "no-due")
+	if err != nil {
+		t.Fatalf("Unexpected error setting due dates: %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("Expected 2 tasks changed, got %d", changed)
+	}
+
+	expected := map[string]time.Time{
+		"undated-1": created.Add(14 * 24 * time.Hour),
+		"undated-2": created.Add(16 * 24 * time.Hour),
+		"dated":     existingDue,
+		"done":      {},
+	}
+	for id, want := range expected {
+		got, _ := store.GetByID(ctx, id)
+		if !got.DueDate.Equal(want) {
+			t.Errorf("%s: expected due %v, got %v", id, want, got.DueDate)
+		}
+	}
+
+	if _, err := tm.SetDueRelative(ctx, "birthday", offset, "no-due"); err == nil {
+		t.Error("Expected error for invalid base field")
+	}
+	if _, err := tm.SetDueRelative(ctx, "created", offset, "someday"); err == nil {
+		t.Error("Expected error for invalid selection")
+	}
+}
+
+func TestTaskManagerPlanToday(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, tt := range []*task.Task{
+		{ID: "a", Title: "A", CreatedAt: now, UpdatedAt: now},
+		{ID: "b", Title: "B", DueDate: now.AddDate(0, 0, 5), CreatedAt: now, UpdatedAt: now},
+		{ID: "late", Title: "Late", DueDate: now.AddDate(0, 0, -3), CreatedAt: now, UpdatedAt: now},
+		{ID: "untouched", Title: "Untouched", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	if _, err := tm.PlanToday(ctx, []string{"a", "missing"}, false); err == nil {
+		t.Fatal("Expected error for unknown ID")
+	}
+	if a, _ := store.GetByID(ctx, "a"); !a.DueDate.IsZero() {
+		t.Errorf("Expected no change after a rejected ID, got %v", a.DueDate)
+	}
+
+	changed, err := tm.PlanToday(ctx, []string{"a", "b"}, true)
+	if err != nil {
+		t.Fatalf("Unexpected error planning today: %v", err)
+	}
+	if changed != 3 {
+		t.Errorf("Expected 3 tasks changed, got %d", changed)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Set due date on 3 tasks")) {
+		t.Errorf("Expected count in output, got %q", out.String())
+	}
+
+	want := endOfDay(now)
+	for _, id := range []string{"a", "b", "late"} {
+		got, _ := store.GetByID(ctx, id)
+		if !got.DueDate.Equal(want) {
+			t.Errorf("Expected %s due %v, got %v", id, want, got.DueDate)
+		}
+	}
+	if u, _ := store.GetByID(ctx, "untouched"); !u.DueDate.IsZero() {
+		t.Errorf("Expected untouched task to keep no due date, got %v", u.DueDate)
+	}
+}
+
+func TestTaskManagerDueSettersSkipLaterStart(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	now := time.Now()
+	start := now.AddDate(0, 1, 0)
+	for _, tt := range []*task.Task{
+		{ID: "later", Title: "Later", StartDate: start, CreatedAt: now, UpdatedAt: now},
+		{ID: "now", Title: "Now", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	changed, err := tm.PlanToday(ctx, []string{"later", "now"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error planning today: %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("Expected 1 task changed, got %d", changed)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Skipped Later (later)")) {
+		t.Errorf("Expected the skipped task in output, got %q", out.String())
+	}
+
+	offset, err := ParseOffset("+1d")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing offset: %v", err)
+	}
+	if _, err := tm.SetDueRelative(ctx, "now", offset, "all"); err != nil {
+		t.Fatalf("Unexpected error setting due dates: %v", err)
+	}
+
+	later, _ := store.GetByID(ctx, "later")
+	if !later.DueDate.IsZero() {
+		t.Errorf("Expected the task starting later to keep no due date, got %v", later.DueDate)
+	}
+	// The skipped task stays valid, so later edits still work
+	if err := tm.AddNote(ctx, "later", "still editable"); err != nil {
+		t.Errorf("Unexpected error editing the skipped task: %v", err)
+	}
+}
+
+func TestSpreadDue(t *testing.T) {
+	start := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	overdue := start.Add(-30 * 24 * time.Hour)
+
+	// Twelve tasks, the first four high priority; later tasks are more
+	// recently overdue so the order within a priority is by index
+	var tasks []*task.Task
+	for i := 0; i < 12; i++ {
+		priority := task.Low
+		if i < 4 {
+			priority = task.High
+		}
+		tasks = append(tasks, &task.Task{ID: fmt.Sprintf("t%d", i), Priority: priority, DueDate: overdue.Add(time.Duration(i) * time.Hour)})
+	}
+
+	assigned := spreadDue(tasks, start, 5)
+	if len(assigned) != 12 {
+		t.Fatalf("Expected 12 assignments, got %d", len(assigned))
+	}
+
+	perDay := map[time.Time]int{}
+	for _, due := range assigned {
+		perDay[due]++
+	}
+	days := []time.Time{
+		time.Date(2025, 6, 10, 23, 59, 59, 0, time.UTC),
+		time.Date(2025, 6, 11, 23, 59, 59, 0, time.UTC),
+		time.Date(2025, 6, 12, 23, 59, 59, 0, time.UTC),
+	}
+	for i, want := range []int{5, 5, 2} {
+		if perDay[days[i]] != want {
+			t.Errorf("Expected %d tasks on %s, got %d", want, days[i].Format("2006-01-02"), perDay[days[i]])
+		}
+	}
+	if len(perDay) != 3 {
+		t.Errorf("Expected tasks across 3 days, got %d", len(perDay))
+	}
+
+	// High priority first, then the oldest due of the rest
+	for i, want := range map[int]time.Time{0: days[0], 3: days[0], 4: days[0], 5: days[1], 9: days[1], 10: days[2], 11: days[2]} {
+		if got := assigned[tasks[i]]; !got.Equal(want) {
+			t.Errorf("t%d: expected %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestTaskManagerRescheduleOverdue(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	past := time.Now().Add(-48 * time.Hour)
+	for _, tt := range []*task.Task{
+		{ID: "late-1", Title: "Late 1", DueDate: past},
+		{ID: "late-2", Title: "Late 2", DueDate: past},
+		{ID: "late-3", Title: "Late 3", DueDate: past},
+		{ID: "done", Title: "Done", DueDate: past, Completed: true},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	start := time.Now().AddDate(0, 0, 1)
+	changed, err := tm.RescheduleOverdue(ctx, start, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error rescheduling: %v", err)
+	}
+	if changed != 3 {
+		t.Errorf("Expected 3 tasks changed, got %d", changed)
+	}
+
+	tasks, _ := store.Load(ctx)
+	for _, tt := range tasks {
+		if tt.ID == "done" {
+			if !tt.DueDate.Equal(past) {
+				t.Error("Expected completed task to keep its due date")
+			}
+			continue
+		}
+		if tt.DueDate.Before(start) {
+			t.Errorf("%s: expected a due date from %v on, got %v", tt.ID, start, tt.DueDate)
+		}
+	}
+
+	if _, err := tm.RescheduleOverdue(ctx, start, 0); err == nil {
+		t.Error("Expected error for zero tasks per day")
+	}
+}
+
+func TestTaskManagerSetPriorityAndDueDate(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	created := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	original := &task.Task{
+		ID: "report", Title: "Quarterly report", Description: "Numbers and charts",
+		Priority: task.Low, DueDate: created.Add(72 * time.Hour), Tags: []string{"work"},
+		DependsOn: []string{"other"}, EstimateMinutes: 45, CreatedAt: created, UpdatedAt: created,
+	}
+	if err := store.Add(ctx, snapshot(original)); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	// checkOnly compares the stored task with original after change is
+	// applied to a copy, ignoring UpdatedAt, which must have moved
+	checkOnly := func(change func(*task.Task)) {
+		t.Helper()
+		got, err := store.GetByID(ctx, "report")
+		if err != nil {
+			t.Fatalf("Unexpected error getting task: %v", err)
+		}
+		if !got.UpdatedAt.After(created) {
+			t.Error("Expected UpdatedAt to be bumped")
+		}
+		want := snapshot(original)
+		change(want)
+		want.UpdatedAt = got.UpdatedAt
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	}
+
+	if err := tm.SetPriority(ctx, "rep", task.Urgent); err != nil {
+		t.Fatalf("Unexpected error setting priority: %v", err)
+	}
+	checkOnly(func(w *task.Task) { w.Priority = task.Urgent })
+
+	due := created.Add(24 * time.Hour)
+	if err := tm.SetDueDate(ctx, "report", due); err != nil {
+		t.Fatalf("Unexpected error setting due date: %v", err)
+	}
+	checkOnly(func(w *task.Task) { w.Priority, w.DueDate = task.Urgent, due })
+
+	if err := tm.SetDueDate(ctx, "report", time.Time{}); err != nil {
+		t.Fatalf("Unexpected error clearing due date: %v", err)
+	}
+	checkOnly(func(w *task.Task) { w.Priority, w.DueDate = task.Urgent, time.Time{} })
+
+	if err := tm.SetDueDate(ctx, "missing", due); err == nil {
+		t.Error("Expected error for a missing task")
+	}
+}
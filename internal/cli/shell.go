@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ShellCommand runs one command read by Shell
+type ShellCommand func(ctx context.Context, command string, args []string) error
+
+// shellPrompt is printed before each line Shell reads
+const shellPrompt = "go-fun> "
+
+// Shell reads commands from the input set by SetInput one line at a time
+// and hands each to run, until quit, exit or the end of input. Prompts the
+// commands show read from the same input. A failing command prints its
+// error and the loop carries on, so one typo doesn't end a triage session.
+// Blank lines and lines starting with # are skipped.
+func (tm *TaskManager) Shell(ctx context.Context, run ShellCommand) error {
+	for {
+		fmt.Fprint(tm.out, shellPrompt)
+		line, err := tm.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read command: %w", err)
+		}
+		if err == io.EOF && line == "" {
+			fmt.Fprintln(tm.out)
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		args, err := splitShellLine(line)
+		if err != nil {
+			fmt.Fprintf(tm.out, "Error: %v\n", err)
+			continue
+		}
+
+		switch args[0] {
+		case "quit", "exit":
+			return nil
+		}
+		if err := run(ctx, args[0], args[1:]); err != nil {
+			fmt.Fprintf(tm.out, "Error: %v\n", err)
+		}
+	}
+}
+
+// splitShellLine splits a line into words the way a POSIX shell would for
+// simple cases: whitespace separates words, single quotes keep text as is,
+// double quotes allow \" and \\, and a backslash outside quotes escapes the
+// next character
+func splitShellLine(line string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("line ends with a backslash")
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestSplitShellLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"list -p high", []string{"list", "-p", "high"}},
+		{`add -t "Buy milk, eggs"  -d 'it''s fine'`, []string{"add", "-t", "Buy milk, eggs", "-d", "its fine"}},
+		{`add -t "say \"hi\"" -d a\ b`, []string{"add", "-t", `say "hi"`, "-d", "a b"}},
+		{`update id ""`, []string{"update", "id", ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitShellLine(tt.line)
+		if err != nil {
+			t.Errorf("splitShellLine(%q): unexpected error %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitShellLine(%q) = %q, expected %q", tt.line, got, tt.want)
+		}
+	}
+
+	for _, line := range []string{`add "open`, `add 'open`, `add trailing\`} {
+		if _, err := splitShellLine(line); err == nil {
+			t.Errorf("Expected error for %q", line)
+		}
+	}
+}
+
+func TestTaskManagerShell(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+
+	// A small dispatcher standing in for the CLI's command switch
+	run := func(ctx context.Context, command string, args []string) error {
+		switch command {
+		case "add":
+			now := time.Now()
+			return tm.AddTask(ctx, &task.Task{ID: args[0], Title: args[1], CreatedAt: now, UpdatedAt: now})
+		case "complete":
+			return tm.Complete(ctx, args[0])
+		}
+		return fmt.Errorf("unknown command: %s", command)
+	}
+
+	script := strings.Join([]string{
+		`add milk "Buy milk, eggs"`,
+		`# comments and blank lines are skipped`,
+		``,
+		`add bread 'Bake bread'`,
+		`bogus`,
+		`complete milk`,
+		`quit`,
+		`add never "Never added"`,
+	}, "\n")
+	tm.SetInput(strings.NewReader(script))
+	if err := tm.Shell(ctx, run); err != nil {
+		t.Fatalf("Unexpected error running shell: %v", err)
+	}
+
+	tasks, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+	}
+	if tasks[0].Title != "Buy milk, eggs" || !tasks[0].Completed {
+		t.Errorf("Expected milk to be added and completed, got %+v", tasks[0])
+	}
+	if tasks[1].Completed {
+		t.Error("Expected bread to stay pending")
+	}
+	if !strings.Contains(out.String(), "Error: unknown command: bogus") {
+		t.Errorf("Expected the failing command's error in output, got:\n%s", out.String())
+	}
+
+	// End of input also ends the loop
+	tm.SetInput(strings.NewReader("complete bread"))
+	if err := tm.Shell(ctx, run); err != nil {
+		t.Fatalf("Unexpected error running shell: %v", err)
+	}
+	if got, _ := store.GetByID(ctx, "bread"); !got.Completed {
+		t.Error("Expected the last line to run without a trailing newline")
+	}
+}
+
+func TestTaskManagerShellSharesInputWithPrompts(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	now := time.Now()
+	run := func(ctx context.Context, command string, args []string) error {
+		switch command {
+		case "add":
+			if err := tm.ConfirmOrAbort("Add " + args[0] + "?"); err != nil {
+				return err
+			}
+			return tm.AddTask(ctx, &task.Task{ID: args[0], Title: args[0], CreatedAt: now, UpdatedAt: now})
+		}
+		return fmt.Errorf("unknown command: %s", command)
+	}
+
+	// Each prompt answers with the line after its command, not a line the
+	// shell has already buffered
+	tm.SetInput(strings.NewReader("add first\ny\nadd second\nn\nadd third\ny\n"))
+	if err := tm.Shell(ctx, run); err != nil {
+		t.Fatalf("Unexpected error running shell: %v", err)
+	}
+
+	tasks, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	var ids []string
+	for _, tt := range tasks {
+		ids = append(ids, tt.ID)
+	}
+	if strings.Join(ids, ",") != "first,third" {
+		t.Errorf("Expected first and third to be added, got %v", ids)
+	}
+}
+
+func TestTaskManagerShellStopsWhenCancelled(t *testing.T) {
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	tm.SetOutput(&bytes.Buffer{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ran := 0
+	run := func(ctx context.Context, command string, args []string) error {
+		ran++
+		cancel()
+		return nil
+	}
+
+	tm.SetInput(strings.NewReader("one\ntwo\n"))
+	if err := tm.Shell(ctx, run); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if ran != 1 {
+		t.Errorf("Expected one command before the cancellation, got %d", ran)
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+
+	"go-fun/internal/task"
+)
+
+// sortTasksBy orders tasks by the given --sort key, reversed on request.
+// Ties keep the default priority-then-due order, and tasks without a due
+// date stay last under the due key either way.
+func (tm *TaskManager) sortTasksBy(tasks []*task.Task, key string, reverse bool) error {
+	var compare func(a, b *task.Task) int
+	key = strings.ToLower(key)
+	switch key {
+	case "", "priority":
+		compare = func(a, b *task.Task) int { return cmp.Compare(b.Priority, a.Priority) }
+	case "due":
+		compare = func(a, b *task.Task) int { return a.DueDate.Compare(b.DueDate) }
+	case "created":
+		compare = func(a, b *task.Task) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	case "updated":
+		compare = func(a, b *task.Task) int { return a.UpdatedAt.Compare(b.UpdatedAt) }
+	case "title":
+		less, err := tm.titleLess()
+		if err != nil {
+			return err
+		}
+		compare = func(a, b *task.Task) int {
+			switch {
+			case less(a.Title, b.Title):
+				return -1
+			case less(b.Title, a.Title):
+				return 1
+			}
+			return 0
+		}
+	default:
+		return fmt.Errorf("invalid sort key: %s. Use: priority, due, created, updated, title", key)
+	}
+
+	sortTasks(tasks)
+	slices.SortStableFunc(tasks, func(a, b *task.Task) int {
+		if key == "due" && a.DueDate.IsZero() != b.DueDate.IsZero() {
+			if a.DueDate.IsZero() {
+				return 1
+			}
+			return -1
+		}
+		if reverse {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+	return nil
+}
+
+// titleLess returns the title comparison for the configured locale. Without a
+// locale, titles compare case-insensitively; with one, they are collated so
+// accented letters sort where speakers of that language expect them.
+func (tm *TaskManager) titleLess() (func(a, b string) bool, error) {
+	if tm.config.Locale == "" {
+		return func(a, b string) bool {
+			return strings.ToLower(a) < strings.ToLower(b)
+		}, nil
+	}
+
+	tag, err := language.Parse(tm.config.Locale)
+	if err != nil {
+		return nil, fmt.Errorf("invalid locale %q: %w", tm.config.Locale, err)
+	}
+
+	collator := collate.New(tag, collate.IgnoreCase)
+	return func(a, b string) bool {
+		return collator.CompareString(a, b) < 0
+	}, nil
+}
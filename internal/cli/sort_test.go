@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"go-fun/internal/config"
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestSortTasksByTitle(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{"", []string{"apple", "Banana", "Ära"}},
+		{"de", []string{"apple", "Ära", "Banana"}},
+		{"sv", []string{"apple", "Banana", "Ära"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("locale="+tt.locale, func(t *testing.T) {
+			tm := NewTaskManager(storage.NewInMemoryStorage())
+			cfg := config.Default()
+			cfg.Locale = tt.locale
+			tm.SetConfig(cfg)
+
+			tasks := []*task.Task{{Title: "Ära"}, {Title: "Banana"}, {Title: "apple"}}
+			if err := tm.sortTasksBy(tasks, "title", false); err != nil {
+				t.Fatalf("Unexpected error sorting: %v", err)
+			}
+
+			for i, want := range tt.want {
+				if tasks[i].Title != want {
+					t.Errorf("Position %d: expected %s, got %s", i, want, tasks[i].Title)
+				}
+			}
+		})
+	}
+}
+
+func TestSortTasksByKeys(t *testing.T) {
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	fixture := func() []*task.Task {
+		return []*task.Task{
+			{ID: "a", Title: "Alpha", Priority: task.Low, DueDate: base.AddDate(0, 0, 3), CreatedAt: base.AddDate(0, 0, -1), UpdatedAt: base.AddDate(0, 0, 2)},
+			{ID: "b", Title: "bravo", Priority: task.High, CreatedAt: base.AddDate(0, 0, -3), UpdatedAt: base},
+			{ID: "c", Title: "Charlie", Priority: task.Medium, DueDate: base.AddDate(0, 0, 1), CreatedAt: base.AddDate(0, 0, -2), UpdatedAt: base.AddDate(0, 0, 1)},
+			{ID: "d", Title: "delta", Priority: task.High, DueDate: base.AddDate(0, 0, 2), CreatedAt: base, UpdatedAt: base.AddDate(0, 0, 3)},
+		}
+	}
+
+	tests := []struct {
+		key     string
+		reverse bool
+		want    string
+	}{
+		{"", false, "dbca"},
+		{"priority", true, "acdb"},
+		{"due", false, "cdab"},
+		{"due", true, "adcb"}, // undated b stays last
+		{"created", false, "bcad"},
+		{"created", true, "dacb"},
+		{"updated", false, "bcad"},
+		{"updated", true, "dacb"},
+		{"title", false, "abcd"},
+		{"title", true, "dcba"},
+	}
+
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	for _, tt := range tests {
+		tasks := fixture()
+		if err := tm.sortTasksBy(tasks, tt.key, tt.reverse); err != nil {
+			t.Fatalf("Unexpected error sorting by %q: %v", tt.key, err)
+		}
+		got := ""
+		for _, task := range tasks {
+			got += task.ID
+		}
+		if got != tt.want {
+			t.Errorf("sort %q reverse=%v: expected %s, got %s", tt.key, tt.reverse, tt.want, got)
+		}
+	}
+}
+
+func TestSortTasksByInvalid(t *testing.T) {
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	if err := tm.sortTasksBy(nil, "colour", false); err == nil {
+		t.Error("Expected error for unknown sort key")
+	}
+
+	cfg := config.Default()
+	cfg.Locale = "not a locale!"
+	tm.SetConfig(cfg)
+	if err := tm.sortTasksBy(nil, "title", false); err == nil {
+		t.Error("Expected error for invalid locale")
+	}
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// StatsResult holds aggregate counts over a set of tasks
+type StatsResult struct {
+	Total         int                   `json:"total"`
+	Completed     int                   `json:"completed"`
+	CompletedLate int                   `json:"completed_late"`
+	Overdue       int                   `json:"overdue"`
+	DueToday      int                   `json:"due_today"`
+	DueSoon       int                   `json:"due_soon"`
+	ByPriority    map[task.Priority]int `json:"-"`
+
+	// PendingByTag counts incomplete tasks under each tag they carry
+	PendingByTag map[string]int `json:"pending_by_tag"`
+	// OverdueByPriority counts overdue tasks at each priority level
+	OverdueByPriority map[task.Priority]int `json:"-"`
+}
+
+// MarshalJSON encodes the result with priority counts keyed by name, since
+// the numeric Priority values are a storage detail
+func (s StatsResult) MarshalJSON() ([]byte, error) {
+	type plain StatsResult
+	return json.Marshal(struct {
+		plain
+		ByPriority        map[string]int `json:"by_priority"`
+		OverdueByPriority map[string]int `json:"overdue_by_priority"`
+	}{plain(s), priorityNames(s.ByPriority), priorityNames(s.OverdueByPriority)})
+}
+
+// priorityNames rekeys per-priority counts by lowercase priority name
+func priorityNames(counts map[task.Priority]int) map[string]int {
+	named := make(map[string]int, len(counts))
+	for p, n := range counts {
+		named[strings.ToLower(p.String())] = n
+	}
+	return named
+}
+
+// TagsByPendingCount returns the tags in PendingByTag, busiest first and
+// alphabetically among equal counts
+func (s StatsResult) TagsByPendingCount() []string {
+	tags := make([]string, 0, len(s.PendingByTag))
+	for tag := range s.PendingByTag {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if s.PendingByTag[tags[i]] != s.PendingByTag[tags[j]] {
+			return s.PendingByTag[tags[i]] > s.PendingByTag[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	return tags
+}
+
+// Remaining returns the number of tasks not yet completed
+func (s StatsResult) Remaining() int {
+	return s.Total - s.Completed
+}
+
+// CompletionPercent returns the share of completed tasks, rounded down
+func (s StatsResult) CompletionPercent() int {
+	if s.Total == 0 {
+		return 0
+	}
+	return s.Completed * 100 / s.Total
+}
+
+// ComputeStats loads all tasks and aggregates their statistics
+func (tm *TaskManager) ComputeStats(ctx context.Context) (StatsResult, error) {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return StatsResult{}, fmt.Errorf("failed to load tasks: %w", err)
+	}
+	return computeStats(tasks, tm.dueSoon()), nil
+}
+
+// SetDueSoonWindow sets how far ahead a task counts as due soon in
+// statistics. Zero or less restores task.DefaultDueSoonWindow.
+func (tm *TaskManager) SetDueSoonWindow(d time.Duration) {
+	tm.dueSoonWindow = d
+}
+
+// dueSoon returns the configured due-soon window or the default
+func (tm *TaskManager) dueSoon() time.Duration {
+	if tm.dueSoonWindow <= 0 {
+		return task.DefaultDueSoonWindow
+	}
+	return tm.dueSoonWindow
+}
+
+// formatDays renders a whole-day window such as "7 days"
+func formatDays(d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// computeStats aggregates statistics for the given tasks, counting those
+// due within window as due soon
+func computeStats(tasks []*task.Task, window time.Duration) StatsResult {
+	result := StatsResult{
+		ByPriority:        make(map[task.Priority]int),
+		PendingByTag:      make(map[string]int),
+		OverdueByPriority: make(map[task.Priority]int),
+	}
+
+	for _, t := range tasks {
+		result.Total++
+		if t.Completed {
+			result.Completed++
+			if t.WasLate {
+				result.CompletedLate++
+			}
+		} else {
+			if t.IsOverdue() {
+				result.Overdue++
+				result.OverdueByPriority[t.Priority]++
+			}
+			if t.IsDueToday() {
+				result.DueToday++
+			}
+			if t.IsDueSoonWithin(window) {
+				result.DueSoon++
+			}
+			for _, tag := range t.Tags {
+				result.PendingByTag[tag]++
+			}
+		}
+		result.ByPriority[t.Priority]++
+	}
+
+	return result
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-fun/internal/task"
+)
+
+// ErrHasSubtasks is returned when deleting a task that still has children
+// without asking for a cascade
+var ErrHasSubtasks = errors.New("task has subtasks (use --cascade to delete them too)")
+
+// ErrOpenSubtasks is returned when completing a task with pending subtasks
+// while subtasks are required to be done first
+var ErrOpenSubtasks = errors.New("task has open subtasks")
+
+// SetRequireSubtasks makes Complete refuse a task whose subtasks are still
+// pending instead of completing them along with it
+func (tm *TaskManager) SetRequireSubtasks(enabled bool) {
+	tm.requireSubtasks = enabled
+}
+
+// SetAutoCompleteParent makes Complete also complete a parent once its last
+// open subtask is done
+func (tm *TaskManager) SetAutoCompleteParent(enabled bool) {
+	tm.autoCompleteParent = enabled
+}
+
+// completeFinishedParent completes the parent when none of its subtasks are
+// left open. Going through Complete lets a grandparent follow in turn.
+func (tm *TaskManager) completeFinishedParent(ctx context.Context, parentID string) error {
+	parent, err := tm.storage.GetByID(ctx, parentID)
+	if err != nil {
+		// A dangling parent reference is not this completion's problem
+		return nil
+	}
+	if parent.Completed {
+		return nil
+	}
+
+	open, err := tm.openSubtasks(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	if len(open) > 0 {
+		return nil
+	}
+
+	fmt.Fprintf(tm.out, "%s All subtasks done, completing parent: %s (%s)\n", tm.Icons().Done, parent.Title, parent.ID)
+	return tm.Complete(ctx, parentID)
+}
+
+// openSubtasks returns the pending tasks anywhere below id
+func (tm *TaskManager) openSubtasks(ctx context.Context, id string) ([]*task.Task, error) {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	byID := make(map[string]*task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	var open []*task.Task
+	for _, childID := range descendantIDs(tasks, id) {
+		if child := byID[childID]; !child.Completed {
+			open = append(open, child)
+		}
+	}
+	return open, nil
+}
+
+// completeSubtasks marks subtasks done alongside their parent. Recurring
+// subtasks do not spawn a next occurrence, since their parent is finished.
+func (tm *TaskManager) completeSubtasks(ctx context.Context, subtasks []*task.Task) error {
+	for _, t := range subtasks {
+		before := snapshot(t)
+		t.Complete()
+		if err := tm.storage.Update(ctx, t.ID, t); err != nil {
+			return fmt.Errorf("failed to complete subtask %s: %w", t.ID, err)
+		}
+		if err := tm.logEvent("complete", t.ID, before, t); err != nil {
+			return err
+		}
+		fmt.Fprintf(tm.out, "%s Also completed subtask: %s (%s)\n", tm.Icons().Done, t.Title, t.ID)
+	}
+	return nil
+}
+
+// describeTasks lists tasks as "title (id)" for error messages
+func describeTasks(tasks []*task.Task) string {
+	names := make([]string, len(tasks))
+	for i, t := range tasks {
+		names[i] = fmt.Sprintf("%s (%s)", t.Title, t.ID)
+	}
+	return strings.Join(names, ", ")
+}
+
+// checkParent verifies that parentID names an existing task and that making
+// it the parent of id would not make id its own ancestor
+func checkParent(tasks []*task.Task, id, parentID string) error {
+	byID := make(map[string]*task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	if _, ok := byID[parentID]; !ok {
+		return fmt.Errorf("parent task with ID %s not found", parentID)
+	}
+
+	seen := make(map[string]bool)
+	for ancestor := parentID; ancestor != ""; {
+		if ancestor == id {
+			return fmt.Errorf("cannot make %s a subtask of %s: a task cannot be its own ancestor", id, parentID)
+		}
+		if seen[ancestor] {
+			break // existing data already loops; nothing new to detect
+		}
+		seen[ancestor] = true
+
+		t, ok := byID[ancestor]
+		if !ok {
+			break
+		}
+		ancestor = t.ParentID
+	}
+	return nil
+}
+
+// childrenOf groups tasks by parent ID, keeping the order of tasks
+func childrenOf(tasks []*task.Task) map[string][]*task.Task {
+	children := make(map[string][]*task.Task)
+	for _, t := range tasks {
+		if t.ParentID != "" {
+			children[t.ParentID] = append(children[t.ParentID], t)
+		}
+	}
+	return children
+}
+
+// descendantIDs returns the IDs of every task below id
+func descendantIDs(tasks []*task.Task, id string) []string {
+	children := childrenOf(tasks)
+
+	var ids []string
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range children[current] {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			ids = append(ids, child.ID)
+			queue = append(queue, child.ID)
+		}
+	}
+	return ids
+}
+
+// displayTree prints tasks with children indented under their parents. Tasks
+// whose parent is not among tasks are shown at the top level. show prints a
+// single task.
+func (tm *TaskManager) displayTree(tasks []*task.Task, show func(t *task.Task)) {
+	present := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		present[t.ID] = true
+	}
+	children := childrenOf(tasks)
+	shown := make(map[string]bool, len(tasks))
+
+	var walk func(t *task.Task, depth int)
+	walk = func(t *task.Task, depth int) {
+		if shown[t.ID] {
+			return
+		}
+		shown[t.ID] = true
+
+		out := tm.out
+		tm.out = &indentWriter{w: out, prefix: strings.Repeat("    ", depth)}
+		show(t)
+		tm.out = out
+		fmt.Fprintln(tm.out)
+
+		for _, child := range children[t.ID] {
+			walk(child, depth+1)
+		}
+	}
+
+	for _, t := range tasks {
+		if t.ParentID == "" || !present[t.ParentID] {
+			walk(t, 0)
+		}
+	}
+	// Anything left sits on a parent cycle in the stored data
+	for _, t := range tasks {
+		walk(t, 0)
+	}
+}
+
+// indentWriter prefixes every line written through it
+type indentWriter struct {
+	w       io.Writer
+	prefix  string
+	midLine bool
+}
+
+func (iw *indentWriter) Write(p []byte) (int, error) {
+	var b strings.Builder
+	for _, c := range string(p) {
+		if !iw.midLine && c != '\n' {
+			b.WriteString(iw.prefix)
+		}
+		b.WriteRune(c)
+		iw.midLine = c != '\n'
+	}
+	if _, err := io.WriteString(iw.w, b.String()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+// addHierarchy stores root > child > grandchild plus an unrelated task
+func addHierarchy(t *testing.T, tm *TaskManager) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, tt := range []*task.Task{
+		{ID: "root", Title: "Launch", Priority: task.High, CreatedAt: now, UpdatedAt: now},
+		{ID: "child", Title: "Write docs", Priority: task.Medium, ParentID: "root", CreatedAt: now, UpdatedAt: now},
+		{ID: "grandchild", Title: "Draft intro", Priority: task.Low, ParentID: "child", Completed: true, CreatedAt: now, UpdatedAt: now},
+		{ID: "other", Title: "Unrelated", Priority: task.Low, DependsOn: []string{"child"}, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := tm.AddTask(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding %s: %v", tt.ID, err)
+		}
+	}
+}
+
+func TestTaskManagerAddSubtaskValidation(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	ctx := context.Background()
+	addHierarchy(t, tm)
+
+	orphan := task.NewTask("Orphan", "", task.Low, time.Time{}, nil)
+	orphan.ParentID = "missing"
+	if err := tm.AddTask(ctx, orphan); err == nil {
+		t.Error("Expected error for a missing parent")
+	}
+
+	self := &task.Task{ID: "self", Title: "Self", ParentID: "self"}
+	if err := tm.AddTask(ctx, self); err == nil {
+		t.Error("Expected error for a task that is its own parent")
+	}
+
+	// Re-parenting root under its grandchild would make a cycle
+	root, _ := store.GetByID(ctx, "root")
+	reparented := *root
+	reparented.ParentID = "grandchild"
+	if _, err := tm.Upsert(ctx, &reparented); err == nil || !strings.Contains(err.Error(), "own ancestor") {
+		t.Errorf("Expected cycle error, got %v", err)
+	}
+}
+
+func TestTaskManagerListTree(t *testing.T) {
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	addHierarchy(t, tm)
+
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	if err := tm.List(context.Background(), ListOptions{ShowCompleted: true, Tree: true, Sort: "title"}); err != nil {
+		t.Fatalf("Unexpected error listing tasks: %v", err)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	indentOf := func(title string) int {
+		for _, line := range lines {
+			if strings.HasSuffix(line, " "+title) {
+				return len(line) - len(strings.TrimLeft(line, " "))
+			}
+		}
+		t.Fatalf("Title %q not found in output:\n%s", title, out.String())
+		return -1
+	}
+
+	if got := indentOf("Launch"); got != 0 {
+		t.Errorf("Expected root at the top level, got indent %d", got)
+	}
+	if got := indentOf("Write docs"); got != 4 {
+		t.Errorf("Expected child indented by 4, got %d", got)
+	}
+	if got := indentOf("Draft intro"); got != 8 {
+		t.Errorf("Expected grandchild indented by 8, got %d", got)
+	}
+	if got := indentOf("Unrelated"); got != 0 {
+		t.Errorf("Expected unrelated task at the top level, got indent %d", got)
+	}
+	if strings.Index(out.String(), "Write docs") < strings.Index(out.String(), "Launch") {
+		t.Error("Expected child to follow its parent")
+	}
+}
+
+func TestTaskManagerShowSubtasks(t *testing.T) {
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	addHierarchy(t, tm)
+
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	if err := tm.Show(context.Background(), "child"); err != nil {
+		t.Fatalf("Unexpected error showing task: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "Subtasks:") || !strings.Contains(out.String(), "✅ Draft intro (grandchild)") {
+		t.Errorf("Expected completed subtask listed, got:\n%s", out.String())
+	}
+}
+
+func TestTaskManagerDeleteWithSubtasks(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	ctx := context.Background()
+	addHierarchy(t, tm)
+
+	if err := tm.Delete(ctx, "root"); !errors.Is(err, ErrHasSubtasks) {
+		t.Fatalf("Expected ErrHasSubtasks, got %v", err)
+	}
+	if tasks, _ := store.Load(ctx); len(tasks) != 4 {
+		t.Fatalf("Expected refused delete to keep all tasks, got %d", len(tasks))
+	}
+
+	if err := tm.DeleteCascade(ctx, "root"); err != nil {
+		t.Fatalf("Unexpected error cascading delete: %v", err)
+	}
+
+	tasks, _ := store.Load(ctx)
+	if len(tasks) != 1 || tasks[0].ID != "other" {
+		t.Fatalf("Expected only the unrelated task to remain, got %d tasks", len(tasks))
+	}
+	if len(tasks[0].DependsOn) != 0 {
+		t.Errorf("Expected dependency on a deleted subtask to be removed, got %v", tasks[0].DependsOn)
+	}
+}
+
+func TestTaskManagerCompleteRequireSubtasks(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	tm.SetRequireSubtasks(true)
+	ctx := context.Background()
+	addHierarchy(t, tm)
+
+	err := tm.Complete(ctx, "root")
+	if !errors.Is(err, ErrOpenSubtasks) {
+		t.Fatalf("Expected ErrOpenSubtasks, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Write docs (child)") || strings.Contains(err.Error(), "grandchild") {
+		t.Errorf("Expected the error to list only open subtasks, got %v", err)
+	}
+	if root, _ := store.GetByID(ctx, "root"); root.Completed {
+		t.Error("Expected the parent to stay pending")
+	}
+
+	// Once the subtasks are done the parent may be completed
+	if err := tm.Complete(ctx, "child"); err != nil {
+		t.Fatalf("Unexpected error completing subtask: %v", err)
+	}
+	if err := tm.Complete(ctx, "root"); err != nil {
+		t.Fatalf("Unexpected error completing parent: %v", err)
+	}
+}
+
+func TestTaskManagerCompleteAutoCompleteParent(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		store := storage.NewInMemoryStorage()
+		tm := NewTaskManager(store)
+		tm.SetOutput(&bytes.Buffer{})
+		tm.SetAutoCompleteParent(enabled)
+		ctx := context.Background()
+		addHierarchy(t, tm)
+		now := time.Now()
+		if err := tm.AddTask(ctx, &task.Task{ID: "child2", Title: "Record demo", ParentID: "root", CreatedAt: now, UpdatedAt: now}); err != nil {
+			t.Fatalf("Unexpected error adding subtask: %v", err)
+		}
+
+		// One subtask left open keeps the parent pending either way
+		if err := tm.Complete(ctx, "child"); err != nil {
+			t.Fatalf("Unexpected error completing subtask: %v", err)
+		}
+		if root, _ := store.GetByID(ctx, "root"); root.Completed {
+			t.Fatalf("enabled=%v: expected the parent to stay pending while a subtask is open", enabled)
+		}
+
+		if err := tm.Complete(ctx, "child2"); err != nil {
+			t.Fatalf("Unexpected error completing last subtask: %v", err)
+		}
+		root, _ := store.GetByID(ctx, "root")
+		if root.Completed != enabled {
+			t.Errorf("enabled=%v: expected parent completed=%v, got %v", enabled, enabled, root.Completed)
+		}
+		if enabled && root.CompletedAt.IsZero() {
+			t.Error("Expected the auto-completed parent to record CompletedAt")
+		}
+	}
+}
+
+func TestTaskManagerCompleteAutoCompletesSubtasks(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+	ctx := context.Background()
+	addHierarchy(t, tm)
+
+	if err := tm.Complete(ctx, "root"); err != nil {
+		t.Fatalf("Unexpected error completing parent: %v", err)
+	}
+
+	for _, id := range []string{"root", "child", "grandchild"} {
+		if got, _ := store.GetByID(ctx, id); !got.Completed {
+			t.Errorf("Expected %s to be completed", id)
+		}
+	}
+	if other, _ := store.GetByID(ctx, "other"); other.Completed {
+		t.Error("Expected the unrelated task to stay pending")
+	}
+	if !strings.Contains(out.String(), "Also completed subtask: Write docs (child)") {
+		t.Errorf("Expected auto-completed subtask to be reported, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "Draft intro") {
+		t.Errorf("Expected already-completed subtask not to be reported, got:\n%s", out.String())
+	}
+}
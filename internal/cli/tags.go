@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// TagCount is a tag and the number of tasks carrying it
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// ListTags prints every tag with the number of tasks using it, most used
+// first. Completed tasks are only counted with includeCompleted.
+func (tm *TaskManager) ListTags(ctx context.Context, includeCompleted bool) ([]TagCount, error) {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	counted, err := filterTasks(tasks, ListOptions{ShowCompleted: includeCompleted, Scheduled: true})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := countTags(counted, "")
+	if len(counts) == 0 {
+		fmt.Fprintln(tm.out, "No tags in use.")
+		return counts, nil
+	}
+
+	fmt.Fprintf(tm.out, "%s Tags (%d)\n", tm.Icons().Tags, len(counts))
+	for _, c := range counts {
+		fmt.Fprintf(tm.out, "  %-20s %d\n", c.Tag, c.Count)
+	}
+	return counts, nil
+}
+
+// RenameTag replaces tag old with new on every task in one save, merging
+// into new where a task already has it. It returns the number of tasks
+// changed.
+func (tm *TaskManager) RenameTag(ctx context.Context, old, new string) (int, error) {
+	old, new = strings.ToLower(strings.TrimSpace(old)), strings.ToLower(strings.TrimSpace(new))
+	if old == "" || new == "" {
+		return 0, fmt.Errorf("tag names cannot be empty")
+	}
+	if old == new {
+		return 0, fmt.Errorf("tag %q is already called that", old)
+	}
+	if err := task.ValidateTags([]string{new}); err != nil {
+		return 0, err
+	}
+
+	n, err := tm.retag(ctx, old, new)
+	if err != nil {
+		return 0, err
+	}
+	fmt.Fprintf(tm.out, "%s Renamed %q to %q on %d tasks\n", tm.Icons().Success, old, new, n)
+	return n, nil
+}
+
+// RemoveTag removes tag from every task in one save and returns the number
+// of tasks changed
+func (tm *TaskManager) RemoveTag(ctx context.Context, tag string) (int, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return 0, fmt.Errorf("tag name cannot be empty")
+	}
+
+	n, err := tm.retag(ctx, tag, "")
+	if err != nil {
+		return 0, err
+	}
+	fmt.Fprintf(tm.out, "%s Removed %q from %d tasks\n", tm.Icons().Success, tag, n)
+	return n, nil
+}
+
+// retag swaps tag for replacement, or drops it when replacement is empty, on
+// every task carrying it
+func (tm *TaskManager) retag(ctx context.Context, tag, replacement string) (n int, err error) {
+	tm.beginOp()
+	defer tm.endOp(&err)
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	var changed [][2]*task.Task
+	now := time.Now()
+	for _, t := range tasks {
+		found := false
+		tags := make([]string, 0, len(t.Tags))
+		for _, existing := range t.Tags {
+			if strings.EqualFold(existing, tag) {
+				existing, found = replacement, true
+			}
+			tags = append(tags, existing)
+		}
+		if !found {
+			continue
+		}
+		before := snapshot(t)
+		t.Tags = task.NormalizeTags(tags)
+		t.UpdatedAt = now
+		changed = append(changed, [2]*task.Task{before, t})
+	}
+
+	if len(changed) == 0 {
+		return 0, fmt.Errorf("no task has tag %q", tag)
+	}
+
+	if err := tm.storage.Save(ctx, tasks); err != nil {
+		return 0, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	for _, pair := range changed {
+		if err := tm.logEvent("update", pair[1].ID, pair[0], pair[1]); err != nil {
+			return 0, err
+		}
+	}
+	return len(changed), nil
+}
+
+// TagSuggestions returns the known tags starting with prefix, ignoring case,
+// most used first. It backs shell and editor completion of tag values.
+func (tm *TaskManager) TagSuggestions(ctx context.Context, prefix string) ([]string, error) {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+	return suggestTags(tasks, prefix), nil
+}
+
+// suggestTags lists the tags matching prefix in countTags order
+func suggestTags(tasks []*task.Task, prefix string) []string {
+	counts := countTags(tasks, prefix)
+	tags := make([]string, 0, len(counts))
+	for _, c := range counts {
+		tags = append(tags, c.Tag)
+	}
+	return tags
+}
+
+// countTags counts the tags starting with prefix, ignoring case, across
+// tasks and orders them by count, then alphabetically
+func countTags(tasks []*task.Task, prefix string) []TagCount {
+	prefix = strings.ToLower(prefix)
+	byTag := make(map[string]int)
+	for _, t := range tasks {
+		for _, tag := range t.Tags {
+			if strings.HasPrefix(strings.ToLower(tag), prefix) {
+				byTag[tag]++
+			}
+		}
+	}
+
+	counts := make([]TagCount, 0, len(byTag))
+	for tag, n := range byTag {
+		counts = append(counts, TagCount{Tag: tag, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	return counts
+}
@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestTaskManagerTagSuggestions(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	ctx := context.Background()
+
+	for _, tt := range []*task.Task{
+		{ID: "1", Title: "One", Tags: []string{"work", "writing"}},
+		{ID: "2", Title: "Two", Tags: []string{"writing", "home"}},
+		{ID: "3", Title: "Three", Tags: []string{"writing", "workshop"}},
+		{ID: "4", Title: "Four", Tags: []string{"workshop"}, Completed: true},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"w", []string{"writing", "workshop", "work"}},
+		{"WOR", []string{"workshop", "work"}},
+		{"h", []string{"home"}},
+		{"x", []string{}},
+		{"", []string{"writing", "workshop", "home", "work"}},
+	}
+
+	for _, tt := range tests {
+		got, err := tm.TagSuggestions(ctx, tt.prefix)
+		if err != nil {
+			t.Fatalf("Unexpected error suggesting tags: %v", err)
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Prefix %q: expected %v, got %v", tt.prefix, tt.expected, got)
+		}
+	}
+}
+
+func TestTaskManagerListTags(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	ctx := context.Background()
+
+	for _, tt := range []*task.Task{
+		{ID: "1", Title: "One", Tags: []string{"work", "urgent"}},
+		{ID: "2", Title: "Two", Tags: []string{"work", "home"}},
+		{ID: "3", Title: "Three", Tags: []string{"home", "errand"}},
+		{ID: "4", Title: "Four", Tags: []string{"errand"}, Completed: true},
+		{ID: "5", Title: "Five", Tags: []string{"errand", "work"}, Completed: true},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	tm.SetOutput(&out)
+
+	pending, err := tm.ListTags(ctx, false)
+	if err != nil {
+		t.Fatalf("Unexpected error listing tags: %v", err)
+	}
+	expected := []TagCount{{"home", 2}, {"work", 2}, {"errand", 1}, {"urgent", 1}}
+	if !reflect.DeepEqual(pending, expected) {
+		t.Errorf("Expected pending counts %v, got %v", expected, pending)
+	}
+	if !strings.Contains(out.String(), "home") || !strings.Contains(out.String(), "2") {
+		t.Errorf("Expected counts in output, got %q", out.String())
+	}
+
+	all, err := tm.ListTags(ctx, true)
+	if err != nil {
+		t.Fatalf("Unexpected error listing tags: %v", err)
+	}
+	expected = []TagCount{{"errand", 3}, {"work", 3}, {"home", 2}, {"urgent", 1}}
+	if !reflect.DeepEqual(all, expected) {
+		t.Errorf("Expected counts with completed %v, got %v", expected, all)
+	}
+}
+
+func TestTaskManagerRenameTagMerges(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	for _, tt := range []*task.Task{
+		{ID: "both", Title: "Both", Tags: []string{"job", "work"}},
+		{ID: "old", Title: "Old", Tags: []string{"home", "job"}},
+		{ID: "untouched", Title: "Untouched", Tags: []string{"home"}},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	n, err := tm.RenameTag(ctx, "job", "work")
+	if err != nil {
+		t.Fatalf("Unexpected error renaming tag: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 tasks changed, got %d", n)
+	}
+
+	expected := map[string][]string{"both": {"work"}, "old": {"home", "work"}, "untouched": {"home"}}
+	for id, want := range expected {
+		got, _ := store.GetByID(ctx, id)
+		if !reflect.DeepEqual(got.Tags, want) {
+			t.Errorf("%s: expected tags %v, got %v", id, want, got.Tags)
+		}
+	}
+
+	if _, err := tm.RenameTag(ctx, "job", "work"); err == nil {
+		t.Error("Expected error renaming a tag no task has")
+	}
+	if _, err := tm.RenameTag(ctx, "work", "Work"); err == nil {
+		t.Error("Expected error renaming a tag to itself")
+	}
+}
+
+func TestTaskManagerRemoveTag(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	for _, tt := range []*task.Task{
+		{ID: "1", Title: "One", Tags: []string{"stale", "work"}},
+		{ID: "2", Title: "Two", Tags: []string{"stale"}},
+		{ID: "3", Title: "Three", Tags: []string{"work"}, Completed: true},
+	} {
+		if err := store.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	n, err := tm.RemoveTag(ctx, "stale")
+	if err != nil {
+		t.Fatalf("Unexpected error removing tag: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 tasks changed, got %d", n)
+	}
+
+	expected := map[string][]string{"1": {"work"}, "2": nil, "3": {"work"}}
+	for id, want := range expected {
+		got, _ := store.GetByID(ctx, id)
+		if !reflect.DeepEqual(got.Tags, want) {
+			t.Errorf("%s: expected tags %v, got %v", id, want, got.Tags)
+		}
+	}
+}
+
+func TestTaskManagerLegacyTagsStayEditable(t *testing.T) {
+	// Written before tags were limited: a space and more than 20 tags
+	tags := []string{"work stuff"}
+	for i := 0; i < 21; i++ {
+		tags = append(tags, fmt.Sprintf("t%d", i))
+	}
+	data, err := json.Marshal([]*task.Task{{ID: "task_1", Title: "Old", Tags: tags, CreatedAt: time.Now(), UpdatedAt: time.Now()}})
+	if err != nil {
+		t.Fatalf("Unexpected error marshaling: %v", err)
+	}
+	filePath := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatalf("Unexpected error writing file: %v", err)
+	}
+
+	store := storage.NewJSONFileStorage(filePath)
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	ctx := context.Background()
+
+	if err := tm.Complete(ctx, "task_1"); err != nil {
+		t.Fatalf("Expected a task with legacy tags to complete, got %v", err)
+	}
+	got, err := store.GetByID(ctx, "task_1")
+	if err != nil {
+		t.Fatalf("Unexpected error getting task: %v", err)
+	}
+	if !got.Completed || len(got.Tags) != len(tags) {
+		t.Errorf("Expected the task completed with its tags kept, got %+v", got)
+	}
+
+	// New tags still go through the limits
+	var list TagList
+	if err := list.Set("two words"); err == nil {
+		t.Error("Expected a flag tag with a space to be rejected")
+	}
+	if _, err := tm.RenameTag(ctx, "t0", strings.Repeat("x", 31)); err == nil {
+		t.Error("Expected renaming to an over-long tag to be rejected")
+	}
+}
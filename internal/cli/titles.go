@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"fmt"
+
+	"go-fun/internal/task"
+)
+
+// SetStrictTitles makes adds and updates reject titles with tabs, line
+// breaks or surrounding whitespace instead of normalizing them
+func (tm *TaskManager) SetStrictTitles(enabled bool) {
+	tm.strictTitles = enabled
+}
+
+// checkTitle applies the strict title rules when they are enabled
+func (tm *TaskManager) checkTitle(title string) error {
+	if !tm.strictTitles {
+		return nil
+	}
+	if err := task.ValidateStrictTitle(title); err != nil {
+		return fmt.Errorf("invalid task: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// undoHistory is how many operations the undo log keeps
+const undoHistory = 50
+
+// Operation is one line of the undo log: the events of a single command,
+// undone together
+type Operation struct {
+	Timestamp time.Time `json:"ts"`
+	Events    []Event   `json:"events"`
+}
+
+// SetUndoLog sets the file recording recent operations for Undo. An empty
+// path disables recording.
+func (tm *TaskManager) SetUndoLog(path string) {
+	tm.undoLog = path
+}
+
+// beginOp starts an operation; events logged until the matching endOp are
+// undone as one. Operations nest, and only the outermost one is recorded.
+func (tm *TaskManager) beginOp() {
+	tm.opDepth++
+}
+
+// endOp finishes an operation started by beginOp, recording its events. A
+// recording failure is stored in *errp unless it already holds an error.
+func (tm *TaskManager) endOp(errp *error) {
+	tm.opDepth--
+	if tm.opDepth > 0 || len(tm.opEvents) == 0 {
+		return
+	}
+	events := tm.opEvents
+	tm.opEvents = nil
+	if err := tm.recordOp(events); err != nil && *errp == nil {
+		*errp = err
+	}
+}
+
+// trackEvent adds an event to the current operation, or records it as an
+// operation of its own outside one
+func (tm *TaskManager) trackEvent(e Event) error {
+	if tm.undoLog == "" || tm.undoing {
+		return nil
+	}
+	if tm.opDepth > 0 {
+		tm.opEvents = append(tm.opEvents, e)
+		return nil
+	}
+	return tm.recordOp([]Event{e})
+}
+
+// recordOp appends an operation to the undo log, dropping the oldest
+// beyond undoHistory
+func (tm *TaskManager) recordOp(events []Event) error {
+	ops, err := tm.readUndoLog()
+	if err != nil {
+		return err
+	}
+	ops = append(ops, Operation{Timestamp: time.Now(), Events: events})
+	if len(ops) > undoHistory {
+		ops = ops[len(ops)-undoHistory:]
+	}
+	return tm.writeUndoLog(ops)
+}
+
+// readUndoLog returns the recorded operations, oldest first
+func (tm *TaskManager) readUndoLog() ([]Operation, error) {
+	data, err := os.ReadFile(tm.undoLog)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read undo log %s: %w", tm.undoLog, err)
+	}
+
+	var ops []Operation
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var op Operation
+		if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			return nil, fmt.Errorf("failed to parse undo log %s: %w", tm.undoLog, err)
+		}
+		ops = append(ops, op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read undo log %s: %w", tm.undoLog, err)
+	}
+	return ops, nil
+}
+
+// writeUndoLog replaces the undo log atomically
+func (tm *TaskManager) writeUndoLog(ops []Operation) error {
+	var buf bytes.Buffer
+	for _, op := range ops {
+		data, err := json.Marshal(op)
+		if err != nil {
+			return fmt.Errorf("failed to marshal operation: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(tm.undoLog), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for undo log: %w", err)
+	}
+	tempFile := tm.undoLog + ".tmp"
+	if err := os.WriteFile(tempFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write undo log: %w", err)
+	}
+	if err := os.Rename(tempFile, tm.undoLog); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to write undo log: %w", err)
+	}
+	return nil
+}
+
+// Undo reverses the most recent recorded operation: added tasks are
+// removed, deleted tasks are restored and completed or updated tasks go back
+// to their prior state, timestamps included. It refuses when a task the
+// operation touched has changed since, as reverting would lose that change.
+// It reports whether anything was undone.
+func (tm *TaskManager) Undo(ctx context.Context) (bool, error) {
+	if tm.undoLog == "" {
+		return false, fmt.Errorf("undo is not available: no undo log configured")
+	}
+
+	ops, err := tm.readUndoLog()
+	if err != nil {
+		return false, err
+	}
+	if len(ops) == 0 {
+		fmt.Fprintln(tm.out, "Nothing to undo.")
+		return false, nil
+	}
+	last := ops[len(ops)-1]
+
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	if err := checkUnchanged(tasks, last); err != nil {
+		return false, err
+	}
+
+	// Walk the events backwards so later changes are reverted first
+	for i := len(last.Events) - 1; i >= 0; i-- {
+		tasks = revertEvent(tasks, last.Events[i])
+	}
+
+	if err := tm.storage.Save(ctx, tasks); err != nil {
+		return false, fmt.Errorf("failed to save tasks: %w", err)
+	}
+	if err := tm.writeUndoLog(ops[:len(ops)-1]); err != nil {
+		return false, err
+	}
+
+	// The reverted changes still reach the audit log, but not the undo log
+	tm.undoing = true
+	defer func() { tm.undoing = false }()
+	for i := len(last.Events) - 1; i >= 0; i-- {
+		e := last.Events[i]
+		op := "update"
+		switch e.Op {
+		case "add":
+			op = "delete"
+		case "delete":
+			op = "add"
+		}
+		if err := tm.logEvent(op, e.ID, e.After, e.Before); err != nil {
+			return false, err
+		}
+	}
+
+	fmt.Fprintf(tm.out, "%s Undid %s\n", tm.Icons().Success, describeOp(last))
+	return true, nil
+}
+
+// checkUnchanged reports an error unless every task op touched is still in
+// the state its last event left it: absent after a delete, otherwise with
+// the same UpdatedAt, which every change to a task moves on
+func checkUnchanged(tasks []*task.Task, op Operation) error {
+	byID := make(map[string]*task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	checked := make(map[string]bool, len(op.Events))
+	for i := len(op.Events) - 1; i >= 0; i-- {
+		e := op.Events[i]
+		if checked[e.ID] {
+			continue
+		}
+		checked[e.ID] = true
+
+		current, ok := byID[e.ID]
+		switch {
+		case e.After == nil && ok:
+			return fmt.Errorf("cannot undo %s: task %s was added again since", describeOp(op), e.ID)
+		case e.After != nil && !ok:
+			return fmt.Errorf("cannot undo %s: task %s was removed since", describeOp(op), e.ID)
+		case e.After != nil && !current.UpdatedAt.Equal(e.After.UpdatedAt):
+			return fmt.Errorf("cannot undo %s: task %s changed since", describeOp(op), e.ID)
+		}
+	}
+	return nil
+}
+
+// revertEvent applies the inverse of e to tasks
+func revertEvent(tasks []*task.Task, e Event) []*task.Task {
+	for i, t := range tasks {
+		if t.ID != e.ID {
+			continue
+		}
+		if e.Before == nil {
+			return append(tasks[:i], tasks[i+1:]...)
+		}
+		tasks[i] = e.Before
+		return tasks
+	}
+	if e.Before != nil {
+		tasks = append(tasks, e.Before)
+	}
+	return tasks
+}
+
+// describeOp summarizes an operation such as "delete of Buy milk" or
+// "complete of 3 tasks"
+func describeOp(op Operation) string {
+	first := op.Events[0]
+	if len(op.Events) > 1 {
+		return fmt.Sprintf("%s of %d tasks", first.Op, len(op.Events))
+	}
+	t := first.Before
+	if t == nil {
+		t = first.After
+	}
+	return fmt.Sprintf("%s of %s (%s)", first.Op, t.Title, first.ID)
+}
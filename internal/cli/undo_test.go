@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func newUndoManager(t *testing.T) (*TaskManager, storage.Storage) {
+	t.Helper()
+	tempDir, err := os.MkdirTemp("", "go-fun-undo-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+
+	store := storage.NewJSONFileStorage(filepath.Join(tempDir, "tasks.json"))
+	tm := NewTaskManager(store)
+	tm.SetOutput(&bytes.Buffer{})
+	tm.SetUndoLog(filepath.Join(tempDir, "undo.jsonl"))
+	return tm, store
+}
+
+func addUndoFixture(t *testing.T, store storage.Storage) *task.Task {
+	t.Helper()
+	created := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	original := &task.Task{
+		ID:          "keep-me",
+		Title:       "Write report",
+		Description: "Q3 numbers",
+		Priority:    task.High,
+		DueDate:     time.Date(2025, 6, 10, 17, 0, 0, 0, time.UTC),
+		CreatedAt:   created,
+		UpdatedAt:   created.Add(time.Hour),
+		Tags:        []string{"work"},
+	}
+	if err := store.Save(context.Background(), []*task.Task{original}); err != nil {
+		t.Fatalf("Unexpected error saving tasks: %v", err)
+	}
+	loaded, err := store.GetByID(context.Background(), original.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error getting task: %v", err)
+	}
+	return loaded
+}
+
+func TestTaskManagerUndoDelete(t *testing.T) {
+	tm, store := newUndoManager(t)
+	ctx := context.Background()
+	original := addUndoFixture(t, store)
+
+	if err := tm.Delete(ctx, original.ID); err != nil {
+		t.Fatalf("Unexpected error deleting task: %v", err)
+	}
+	undone, err := tm.Undo(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error undoing: %v", err)
+	}
+	if !undone {
+		t.Fatal("Expected the delete to be undone")
+	}
+
+	restored, err := store.GetByID(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("Expected deleted task to be restored: %v", err)
+	}
+	if !reflect.DeepEqual(restored, original) {
+		t.Errorf("Expected exact prior state:\n got %+v\nwant %+v", restored, original)
+	}
+
+	// The log is now empty
+	if undone, err := tm.Undo(ctx); err != nil || undone {
+		t.Errorf("Expected nothing left to undo, got %v, %v", undone, err)
+	}
+}
+
+func TestTaskManagerUndoUpdate(t *testing.T) {
+	tm, store := newUndoManager(t)
+	ctx := context.Background()
+	original := addUndoFixture(t, store)
+
+	if err := tm.Update(ctx, original.ID, "Rewritten", "", task.Low, time.Time{}, nil); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+	if _, err := tm.Undo(ctx); err != nil {
+		t.Fatalf("Unexpected error undoing: %v", err)
+	}
+
+	restored, err := store.GetByID(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error getting task: %v", err)
+	}
+	if !reflect.DeepEqual(restored, original) {
+		t.Errorf("Expected exact prior state:\n got %+v\nwant %+v", restored, original)
+	}
+}
+
+func TestTaskManagerUndoGroupsOperation(t *testing.T) {
+	tm, store := newUndoManager(t)
+	ctx := context.Background()
+	parent := addUndoFixture(t, store)
+
+	if err := tm.Add(ctx, "Child", "", task.Medium, time.Time{}, nil); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	tasks, _ := store.Load(ctx)
+	child := tasks[1]
+	child.ParentID = parent.ID
+	if err := store.Update(ctx, child.ID, child); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+
+	// Completing the parent completes the child too; one undo reverts both
+	if err := tm.Complete(ctx, parent.ID); err != nil {
+		t.Fatalf("Unexpected error completing task: %v", err)
+	}
+	if _, err := tm.Undo(ctx); err != nil {
+		t.Fatalf("Unexpected error undoing: %v", err)
+	}
+	tasks, _ = store.Load(ctx)
+	for _, tt := range tasks {
+		if tt.Completed {
+			t.Errorf("Expected %s to be pending again", tt.ID)
+		}
+	}
+
+	// The add is still recorded underneath
+	if _, err := tm.Undo(ctx); err != nil {
+		t.Fatalf("Unexpected error undoing: %v", err)
+	}
+	if tasks, _ = store.Load(ctx); len(tasks) != 1 {
+		t.Errorf("Expected the added task to be removed, got %d tasks", len(tasks))
+	}
+}
+
+func TestTaskManagerUndoHistoryLimit(t *testing.T) {
+	tm, _ := newUndoManager(t)
+	ctx := context.Background()
+
+	for i := 0; i < undoHistory+5; i++ {
+		if err := tm.Add(ctx, "Task", "", task.Medium, time.Time{}, nil); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+	ops, err := tm.readUndoLog()
+	if err != nil {
+		t.Fatalf("Unexpected error reading undo log: %v", err)
+	}
+	if len(ops) != undoHistory {
+		t.Errorf("Expected %d operations kept, got %d", undoHistory, len(ops))
+	}
+}
+
+func TestTaskManagerUndoPurge(t *testing.T) {
+	tm, store := newUndoManager(t)
+	ctx := context.Background()
+	original := addUndoFixture(t, store)
+
+	if err := tm.Complete(ctx, original.ID); err != nil {
+		t.Fatalf("Unexpected error completing task: %v", err)
+	}
+	if err := tm.Uncomplete(ctx, original.ID); err != nil {
+		t.Fatalf("Unexpected error uncompleting task: %v", err)
+	}
+	if err := tm.Complete(ctx, original.ID); err != nil {
+		t.Fatalf("Unexpected error completing task: %v", err)
+	}
+	if _, err := tm.Purge(ctx, true); err != nil {
+		t.Fatalf("Unexpected error purging: %v", err)
+	}
+
+	// Undo the purge, the second complete and the uncomplete in turn
+	for i := 0; i < 3; i++ {
+		if _, err := tm.Undo(ctx); err != nil {
+			t.Fatalf("Unexpected error undoing step %d: %v", i+1, err)
+		}
+	}
+
+	restored, err := store.GetByID(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("Expected purged task to be restored: %v", err)
+	}
+	if !restored.Completed {
+		t.Error("Expected undoing the uncomplete to leave the task completed")
+	}
+}
+
+func TestTaskManagerUndoRefusesStaleOperation(t *testing.T) {
+	tm, store := newUndoManager(t)
+	ctx := context.Background()
+	original := addUndoFixture(t, store)
+
+	if err := tm.Update(ctx, original.ID, "Write summary", "", task.High, time.Time{}, nil); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+
+	// A change the undo log does not know about
+	changed, err := store.GetByID(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error getting task: %v", err)
+	}
+	changed.Title = "Changed elsewhere"
+	changed.UpdatedAt = changed.UpdatedAt.Add(time.Second)
+	if err := store.Update(ctx, changed.ID, changed); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+
+	if _, err := tm.Undo(ctx); err == nil {
+		t.Fatal("Expected undo to refuse a task changed since the operation")
+	}
+	current, err := store.GetByID(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error getting task: %v", err)
+	}
+	if current.Title != "Changed elsewhere" {
+		t.Errorf("Expected the refused undo to leave the task alone, got title %q", current.Title)
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// clearScreen moves the cursor home and erases the terminal
+const clearScreen = "\033[H\033[2J"
+
+// fileStamp identifies a version of a file by modification time and size.
+// The zero value stands for a missing file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statStamp returns the current stamp of path
+func statStamp(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fileStamp{}, nil
+	}
+	if err != nil {
+		return fileStamp{}, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// Watch renders the task list, then polls path every interval and renders it
+// again whenever the file changes. It returns nil once ctx is done.
+func (tm *TaskManager) Watch(ctx context.Context, path string, interval time.Duration, opts ListOptions) error {
+	if interval <= 0 {
+		return fmt.Errorf("watch interval must be positive: %s", interval)
+	}
+
+	last, err := statStamp(path)
+	if err != nil {
+		return err
+	}
+	if err := tm.renderWatch(ctx, opts); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := statStamp(path)
+		if err != nil {
+			return err
+		}
+		if current == last {
+			continue
+		}
+		last = current
+
+		if err := tm.renderWatch(ctx, opts); err != nil {
+			return err
+		}
+	}
+}
+
+// renderWatch redraws the list below a header with the refresh time
+func (tm *TaskManager) renderWatch(ctx context.Context, opts ListOptions) error {
+	if tm.color {
+		fmt.Fprint(tm.out, clearScreen)
+	}
+	fmt.Fprintf(tm.out, "Last refresh: %s (Ctrl-C to stop)\n", time.Now().Format("2006-01-02 15:04:05"))
+	return tm.List(ctx, opts)
+}
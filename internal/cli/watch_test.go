@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/filter"
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestWatchRerendersOnChange(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "watch_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "tasks.json")
+	store := storage.NewJSONFileStorage(path)
+	tm := NewTaskManager(store)
+
+	outR, outW := io.Pipe()
+	tm.SetOutput(outW)
+
+	high := filter.PriorityFilter{Level: task.High}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- tm.Watch(ctx, path, 10*time.Millisecond, ListOptions{Priority: &high})
+	}()
+
+	lines := bufio.NewScanner(outR)
+	waitFor := func(want string) {
+		t.Helper()
+		for lines.Scan() {
+			if strings.Contains(lines.Text(), want) {
+				return
+			}
+		}
+		t.Fatalf("Output ended before %q appeared", want)
+	}
+
+	waitFor("Last refresh")
+	waitFor("No tasks found.")
+
+	// A separate store stands in for another process editing the file
+	other := storage.NewJSONFileStorage(path)
+	for _, tk := range []*task.Task{
+		task.NewTask("Low item", "Desc", task.Low, time.Time{}, nil),
+		task.NewTask("Urgent item", "Desc", task.High, time.Time{}, nil),
+	} {
+		if err := other.Add(context.Background(), tk); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	var refresh []string
+	for lines.Scan() && !strings.Contains(lines.Text(), "Urgent item") {
+		refresh = append(refresh, lines.Text())
+	}
+	if strings.Contains(strings.Join(refresh, "\n"), "Low item") {
+		t.Error("Expected the priority filter to hide the low-priority task")
+	}
+
+	cancel()
+	// Unblock any write still in flight so Watch can observe the cancellation
+	outR.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected nil error after cancellation, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch did not return after the context was cancelled")
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// WorkloadDay sums the estimated effort of the tasks due on one day
+type WorkloadDay struct {
+	Date    time.Time
+	Minutes int
+	Tasks   int
+	Over    bool // Minutes exceeds the daily capacity
+}
+
+// buildWorkload aggregates the estimates of pending tasks due on each of the
+// n days starting today. A capacity of zero or less flags no day.
+func buildWorkload(tasks []*task.Task, now time.Time, n, capacity int) ([]WorkloadDay, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("day count must be positive: %d", n)
+	}
+
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	days := make([]WorkloadDay, n)
+	for i := range days {
+		days[i].Date = start.AddDate(0, 0, i)
+	}
+
+	for _, t := range tasks {
+		if t.Completed || t.DueDate.IsZero() {
+			continue
+		}
+		due := t.DueDate.In(now.Location())
+		day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, now.Location())
+		for i := range days {
+			if days[i].Date.Equal(day) {
+				days[i].Minutes += t.EstimateMinutes
+				days[i].Tasks++
+				break
+			}
+		}
+	}
+
+	for i := range days {
+		days[i].Over = capacity > 0 && days[i].Minutes > capacity
+	}
+	return days, nil
+}
+
+// ParseEstimate parses an effort such as "90m", "1h30m" or a bare number of
+// minutes
+func ParseEstimate(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if minutes, err := strconv.Atoi(s); err == nil {
+		if minutes < 0 {
+			return 0, fmt.Errorf("estimate cannot be negative: %s", s)
+		}
+		return minutes, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid estimate: %s. Use minutes or a duration like 1h30m", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("estimate cannot be negative: %s", s)
+	}
+	return int(d.Round(time.Minute) / time.Minute), nil
+}
+
+// formatMinutes renders a minute count as hours and minutes, e.g. "1h30m"
+func formatMinutes(minutes int) string {
+	h, m := minutes/60, minutes%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%02dm", h, m)
+	}
+}
+
+// Workload displays the estimated effort due on each of the next n days,
+// flagging days over capacity minutes
+func (tm *TaskManager) Workload(ctx context.Context, n, capacity int) error {
+	tasks, err := tm.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	days, err := buildWorkload(tasks, time.Now(), n, capacity)
+	if err != nil {
+		return err
+	}
+
+	icons := tm.Icons()
+	if capacity > 0 {
+		fmt.Fprintf(tm.out, "\n%s Workload for the next %d days (capacity %s/day)\n", icons.Stats, n, formatMinutes(capacity))
+	} else {
+		fmt.Fprintf(tm.out, "\n%s Workload for the next %d days\n", icons.Stats, n)
+	}
+	fmt.Fprintln(tm.out, strings.Repeat("=", 50))
+
+	over := 0
+	for _, d := range days {
+		line := fmt.Sprintf("%s %s | %6s (%d tasks)", d.Date.Format("2006-01-02"), d.Date.Format("Mon"), formatMinutes(d.Minutes), d.Tasks)
+		if d.Over {
+			over++
+			line += fmt.Sprintf(" %s over by %s", icons.Overdue, formatMinutes(d.Minutes-capacity))
+		}
+		fmt.Fprintln(tm.out, line)
+	}
+
+	if over > 0 {
+		fmt.Fprintf(tm.out, "\n%d day(s) over capacity\n", over)
+	}
+	fmt.Fprintln(tm.out)
+
+	return nil
+}
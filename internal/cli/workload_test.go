@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+func TestBuildWorkloadFlagsOverCapacity(t *testing.T) {
+	now := time.Date(2025, 6, 15, 18, 30, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time {
+		return time.Date(2025, 6, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tasks := []*task.Task{
+		{ID: "1", DueDate: at(15, 20), EstimateMinutes: 120},
+		{ID: "2", DueDate: at(16, 9), EstimateMinutes: 300},
+		{ID: "3", DueDate: at(16, 17), EstimateMinutes: 240},
+		{ID: "4", DueDate: at(16, 12), EstimateMinutes: 600, Completed: true}, // done, not counted
+		{ID: "5", DueDate: at(17, 10)},                                        // no estimate
+		{ID: "6", DueDate: at(25, 10), EstimateMinutes: 999},                  // outside the range
+		{ID: "7", EstimateMinutes: 999},                                       // no due date
+	}
+
+	days, err := buildWorkload(tasks, now, 3, 480)
+	if err != nil {
+		t.Fatalf("Unexpected error building workload: %v", err)
+	}
+
+	expected := []struct {
+		date    time.Time
+		minutes int
+		tasks   int
+		over    bool
+	}{
+		{at(15, 0), 120, 1, false},
+		{at(16, 0), 540, 2, true},
+		{at(17, 0), 0, 1, false},
+	}
+
+	if len(days) != len(expected) {
+		t.Fatalf("Expected %d days, got %d", len(expected), len(days))
+	}
+	for i, want := range expected {
+		got := days[i]
+		if !got.Date.Equal(want.date) {
+			t.Errorf("Day %d: expected date %v, got %v", i, want.date, got.Date)
+		}
+		if got.Minutes != want.minutes || got.Tasks != want.tasks {
+			t.Errorf("Day %d: expected %d minutes over %d tasks, got %d over %d", i, want.minutes, want.tasks, got.Minutes, got.Tasks)
+		}
+		if got.Over != want.over {
+			t.Errorf("Day %d: expected over capacity %v, got %v", i, want.over, got.Over)
+		}
+	}
+
+	// Without a capacity nothing is flagged
+	days, err = buildWorkload(tasks, now, 3, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error building workload: %v", err)
+	}
+	for i, d := range days {
+		if d.Over {
+			t.Errorf("Day %d: expected no flag without a capacity", i)
+		}
+	}
+
+	if _, err := buildWorkload(tasks, now, 0, 480); err == nil {
+		t.Error("Expected error for a non-positive day count")
+	}
+}
+
+func TestParseEstimate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		wantErr  bool
+	}{
+		{"45", 45, false},
+		{"90m", 90, false},
+		{"1h30m", 90, false},
+		{"2h", 120, false},
+		{"-5", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEstimate(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseEstimate(%q): expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseEstimate(%q): unexpected error: %v", tt.input, err)
+		} else if got != tt.expected {
+			t.Errorf("ParseEstimate(%q): expected %d, got %d", tt.input, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"go-fun/internal/task"
+)
+
+// yamlTask is the YAML layout of a task: priorities by name, times as
+// RFC3339 strings and empty fields left out, so files read well by hand
+type yamlTask struct {
+	ID              string     `yaml:"id"`
+	Title           string     `yaml:"title"`
+	Description     string     `yaml:"description,omitempty"`
+	Priority        string     `yaml:"priority"`
+	DueDate         string     `yaml:"due_date,omitempty"`
+	Completed       bool       `yaml:"completed,omitempty"`
+	CompletedAt     string     `yaml:"completed_at,omitempty"`
+	CreatedAt       string     `yaml:"created_at,omitempty"`
+	UpdatedAt       string     `yaml:"updated_at,omitempty"`
+	Tags            []string   `yaml:"tags,omitempty"`
+	RelatedTo       []string   `yaml:"related_to,omitempty"`
+	DependsOn       []string   `yaml:"depends_on,omitempty"`
+	Recurrence      string     `yaml:"recurrence,omitempty"`
+	ParentID        string     `yaml:"parent_id,omitempty"`
+	EstimateMinutes int        `yaml:"estimate_minutes,omitempty"`
+	WasLate         bool       `yaml:"was_late,omitempty"`
+	StartDate       string     `yaml:"start_date,omitempty"`
+	Notes           []yamlNote `yaml:"notes,omitempty"`
+}
+
+// yamlNote is the YAML layout of a note
+type yamlNote struct {
+	Text      string `yaml:"text"`
+	CreatedAt string `yaml:"created_at"`
+}
+
+// writeYAML exports tasks as a YAML list
+func writeYAML(w io.Writer, tasks []*task.Task) error {
+	out := make([]yamlTask, 0, len(tasks))
+	for _, t := range tasks {
+		out = append(out, toYAMLTask(t))
+	}
+
+	data, err := yaml.Marshal(out)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write YAML: %w", err)
+	}
+	return nil
+}
+
+// parseYAMLImport reads a list written by the YAML exporter
+func parseYAMLImport(data []byte) ([]*task.Task, error) {
+	var in []yamlTask
+	if err := yaml.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	tasks := make([]*task.Task, 0, len(in))
+	for i, y := range in {
+		t, err := fromYAMLTask(y)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse YAML task %d: %w", i+1, err)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+func toYAMLTask(t *task.Task) yamlTask {
+	y := yamlTask{
+		ID:              t.ID,
+		Title:           t.Title,
+		Description:     t.Description,
+		Priority:        strings.ToLower(t.Priority.String()),
+		DueDate:         formatYAMLTime(t.DueDate),
+		Completed:       t.Completed,
+		CompletedAt:     formatYAMLTime(t.CompletedAt),
+		CreatedAt:       formatYAMLTime(t.CreatedAt),
+		UpdatedAt:       formatYAMLTime(t.UpdatedAt),
+		Tags:            t.Tags,
+		RelatedTo:       t.RelatedTo,
+		DependsOn:       t.DependsOn,
+		ParentID:        t.ParentID,
+		EstimateMinutes: t.EstimateMinutes,
+		WasLate:         t.WasLate,
+		StartDate:       formatYAMLTime(t.StartDate),
+	}
+	for _, n := range t.Notes {
+		y.Notes = append(y.Notes, yamlNote{Text: n.Text, CreatedAt: formatYAMLTime(n.CreatedAt)})
+	}
+	if t.Recurrence != nil {
+		y.Recurrence = t.Recurrence.Interval
+		if t.Recurrence.Count > 0 {
+			y.Recurrence += fmt.Sprintf(":%d", t.Recurrence.Count)
+		}
+	}
+	return y
+}
+
+func fromYAMLTask(y yamlTask) (*task.Task, error) {
+	t := &task.Task{
+		ID:              y.ID,
+		Title:           y.Title,
+		Description:     y.Description,
+		Completed:       y.Completed,
+		Tags:            y.Tags,
+		RelatedTo:       y.RelatedTo,
+		DependsOn:       y.DependsOn,
+		ParentID:        y.ParentID,
+		EstimateMinutes: y.EstimateMinutes,
+		WasLate:         y.WasLate,
+	}
+
+	var err error
+	if t.Priority, err = task.ParsePriority(y.Priority); err != nil {
+		return nil, err
+	}
+	for _, field := range []struct {
+		dst *time.Time
+		src string
+	}{
+		{&t.DueDate, y.DueDate},
+		{&t.CompletedAt, y.CompletedAt},
+		{&t.CreatedAt, y.CreatedAt},
+		{&t.UpdatedAt, y.UpdatedAt},
+		{&t.StartDate, y.StartDate},
+	} {
+		if *field.dst, err = parseYAMLTime(field.src); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range y.Notes {
+		note := task.Note{Text: n.Text}
+		if note.CreatedAt, err = parseYAMLTime(n.CreatedAt); err != nil {
+			return nil, err
+		}
+		t.Notes = append(t.Notes, note)
+	}
+	if y.Recurrence != "" {
+		if t.Recurrence, err = task.ParseRecurrence(y.Recurrence); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// formatYAMLTime writes t as RFC3339, keeping any fraction of a second so
+// the import restores the exact time; zero times are left empty
+func formatYAMLTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func parseYAMLTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected RFC3339", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/storage"
+	"go-fun/internal/task"
+)
+
+func TestYAMLRoundTrip(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-yaml-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	created := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	zone := time.FixedZone("CEST", 2*60*60)
+	tasks := []*task.Task{
+		{
+			ID: "full", Title: "Write: the report", Description: "Q3 numbers\nand charts",
+			Priority: task.Urgent, DueDate: time.Date(2025, 6, 3, 17, 0, 0, 0, zone),
+			Completed: true, CompletedAt: created.Add(36*time.Hour + 500*time.Millisecond), WasLate: true,
+			CreatedAt: created, UpdatedAt: created.Add(time.Hour),
+			Tags: []string{"work", "q3"}, RelatedTo: []string{"plain"}, DependsOn: []string{"plain"},
+			Recurrence: &task.Recurrence{Interval: "weekly", Count: 3}, ParentID: "plain", EstimateMinutes: 90,
+		},
+		{ID: "plain", Title: "Plain", Priority: task.Low, CreatedAt: created, UpdatedAt: created},
+	}
+
+	tm := NewTaskManager(storage.NewInMemoryStorage())
+	path := filepath.Join(tempDir, "tasks.yaml")
+	if err := tm.exportFormat(tasks, "yaml", path, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting YAML: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading YAML: %v", err)
+	}
+	for _, want := range []string{"priority: urgent", "due_date: \"2025-06-03T17:00:00+02:00\"", "recurrence: weekly:3"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected YAML to contain %q, got:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "0001-01-01") {
+		t.Error("Expected zero times to be left out")
+	}
+
+	parsed, err := parseYAMLImport(data)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing YAML: %v", err)
+	}
+	if len(parsed) != len(tasks) {
+		t.Fatalf("Expected %d tasks, got %d", len(tasks), len(parsed))
+	}
+	for i, want := range tasks {
+		got := parsed[i]
+		for _, pair := range [][2]time.Time{
+			{got.DueDate, want.DueDate}, {got.CompletedAt, want.CompletedAt},
+			{got.CreatedAt, want.CreatedAt}, {got.UpdatedAt, want.UpdatedAt},
+		} {
+			if !pair[0].Equal(pair[1]) {
+				t.Errorf("%s: expected time %v, got %v", want.ID, pair[1], pair[0])
+			}
+		}
+
+		// Times compare by instant above; zone pointers need not match
+		gotCopy, wantCopy := *got, *want
+		gotCopy.DueDate, gotCopy.CompletedAt, gotCopy.CreatedAt, gotCopy.UpdatedAt = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+		wantCopy.DueDate, wantCopy.CompletedAt, wantCopy.CreatedAt, wantCopy.UpdatedAt = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+		if !reflect.DeepEqual(gotCopy, wantCopy) {
+			t.Errorf("%s: expected %+v, got %+v", want.ID, wantCopy, gotCopy)
+		}
+	}
+
+	if _, err := parseYAMLImport([]byte("- id: a\n  title: A\n  priority: huge\n")); err == nil {
+		t.Error("Expected error for an unknown priority")
+	}
+	if _, err := parseYAMLImport([]byte("- id: a\n  title: A\n  priority: low\n  due_date: tomorrow\n")); err == nil {
+		t.Error("Expected error for a non-RFC3339 time")
+	}
+}
+
+func TestTaskManagerImportYAML(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-yaml-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	source := storage.NewInMemoryStorage()
+	if err := source.Add(ctx, task.NewTask("Template", "From YAML", task.High, time.Time{}, []string{"team"})); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	exporter := NewTaskManager(source)
+	path := filepath.Join(tempDir, "tasks.yml")
+	if err := exporter.ExportTasks(ctx, "yml", path, ExportOptions{}); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+
+	importer := NewTaskManager(storage.NewInMemoryStorage())
+	importer.SetOutput(&bytes.Buffer{})
+	result, err := importer.ImportTasks(ctx, "yaml", path, MergeSkip)
+	if err != nil {
+		t.Fatalf("Unexpected error importing: %v", err)
+	}
+	if result.Added != 1 {
+		t.Errorf("Expected 1 task added, got %+v", result)
+	}
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go-fun/internal/task"
+)
+
+// Config holds user preferences loaded from the data directory
+type Config struct {
+	// PriorityLabels overrides the display name of a priority level, keyed by
+	// its canonical name ("low", "medium", "high", "urgent"). The stored value
+	// is unchanged.
+	PriorityLabels map[string]string `json:"priority_labels,omitempty"`
+
+	// Locale is a BCP 47 tag (e.g. "de", "sv") used to collate titles when
+	// sorting. Empty means a plain case-insensitive comparison.
+	Locale string `json:"locale,omitempty"`
+
+	// EventLog is a file that receives one JSON line per task change, for
+	// auditing. Empty disables the log.
+	EventLog string `json:"event_log,omitempty"`
+
+	// TagFromBranch tags new tasks with the current git branch by default
+	TagFromBranch bool `json:"tag_from_branch,omitempty"`
+
+	// IconSet picks the glyphs used in output: "emoji" (default), "ascii"
+	// or "nerdfont"
+	IconSet string `json:"icon_set,omitempty"`
+
+	// DailyCapacityMinutes is the effort a day can hold before the workload
+	// report flags it. Zero disables the flag.
+	DailyCapacityMinutes int `json:"daily_capacity_minutes,omitempty"`
+
+	// RequireSubtasks refuses to complete a task while it has open subtasks.
+	// By default the subtasks are completed along with it.
+	RequireSubtasks bool `json:"require_subtasks,omitempty"`
+
+	// AutoCompleteParent completes a task once its last open subtask is
+	// completed
+	AutoCompleteParent bool `json:"auto_complete_parent,omitempty"`
+
+	// StrictTitles rejects titles with tabs, line breaks or surrounding
+	// whitespace instead of normalizing them
+	StrictTitles bool `json:"strict_titles,omitempty"`
+
+	// Views are saved list filters, keyed by name, holding the list flags
+	// exactly as typed
+	Views map[string][]string `json:"views,omitempty"`
+}
+
+// Default returns a configuration with no overrides
+func Default() *Config {
+	return &Config{}
+}
+
+// Load reads the configuration file, returning defaults if it does not exist
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	cfg := Default()
+	if len(data) == 0 {
+		return cfg, nil
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the configuration file atomically
+func (c *Config) Save(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+	return nil
+}
+
+// PriorityLabel returns the display name for a priority
+func (c *Config) PriorityLabel(p task.Priority) string {
+	if label := c.PriorityLabels[priorityKey(p)]; label != "" {
+		return label
+	}
+	return p.String()
+}
+
+// SetPriorityLabel sets or, with an empty label, clears a display name
+func (c *Config) SetPriorityLabel(p task.Priority, label string) {
+	if label == "" {
+		delete(c.PriorityLabels, priorityKey(p))
+		return
+	}
+	if c.PriorityLabels == nil {
+		c.PriorityLabels = make(map[string]string)
+	}
+	c.PriorityLabels[priorityKey(p)] = label
+}
+
+// ParsePriority parses a priority from its built-in or custom name
+func (c *Config) ParsePriority(s string) (task.Priority, error) {
+	if p, err := task.ParsePriority(s); err == nil {
+		return p, nil
+	}
+	for key, label := range c.PriorityLabels {
+		if strings.EqualFold(strings.TrimSpace(s), label) {
+			return task.ParsePriority(key)
+		}
+	}
+	return task.Medium, fmt.Errorf("invalid priority: %s. Use: %s", s, strings.Join(c.priorityNames(), ", "))
+}
+
+// SetView saves the list flags for a view, replacing any of the same name
+func (c *Config) SetView(name string, args []string) {
+	if c.Views == nil {
+		c.Views = make(map[string][]string)
+	}
+	c.Views[name] = append([]string(nil), args...)
+}
+
+// DeleteView removes a view, reporting whether it existed
+func (c *Config) DeleteView(name string) bool {
+	if _, ok := c.Views[name]; !ok {
+		return false
+	}
+	delete(c.Views, name)
+	return true
+}
+
+// ViewArgs returns a view's saved flags followed by extra, so flags given
+// when running the view override the saved ones
+func (c *Config) ViewArgs(name string, extra []string) ([]string, error) {
+	saved, ok := c.Views[name]
+	if !ok {
+		return nil, fmt.Errorf("no view named %q", name)
+	}
+	return append(append([]string(nil), saved...), extra...), nil
+}
+
+// ViewNames lists the saved views alphabetically
+func (c *Config) ViewNames() []string {
+	names := make([]string, 0, len(c.Views))
+	for name := range c.Views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// priorityNames lists the accepted priority names, lowest first
+func (c *Config) priorityNames() []string {
+	names := make([]string, 0, 4)
+	for p := task.Low; p <= task.Urgent; p++ {
+		name := priorityKey(p)
+		if label := c.PriorityLabels[name]; label != "" {
+			name += "/" + label
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// priorityKey returns the canonical config key for a priority
+func priorityKey(p task.Priority) string {
+	return strings.ToLower(p.String())
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-fun/internal/task"
+)
+
+func TestPriorityLabel(t *testing.T) {
+	cfg := Default()
+	if got := cfg.PriorityLabel(task.High); got != "High" {
+		t.Errorf("Expected default label High, got %s", got)
+	}
+
+	cfg.SetPriorityLabel(task.High, "P1")
+	if got := cfg.PriorityLabel(task.High); got != "P1" {
+		t.Errorf("Expected custom label P1, got %s", got)
+	}
+
+	cfg.SetPriorityLabel(task.High, "")
+	if got := cfg.PriorityLabel(task.High); got != "High" {
+		t.Errorf("Expected label to reset to High, got %s", got)
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	cfg := Default()
+	cfg.SetPriorityLabel(task.High, "must")
+	cfg.SetPriorityLabel(task.Medium, "should")
+	cfg.SetPriorityLabel(task.Low, "could")
+
+	tests := []struct {
+		input   string
+		want    task.Priority
+		wantErr bool
+	}{
+		{"must", task.High, false},
+		{"SHOULD", task.Medium, false},
+		{"could", task.Low, false},
+		{"h", task.High, false},
+		{"medium", task.Medium, false},
+		{"won't", task.Medium, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := cfg.ParsePriority(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePriority(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParsePriority(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigSaveLoad(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-config-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "config.json")
+
+	// Missing file yields defaults
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error loading missing config: %v", err)
+	}
+	if len(cfg.PriorityLabels) != 0 {
+		t.Errorf("Expected no labels, got %v", cfg.PriorityLabels)
+	}
+
+	cfg.SetPriorityLabel(task.Low, "P3")
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Unexpected error saving config: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error loading config: %v", err)
+	}
+	if got := loaded.PriorityLabel(task.Low); got != "P3" {
+		t.Errorf("Expected label P3 after reload, got %s", got)
+	}
+}
+
+func TestConfigViews(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-config-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "config.json")
+	cfg := Default()
+	cfg.SetView("work", []string{"-p", "high", "-T", "work"})
+	cfg.SetView("later", []string{"-d", "week"})
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Unexpected error saving config: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error loading config: %v", err)
+	}
+	if got := loaded.ViewNames(); !reflect.DeepEqual(got, []string{"later", "work"}) {
+		t.Errorf("Expected views [later work], got %v", got)
+	}
+
+	// Extra flags come last so they win over the saved ones
+	args, err := loaded.ViewArgs("work", []string{"-p", "urgent"})
+	if err != nil {
+		t.Fatalf("Unexpected error expanding view: %v", err)
+	}
+	if want := []string{"-p", "high", "-T", "work", "-p", "urgent"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("Expected args %v, got %v", want, args)
+	}
+	if again, _ := loaded.ViewArgs("work", nil); len(again) != 4 {
+		t.Errorf("Expected running a view not to change it, got %v", again)
+	}
+
+	if _, err := loaded.ViewArgs("missing", nil); err == nil {
+		t.Error("Expected error for an unknown view")
+	}
+	if !loaded.DeleteView("later") || loaded.DeleteView("later") {
+		t.Error("Expected DeleteView to report whether the view existed")
+	}
+}
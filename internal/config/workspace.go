@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultWorkspace lives in the data directory itself, so data from before
+// workspaces existed stays where it was
+const DefaultWorkspace = "default"
+
+// workspaceStateFile records the active workspace inside the data directory
+const workspaceStateFile = "workspace"
+
+// workspaceName keeps names usable as a directory on every platform
+var workspaceName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// ValidateWorkspaceName reports whether name can be used for a workspace
+func ValidateWorkspaceName(name string) error {
+	if !workspaceName.MatchString(name) {
+		return fmt.Errorf("invalid workspace name %q: use lowercase letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// WorkspaceDir returns the directory holding a workspace's tasks and
+// config under root: root itself for the default workspace, root/<name>
+// for the others
+func WorkspaceDir(root, name string) string {
+	if name == "" || name == DefaultWorkspace {
+		return root
+	}
+	return filepath.Join(root, name)
+}
+
+// CurrentWorkspace returns the active workspace recorded under root, or
+// DefaultWorkspace if none was chosen
+func CurrentWorkspace(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, workspaceStateFile))
+	if os.IsNotExist(err) {
+		return DefaultWorkspace, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read workspace state: %w", err)
+	}
+
+	name := strings.TrimSpace(string(data))
+	if name == "" {
+		return DefaultWorkspace, nil
+	}
+	if err := ValidateWorkspaceName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// UseWorkspace makes name the active workspace under root, creating its
+// directory
+func UseWorkspace(root, name string) error {
+	if err := ValidateWorkspaceName(name); err != nil {
+		return err
+	}
+	dir := WorkspaceDir(root, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create workspace %s: %w", name, err)
+	}
+	if err := os.WriteFile(filepath.Join(root, workspaceStateFile), []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to save workspace state: %w", err)
+	}
+	return nil
+}
+
+// Workspaces lists the default workspace followed by every workspace
+// directory under root, sorted by name
+func Workspaces(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read data directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != DefaultWorkspace && ValidateWorkspaceName(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultWorkspace}, names...), nil
+}
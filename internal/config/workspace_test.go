@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWorkspaceDir(t *testing.T) {
+	root := filepath.Join("home", ".go-fun")
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", root},
+		{DefaultWorkspace, root},
+		{"work", filepath.Join(root, "work")},
+		{"side-project", filepath.Join(root, "side-project")},
+	}
+	for _, tt := range tests {
+		if got := WorkspaceDir(root, tt.name); got != tt.want {
+			t.Errorf("WorkspaceDir(%q) = %s, expected %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUseWorkspace(t *testing.T) {
+	root := t.TempDir()
+
+	current, err := CurrentWorkspace(root)
+	if err != nil {
+		t.Fatalf("Unexpected error reading workspace: %v", err)
+	}
+	if current != DefaultWorkspace {
+		t.Errorf("Expected %s before any use, got %s", DefaultWorkspace, current)
+	}
+
+	for _, name := range []string{"work", "personal"} {
+		if err := UseWorkspace(root, name); err != nil {
+			t.Fatalf("Unexpected error using workspace %s: %v", name, err)
+		}
+	}
+	current, err = CurrentWorkspace(root)
+	if err != nil {
+		t.Fatalf("Unexpected error reading workspace: %v", err)
+	}
+	if current != "personal" {
+		t.Errorf("Expected the last used workspace, got %s", current)
+	}
+	if info, err := os.Stat(filepath.Join(root, "work")); err != nil || !info.IsDir() {
+		t.Errorf("Expected use to create the workspace directory, got %v", err)
+	}
+
+	// Stray files and directories that aren't workspace names are skipped
+	os.WriteFile(filepath.Join(root, "tasks.json"), []byte("[]"), 0644)
+	os.Mkdir(filepath.Join(root, "Not A Workspace"), 0755)
+	names, err := Workspaces(root)
+	if err != nil {
+		t.Fatalf("Unexpected error listing workspaces: %v", err)
+	}
+	if want := []string{DefaultWorkspace, "personal", "work"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected workspaces %v, got %v", want, names)
+	}
+
+	// Switching back to the default keeps using the root directory
+	if err := UseWorkspace(root, DefaultWorkspace); err != nil {
+		t.Fatalf("Unexpected error using default workspace: %v", err)
+	}
+	if current, _ := CurrentWorkspace(root); WorkspaceDir(root, current) != root {
+		t.Errorf("Expected the default workspace to resolve to the root, got %s", WorkspaceDir(root, current))
+	}
+}
+
+func TestUseWorkspaceInvalidName(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"", "../escape", "a/b", "Work", ".hidden", "with space"} {
+		if err := UseWorkspace(root, name); err == nil {
+			t.Errorf("Expected error for workspace name %q", name)
+		}
+	}
+
+	// A hand-edited state file with a bad name is reported, not followed
+	os.WriteFile(filepath.Join(root, "workspace"), []byte("../../etc\n"), 0644)
+	if _, err := CurrentWorkspace(root); err == nil {
+		t.Error("Expected error for an invalid recorded workspace")
+	}
+}
@@ -0,0 +1,142 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"go-fun/internal/task"
+)
+
+// PriorityOp is the comparison a PriorityFilter applies
+type PriorityOp int
+
+const (
+	OpEqual PriorityOp = iota
+	OpLess
+	OpLessOrEqual
+	OpGreater
+	OpGreaterOrEqual
+)
+
+// priorityOps maps expression prefixes to operators, longest first so ">="
+// is not read as ">"
+var priorityOps = []struct {
+	symbol string
+	op     PriorityOp
+}{
+	{">=", OpGreaterOrEqual},
+	{"<=", OpLessOrEqual},
+	{">", OpGreater},
+	{"<", OpLess},
+	{"=", OpEqual},
+}
+
+// PriorityFilter matches priorities equal to, above or below a level
+type PriorityFilter struct {
+	Op    PriorityOp
+	Level task.Priority
+}
+
+// NewPriorityFilter parses a priority name such as "high" or "h", optionally
+// prefixed with a comparison like ">=medium"
+func NewPriorityFilter(input string) (PriorityFilter, error) {
+	return ParsePriorityFilter(input, task.ParsePriority)
+}
+
+// ParsePriorityFilter is NewPriorityFilter with a custom parser for the
+// level, so configured priority labels are accepted too
+func ParsePriorityFilter(input string, parse func(string) (task.Priority, error)) (PriorityFilter, error) {
+	expr := strings.TrimSpace(input)
+	op := OpEqual
+	for _, o := range priorityOps {
+		if strings.HasPrefix(expr, o.symbol) {
+			op = o.op
+			expr = strings.TrimSpace(expr[len(o.symbol):])
+			break
+		}
+	}
+
+	level, err := parse(expr)
+	if err != nil {
+		return PriorityFilter{}, fmt.Errorf("invalid priority filter %q: %w", input, err)
+	}
+	return PriorityFilter{Op: op, Level: level}, nil
+}
+
+// Matches reports whether p satisfies the filter
+func (f PriorityFilter) Matches(p task.Priority) bool {
+	switch f.Op {
+	case OpLess:
+		return p < f.Level
+	case OpLessOrEqual:
+		return p <= f.Level
+	case OpGreater:
+		return p > f.Level
+	case OpGreaterOrEqual:
+		return p >= f.Level
+	default:
+		return p == f.Level
+	}
+}
+
+// Label describes the filter for list --explain, e.g. "priority:>=high"
+func (f PriorityFilter) Label() string {
+	symbol := ""
+	for _, o := range priorityOps {
+		if o.op == f.Op && o.op != OpEqual {
+			symbol = o.symbol
+		}
+	}
+	return "priority:" + symbol + strings.ToLower(f.Level.String())
+}
+
+// PriorityRangeFilter matches priorities between optional inclusive bounds
+type PriorityRangeFilter struct {
+	Min *task.Priority
+	Max *task.Priority
+}
+
+// ParsePriorityRangeFilter builds a range from minimum and maximum priority
+// names, either of which may be empty to leave that side open
+func ParsePriorityRangeFilter(min, max string, parse func(string) (task.Priority, error)) (PriorityRangeFilter, error) {
+	var f PriorityRangeFilter
+	if min != "" {
+		p, err := parse(min)
+		if err != nil {
+			return PriorityRangeFilter{}, fmt.Errorf("invalid minimum priority: %w", err)
+		}
+		f.Min = &p
+	}
+	if max != "" {
+		p, err := parse(max)
+		if err != nil {
+			return PriorityRangeFilter{}, fmt.Errorf("invalid maximum priority: %w", err)
+		}
+		f.Max = &p
+	}
+	if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+		return PriorityRangeFilter{}, fmt.Errorf("minimum priority %s is above maximum %s", *f.Min, *f.Max)
+	}
+	return f, nil
+}
+
+// Matches reports whether p lies within the range
+func (f PriorityRangeFilter) Matches(p task.Priority) bool {
+	if f.Min != nil && p < *f.Min {
+		return false
+	}
+	return f.Max == nil || p <= *f.Max
+}
+
+// Label describes the range for list --explain, e.g. "priority:medium..high"
+func (f PriorityRangeFilter) Label() string {
+	switch {
+	case f.Min != nil && f.Max != nil:
+		return "priority:" + strings.ToLower(f.Min.String()) + ".." + strings.ToLower(f.Max.String())
+	case f.Min != nil:
+		return "priority:>=" + strings.ToLower(f.Min.String())
+	case f.Max != nil:
+		return "priority:<=" + strings.ToLower(f.Max.String())
+	}
+	return "priority:any"
+}
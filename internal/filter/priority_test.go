@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"testing"
+
+	"go-fun/internal/task"
+)
+
+func TestNewPriorityFilter(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected PriorityFilter
+		wantErr  bool
+	}{
+		{"low", PriorityFilter{OpEqual, task.Low}, false},
+		{"l", PriorityFilter{OpEqual, task.Low}, false},
+		{"medium", PriorityFilter{OpEqual, task.Medium}, false},
+		{"m", PriorityFilter{OpEqual, task.Medium}, false},
+		{"high", PriorityFilter{OpEqual, task.High}, false},
+		{"H", PriorityFilter{OpEqual, task.High}, false},
+		{"=urgent", PriorityFilter{OpEqual, task.Urgent}, false},
+		{">=medium", PriorityFilter{OpGreaterOrEqual, task.Medium}, false},
+		{"> low", PriorityFilter{OpGreater, task.Low}, false},
+		{"<=m", PriorityFilter{OpLessOrEqual, task.Medium}, false},
+		{"<high", PriorityFilter{OpLess, task.High}, false},
+		{"", PriorityFilter{}, true},
+		{">=", PriorityFilter{}, true},
+		{"=>high", PriorityFilter{}, true},
+		{"critical", PriorityFilter{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NewPriorityFilter(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q, got %+v", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPriorityFilterMatches(t *testing.T) {
+	atLeastMedium := PriorityFilter{OpGreaterOrEqual, task.Medium}
+	for p, want := range map[task.Priority]bool{task.Low: false, task.Medium: true, task.High: true, task.Urgent: true} {
+		if got := atLeastMedium.Matches(p); got != want {
+			t.Errorf(">=medium matches %v = %v, expected %v", p, got, want)
+		}
+	}
+
+	onlyHigh := PriorityFilter{OpEqual, task.High}
+	if !onlyHigh.Matches(task.High) || onlyHigh.Matches(task.Urgent) {
+		t.Error("Expected an equality filter to match only its level")
+	}
+}
+
+func TestPriorityRangeFilter(t *testing.T) {
+	atLeastMedium, err := ParsePriorityRangeFilter("medium", "", task.ParsePriority)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing range: %v", err)
+	}
+	for p, want := range map[task.Priority]bool{task.Low: false, task.Medium: true, task.High: true, task.Urgent: true} {
+		if got := atLeastMedium.Matches(p); got != want {
+			t.Errorf("min medium: Matches(%s) = %v, want %v", p, got, want)
+		}
+	}
+
+	between, err := ParsePriorityRangeFilter("medium", "high", task.ParsePriority)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing range: %v", err)
+	}
+	for p, want := range map[task.Priority]bool{task.Low: false, task.Medium: true, task.High: true, task.Urgent: false} {
+		if got := between.Matches(p); got != want {
+			t.Errorf("medium..high: Matches(%s) = %v, want %v", p, got, want)
+		}
+	}
+
+	if _, err := ParsePriorityRangeFilter("high", "low", task.ParsePriority); err == nil {
+		t.Error("Expected error for minimum above maximum")
+	}
+	if _, err := ParsePriorityRangeFilter("", "critical", task.ParsePriority); err == nil {
+		t.Error("Expected error for unknown priority")
+	}
+}
+
+func TestPriorityFilterLabels(t *testing.T) {
+	for input, want := range map[string]string{"high": "priority:high", ">=medium": "priority:>=medium", "<u": "priority:<urgent"} {
+		f, err := NewPriorityFilter(input)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing %q: %v", input, err)
+		}
+		if got := f.Label(); got != want {
+			t.Errorf("%q: expected label %q, got %q", input, want, got)
+		}
+	}
+
+	between, err := ParsePriorityRangeFilter("medium", "high", task.ParsePriority)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing range: %v", err)
+	}
+	if got := between.Label(); got != "priority:medium..high" {
+		t.Errorf("Expected range label priority:medium..high, got %q", got)
+	}
+}
@@ -0,0 +1,50 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SearchFilter matches text against a search term, either as a
+// case-insensitive substring or as a regular expression
+type SearchFilter struct {
+	term string
+	re   *regexp.Regexp
+}
+
+// CreateSearchFilter builds a filter for term. With regex set the term is
+// compiled case-insensitively and an invalid pattern is an error.
+func CreateSearchFilter(term string, regex bool) (SearchFilter, error) {
+	if !regex {
+		return SearchFilter{term: strings.ToLower(term)}, nil
+	}
+	re, err := regexp.Compile("(?i)" + term)
+	if err != nil {
+		return SearchFilter{}, fmt.Errorf("invalid search regex %q: %w", term, err)
+	}
+	return SearchFilter{re: re}, nil
+}
+
+// Matches reports whether any of the given texts matches the search
+func (f *SearchFilter) Matches(texts ...string) bool {
+	for _, text := range texts {
+		if f.re != nil {
+			if f.re.MatchString(text) {
+				return true
+			}
+		} else if strings.Contains(strings.ToLower(text), f.term) {
+			return true
+		}
+	}
+	return false
+}
+
+// Label describes the filter for list --explain: "search:term", or the
+// pattern between slashes for a regex
+func (f *SearchFilter) Label() string {
+	if f.re != nil {
+		return "search:/" + strings.TrimPrefix(f.re.String(), "(?i)") + "/"
+	}
+	return "search:" + f.term
+}
@@ -3,6 +3,7 @@ package filter
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,11 +14,16 @@ const (
 	ModeToday
 	ModeOverdue
 	ModeNextNDays
+	ModeRange
 )
 
 type TaskDueFilter struct {
 	Mode FilterMode
 	Days int // for ModeNextNDays
+
+	// Start and End bound ModeRange, both inclusive at day granularity
+	Start time.Time
+	End   time.Time
 }
 
 func CreateTaskDueFilter(input string) (TaskDueFilter, error) {
@@ -29,6 +35,9 @@ func CreateTaskDueFilter(input string) (TaskDueFilter, error) {
 	case "week":
 		return TaskDueFilter{Mode: ModeNextNDays, Days: 7}, nil
 	default:
+		if start, end, ok := strings.Cut(input, ":"); ok {
+			return createRangeFilter(start, end)
+		}
 		days, err := strconv.Atoi(input)
 		if err != nil {
 			return TaskDueFilter{}, fmt.Errorf("invalid filter flag: %q", input)
@@ -40,7 +49,72 @@ func CreateTaskDueFilter(input string) (TaskDueFilter, error) {
 	}
 }
 
+// DueFlags are list's --overdue, --today and --due-soon shortcuts, next to
+// the -d expression they stand in for
+type DueFlags struct {
+	Due      string // a CreateTaskDueFilter expression, e.g. "week"
+	Overdue  bool
+	Today    bool
+	DueSoon  bool
+	SoonDays int // the window --due-soon covers
+}
+
+// Filter returns the filter for the shortcut flags, or nil when none is set.
+// At most one of the shortcuts and Due may be given; Due itself is left to
+// CreateTaskDueFilter.
+func (d DueFlags) Filter() (*TaskDueFilter, error) {
+	var set []string
+	var f TaskDueFilter
+	if d.Due != "" {
+		set = append(set, "-d")
+	}
+	if d.Overdue {
+		set = append(set, "--overdue")
+		f = TaskDueFilter{Mode: ModeOverdue}
+	}
+	if d.Today {
+		set = append(set, "--today")
+		f = TaskDueFilter{Mode: ModeToday}
+	}
+	if d.DueSoon {
+		if d.SoonDays < 0 {
+			return nil, fmt.Errorf("days cannot be negative: %d", d.SoonDays)
+		}
+		set = append(set, "--due-soon")
+		f = TaskDueFilter{Mode: ModeNextNDays, Days: d.SoonDays}
+	}
+
+	if len(set) > 1 {
+		return nil, fmt.Errorf("%s cannot be combined", strings.Join(set, ", "))
+	}
+	if f.Mode == ModeInvalid {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+// createRangeFilter parses the two halves of a "2024-01-01:2024-01-31" range
+func createRangeFilter(startStr, endStr string) (TaskDueFilter, error) {
+	start, err := time.ParseInLocation(time.DateOnly, startStr, time.Local)
+	if err != nil {
+		return TaskDueFilter{}, fmt.Errorf("invalid range start: %q", startStr)
+	}
+	end, err := time.ParseInLocation(time.DateOnly, endStr, time.Local)
+	if err != nil {
+		return TaskDueFilter{}, fmt.Errorf("invalid range end: %q", endStr)
+	}
+	if end.Before(start) {
+		return TaskDueFilter{}, fmt.Errorf("range end %s is before start %s", endStr, startStr)
+	}
+	return TaskDueFilter{Mode: ModeRange, Start: start, End: end}, nil
+}
+
+// Matches reports whether a due date passes the filter. An unset due date
+// never does, so undated tasks are not overdue.
 func (f *TaskDueFilter) Matches(date time.Time) bool {
+	if date.IsZero() {
+		return false
+	}
 	switch f.Mode {
 	case ModeToday:
 		return date.Format(time.DateOnly) == time.Now().Format(time.DateOnly)
@@ -48,6 +122,26 @@ func (f *TaskDueFilter) Matches(date time.Time) bool {
 		return date.Before(time.Now())
 	case ModeNextNDays:
 		return date.After(time.Now()) && date.Before(time.Now().AddDate(0, 0, f.Days))
+	case ModeRange:
+		// YYYY-MM-DD strings compare in date order
+		day := date.Format(time.DateOnly)
+		return day >= f.Start.Format(time.DateOnly) && day <= f.End.Format(time.DateOnly)
 	}
 	return false
 }
+
+// Label describes the filter for list --explain, e.g. "due:today" or
+// "due:next7d"
+func (f *TaskDueFilter) Label() string {
+	switch f.Mode {
+	case ModeToday:
+		return "due:today"
+	case ModeOverdue:
+		return "due:overdue"
+	case ModeNextNDays:
+		return fmt.Sprintf("due:next%dd", f.Days)
+	case ModeRange:
+		return "due:" + f.Start.Format(time.DateOnly) + ".." + f.End.Format(time.DateOnly)
+	}
+	return "due:invalid"
+}
@@ -0,0 +1,126 @@
+package filter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCreateTaskDueFilterRange(t *testing.T) {
+	f, err := CreateTaskDueFilter("2024-01-01:2024-01-31")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing range: %v", err)
+	}
+	if f.Mode != ModeRange {
+		t.Fatalf("Expected ModeRange, got %v", f.Mode)
+	}
+
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2024, month, day, hour, 0, 0, 0, time.Local)
+	}
+	tests := []struct {
+		date     time.Time
+		expected bool
+	}{
+		{at(1, 1, 0), true},     // start of the first day
+		{at(1, 31, 23), true},   // late on the last day
+		{at(1, 15, 12), true},   // inside
+		{at(12, 31, 23), false}, // 2024-12-31, after
+		{time.Date(2023, 12, 31, 23, 0, 0, 0, time.Local), false},
+		{at(2, 1, 0), false},
+		{time.Time{}, false}, // no due date
+	}
+	for _, tt := range tests {
+		if got := f.Matches(tt.date); got != tt.expected {
+			t.Errorf("Matches(%v) = %v, expected %v", tt.date, got, tt.expected)
+		}
+	}
+}
+
+func TestCreateTaskDueFilterSingleDayRange(t *testing.T) {
+	f, err := CreateTaskDueFilter("2024-03-10:2024-03-10")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing single-day range: %v", err)
+	}
+	if !f.Matches(time.Date(2024, 3, 10, 0, 0, 0, 0, time.Local)) || !f.Matches(time.Date(2024, 3, 10, 23, 59, 0, 0, time.Local)) {
+		t.Error("Expected a single-day range to match the whole day")
+	}
+	if f.Matches(time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local)) || f.Matches(time.Date(2024, 3, 9, 23, 59, 0, 0, time.Local)) {
+		t.Error("Expected a single-day range not to match neighbouring days")
+	}
+}
+
+func TestTaskDueFilterSkipsUndated(t *testing.T) {
+	for _, input := range []string{"overdue", "today", "week", "3"} {
+		f, err := CreateTaskDueFilter(input)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing %q: %v", input, err)
+		}
+		if f.Matches(time.Time{}) {
+			t.Errorf("Expected %q not to match a task without a due date", input)
+		}
+	}
+
+	overdue, _ := CreateTaskDueFilter("overdue")
+	if !overdue.Matches(time.Now().Add(-time.Hour)) {
+		t.Error("Expected overdue to match a past due date")
+	}
+}
+
+func TestCreateTaskDueFilterInvalidRanges(t *testing.T) {
+	for _, input := range []string{
+		"2024-01-31:2024-01-01", // reversed
+		"2024-01-01:",
+		":2024-01-31",
+		"2024-13-01:2024-12-31",
+		"jan:feb",
+	} {
+		if _, err := CreateTaskDueFilter(input); err == nil {
+			t.Errorf("Expected error for range %q", input)
+		}
+	}
+}
+
+func TestDueFlagsFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags DueFlags
+		want  *TaskDueFilter
+	}{
+		{name: "none", flags: DueFlags{SoonDays: 7}},
+		{name: "overdue", flags: DueFlags{Overdue: true}, want: &TaskDueFilter{Mode: ModeOverdue}},
+		{name: "today", flags: DueFlags{Today: true}, want: &TaskDueFilter{Mode: ModeToday}},
+		{name: "due soon", flags: DueFlags{DueSoon: true, SoonDays: 3}, want: &TaskDueFilter{Mode: ModeNextNDays, Days: 3}},
+		{name: "-d alone", flags: DueFlags{Due: "week"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.flags.Filter()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("Filter() = %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDueFlagsFilterExclusive(t *testing.T) {
+	tests := []struct {
+		flags DueFlags
+		want  string
+	}{
+		{DueFlags{Overdue: true, Today: true}, "--overdue, --today cannot be combined"},
+		{DueFlags{Today: true, DueSoon: true, SoonDays: 7}, "--today, --due-soon cannot be combined"},
+		{DueFlags{Due: "3", Overdue: true}, "-d, --overdue cannot be combined"},
+		{DueFlags{DueSoon: true, SoonDays: -1}, "days cannot be negative: -1"},
+	}
+
+	for _, tt := range tests {
+		_, err := tt.flags.Filter()
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Filter(%+v) error = %v, expected %q", tt.flags, err, tt.want)
+		}
+	}
+}
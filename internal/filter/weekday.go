@@ -0,0 +1,36 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WeekdayFilter matches dates falling on a given day of the week
+type WeekdayFilter struct {
+	Day time.Weekday
+}
+
+// CreateWeekdayFilter parses a weekday name such as "friday" or "fri"
+func CreateWeekdayFilter(input string) (WeekdayFilter, error) {
+	name := strings.ToLower(strings.TrimSpace(input))
+	if len(name) >= 3 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.HasPrefix(strings.ToLower(d.String()), name) {
+				return WeekdayFilter{Day: d}, nil
+			}
+		}
+	}
+	return WeekdayFilter{}, fmt.Errorf("invalid weekday: %q", input)
+}
+
+// Matches reports whether date falls on the filter's weekday; unset dates
+// never match
+func (f *WeekdayFilter) Matches(date time.Time) bool {
+	return !date.IsZero() && date.Weekday() == f.Day
+}
+
+// Label describes the filter for list --explain, e.g. "weekday:friday"
+func (f *WeekdayFilter) Label() string {
+	return "weekday:" + strings.ToLower(f.Day.String())
+}
@@ -2,9 +2,9 @@ package storage
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,28 +12,83 @@ import (
 	"go-fun/internal/task"
 )
 
+// MergeStrategy decides what happens when a queued task meets a stored copy
+// that another process changed more recently
+type MergeStrategy string
+
+const (
+	// MergeLastWriterWins always keeps the queued task
+	MergeLastWriterWins MergeStrategy = "last-writer-wins"
+	// MergeNewestWins keeps whichever copy has the later UpdatedAt
+	MergeNewestWins MergeStrategy = "newest-updatedat-wins"
+	// MergeErrorOnConflict refuses to merge and keeps the task queued
+	MergeErrorOnConflict MergeStrategy = "error-on-conflict"
+)
+
+// ParseMergeStrategy parses a merge strategy name
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	switch m := MergeStrategy(strings.ToLower(strings.TrimSpace(s))); m {
+	case MergeLastWriterWins, MergeNewestWins, MergeErrorOnConflict:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid merge strategy: %q. Use: %s, %s, %s", s, MergeLastWriterWins, MergeNewestWins, MergeErrorOnConflict)
+}
+
+// ConflictError reports a queued task whose stored copy was updated after it
+type ConflictError struct {
+	ID      string
+	Stored  time.Time
+	Unsaved time.Time
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("task %s was changed at %s, after the unsaved edit at %s",
+		e.ID, e.Stored.Format(time.RFC3339), e.Unsaved.Format(time.RFC3339))
+}
+
 // ConcurrentStorage wraps a Storage with concurrency features
 type ConcurrentStorage struct {
 	storage Storage
 	mutex   sync.RWMutex
+	merge   MergeStrategy // guarded by unsavedMutex, which every merge holds
 
 	// Background save functionality
 	autoSaveEnabled bool
 	autoSaveTicker  *time.Ticker
 	autoSaveStop    chan struct{}
+	autoSaveDone    chan error // receives the result of the worker's final save
 	unsavedTasks    []*task.Task
+	pendingDeletes  map[string]bool // IDs to drop on the next save
 	unsavedMutex    sync.Mutex
 }
 
 // NewConcurrentStorage creates a new concurrent storage wrapper
 func NewConcurrentStorage(s Storage) *ConcurrentStorage {
 	return &ConcurrentStorage{
-		storage:      s,
-		autoSaveStop: make(chan struct{}),
+		storage: s,
+		merge:   MergeLastWriterWins,
 	}
 }
 
-// EnableAutoSave enables automatic background saving every interval
+// NewAutoSavingStorage wraps s so writes are queued and saved in the
+// background every interval. Call DisableAutoSave before exiting to flush
+// whatever is still queued.
+func NewAutoSavingStorage(s Storage, interval time.Duration) *ConcurrentStorage {
+	cs := NewConcurrentStorage(s)
+	cs.EnableAutoSave(interval)
+	return cs
+}
+
+// SetMergeStrategy picks how queued tasks are merged into the stored ones
+func (cs *ConcurrentStorage) SetMergeStrategy(m MergeStrategy) {
+	cs.unsavedMutex.Lock()
+	defer cs.unsavedMutex.Unlock()
+
+	cs.merge = m
+}
+
+// EnableAutoSave enables automatic background saving every interval. It
+// may be called again after DisableAutoSave to start a fresh worker.
 func (cs *ConcurrentStorage) EnableAutoSave(interval time.Duration) {
 	cs.mutex.Lock()
 	defer cs.mutex.Unlock()
@@ -42,88 +97,121 @@ func (cs *ConcurrentStorage) EnableAutoSave(interval time.Duration) {
 		return // Already enabled
 	}
 
+	// Each worker gets its own channels, since a stopped one's are closed
 	cs.autoSaveEnabled = true
 	cs.autoSaveTicker = time.NewTicker(interval)
+	cs.autoSaveStop = make(chan struct{})
+	cs.autoSaveDone = make(chan error, 1)
 
-	go cs.autoSaveWorker()
+	go cs.autoSaveWorker(cs.autoSaveTicker, cs.autoSaveStop, cs.autoSaveDone)
 }
 
-// DisableAutoSave disables automatic background saving
-func (cs *ConcurrentStorage) DisableAutoSave() {
+// DisableAutoSave disables automatic background saving. It returns once the
+// worker has saved the tasks still queued, with the error of that final
+// save, and does nothing when auto-save is already off. Tasks that could
+// not be saved stay queued.
+func (cs *ConcurrentStorage) DisableAutoSave() error {
 	cs.mutex.Lock()
 	defer cs.mutex.Unlock()
 
 	if !cs.autoSaveEnabled {
-		return
+		return nil
 	}
 
 	cs.autoSaveEnabled = false
 	cs.autoSaveTicker.Stop()
 	close(cs.autoSaveStop)
+	return <-cs.autoSaveDone
 }
 
-// autoSaveWorker runs in the background and saves tasks periodically
-func (cs *ConcurrentStorage) autoSaveWorker() {
+// autoSaveWorker runs in the background and saves tasks on every tick until
+// stop is closed, then sends the result of a final save on done. A failed
+// tick leaves the queue for the next one.
+func (cs *ConcurrentStorage) autoSaveWorker(ticker *time.Ticker, stop <-chan struct{}, done chan<- error) {
 	for {
 		select {
-		case <-cs.autoSaveTicker.C:
+		case <-ticker.C:
 			cs.saveUnsavedTasks()
-		case <-cs.autoSaveStop:
+		case <-stop:
 			// Final save before stopping
-			cs.saveUnsavedTasks()
+			done <- cs.saveUnsavedTasks()
 			return
 		}
 	}
 }
 
-// saveUnsavedTasks saves any unsaved tasks
-func (cs *ConcurrentStorage) saveUnsavedTasks() {
+// saveUnsavedTasks saves any unsaved tasks and applies pending deletes. When
+// the store can't be loaded or a merge conflicts, nothing is saved and both
+// stay queued.
+func (cs *ConcurrentStorage) saveUnsavedTasks() error {
 	cs.unsavedMutex.Lock()
 	defer cs.unsavedMutex.Unlock()
 
-	if len(cs.unsavedTasks) == 0 {
-		return
+	if len(cs.unsavedTasks) == 0 && len(cs.pendingDeletes) == 0 {
+		return nil
 	}
 
 	// Load current tasks and merge with unsaved ones
 	ctx := context.Background()
 	currentTasks, err := cs.storage.Load(ctx)
 	if err != nil {
-		// If we can't load, just save the unsaved tasks
-		cs.storage.Save(ctx, cs.unsavedTasks)
-		cs.unsavedTasks = nil
-		return
+		// Saving only the queue would replace every stored task with it
+		return fmt.Errorf("failed to load tasks: %w", err)
 	}
 
 	// Merge unsaved tasks with current ones
-	mergedTasks := cs.mergeTasks(currentTasks, cs.unsavedTasks)
+	mergedTasks, err := cs.mergeTasks(currentTasks, cs.unsavedTasks)
+	if err != nil {
+		return err
+	}
 
 	// Save merged tasks
-	if err := cs.storage.Save(ctx, mergedTasks); err == nil {
-		cs.unsavedTasks = nil // Clear unsaved tasks on successful save
+	if err := cs.storage.Save(ctx, mergedTasks); err != nil {
+		return err
 	}
+	// Clear the queue on successful save
+	cs.unsavedTasks, cs.pendingDeletes = nil, nil
+	return nil
 }
 
-// mergeTasks merges current tasks with unsaved tasks
-func (cs *ConcurrentStorage) mergeTasks(current, unsaved []*task.Task) []*task.Task {
-	// Create a map of current tasks by ID for quick lookup
-	currentMap := make(map[string]*task.Task)
+// mergeTasks merges unsaved tasks into current ones according to the merge
+// strategy and drops pending deletes. A stored copy conflicts when its
+// UpdatedAt is after the unsaved task's. Stored order is kept and new tasks
+// go at the end. The caller holds unsavedMutex.
+func (cs *ConcurrentStorage) mergeTasks(current, unsaved []*task.Task) ([]*task.Task, error) {
+	// Create a map of current task positions by ID for quick lookup
+	index := make(map[string]int, len(current))
+	result := make([]*task.Task, 0, len(current)+len(unsaved))
 	for _, t := range current {
-		currentMap[t.ID] = t
+		if cs.pendingDeletes[t.ID] {
+			continue
+		}
+		index[t.ID] = len(result)
+		result = append(result, t)
 	}
 
 	// Update or add unsaved tasks
 	for _, unsavedTask := range unsaved {
-		currentMap[unsavedTask.ID] = unsavedTask
-	}
+		i, ok := index[unsavedTask.ID]
+		if !ok {
+			index[unsavedTask.ID] = len(result)
+			result = append(result, unsavedTask)
+			continue
+		}
 
-	// Convert back to slice
-	result := make([]*task.Task, 0, len(currentMap))
-	for _, t := range currentMap {
-		result = append(result, t)
+		stored := result[i]
+		if stored.UpdatedAt.After(unsavedTask.UpdatedAt) {
+			switch cs.merge {
+			case MergeNewestWins:
+				continue
+			case MergeErrorOnConflict:
+				return nil, &ConflictError{ID: stored.ID, Stored: stored.UpdatedAt, Unsaved: unsavedTask.UpdatedAt}
+			}
+		}
+		result[i] = unsavedTask
 	}
 
-	return result
+	return result, nil
 }
 
 // QueueTaskForSave queues a task for background saving
@@ -148,6 +236,9 @@ func (cs *ConcurrentStorage) QueueTaskForSave(t *task.Task) {
 	if !found {
 		cs.unsavedTasks = append(cs.unsavedTasks, t)
 	}
+
+	// Saving a task again undoes a pending delete of it
+	delete(cs.pendingDeletes, t.ID)
 }
 
 // Load implements Storage interface
@@ -162,10 +253,10 @@ func (cs *ConcurrentStorage) Load(ctx context.Context) ([]*task.Task, error) {
 
 	// Merge with any unsaved tasks
 	cs.unsavedMutex.Lock()
-	if len(cs.unsavedTasks) > 0 {
-		tasks = cs.mergeTasks(tasks, cs.unsavedTasks)
+	defer cs.unsavedMutex.Unlock()
+	if len(cs.unsavedTasks) > 0 || len(cs.pendingDeletes) > 0 {
+		return cs.mergeTasks(tasks, cs.unsavedTasks)
 	}
-	cs.unsavedMutex.Unlock()
 
 	return tasks, nil
 }
@@ -177,7 +268,7 @@ func (cs *ConcurrentStorage) Save(ctx context.Context, tasks []*task.Task) error
 
 	// Clear unsaved tasks since we're doing a full save
 	cs.unsavedMutex.Lock()
-	cs.unsavedTasks = nil
+	cs.unsavedTasks, cs.pendingDeletes = nil, nil
 	cs.unsavedMutex.Unlock()
 
 	return cs.storage.Save(ctx, tasks)
@@ -215,31 +306,55 @@ func (cs *ConcurrentStorage) Delete(ctx context.Context, id string) error {
 	defer cs.mutex.Unlock()
 
 	if cs.autoSaveEnabled {
-		// Remove from unsaved tasks if present
-		cs.unsavedMutex.Lock()
-		for i, t := range cs.unsavedTasks {
-			if t.ID == id {
-				cs.unsavedTasks = append(cs.unsavedTasks[:i], cs.unsavedTasks[i+1:]...)
-				break
-			}
-		}
-		cs.unsavedMutex.Unlock()
-
-		// Mark for deletion by setting a special flag or removing from storage
-		// For simplicity, we'll do immediate deletion
-		return cs.storage.Delete(ctx, id)
+		return cs.queueDelete(ctx, id)
 	}
 
 	return cs.storage.Delete(ctx, id)
 }
 
+// queueDelete drops any queued copy of id and marks it for removal on the
+// next save, so a delete never races a queued Add or Update of the same
+// task. Like Delete, it fails when no such task exists.
+func (cs *ConcurrentStorage) queueDelete(ctx context.Context, id string) error {
+	cs.unsavedMutex.Lock()
+	defer cs.unsavedMutex.Unlock()
+
+	if cs.pendingDeletes[id] {
+		return fmt.Errorf("task with ID %s not found", id)
+	}
+
+	queued := false
+	for i, t := range cs.unsavedTasks {
+		if t.ID == id {
+			cs.unsavedTasks = append(cs.unsavedTasks[:i], cs.unsavedTasks[i+1:]...)
+			queued = true
+			break
+		}
+	}
+	if !queued {
+		if _, err := cs.storage.GetByID(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if cs.pendingDeletes == nil {
+		cs.pendingDeletes = make(map[string]bool)
+	}
+	cs.pendingDeletes[id] = true
+	return nil
+}
+
 // GetByID implements Storage interface
 func (cs *ConcurrentStorage) GetByID(ctx context.Context, id string) (*task.Task, error) {
 	cs.mutex.RLock()
 	defer cs.mutex.RUnlock()
 
-	// Check unsaved tasks first
+	// Check pending deletes and unsaved tasks first
 	cs.unsavedMutex.Lock()
+	if cs.pendingDeletes[id] {
+		cs.unsavedMutex.Unlock()
+		return nil, fmt.Errorf("task with ID %s not found", id)
+	}
 	for _, t := range cs.unsavedTasks {
 		if t.ID == id {
 			cs.unsavedMutex.Unlock()
@@ -252,6 +367,22 @@ func (cs *ConcurrentStorage) GetByID(ctx context.Context, id string) (*task.Task
 	return cs.storage.GetByID(ctx, id)
 }
 
+// HasData reports whether tasks are queued or the wrapped store holds data,
+// so the guard against wiping an inconsistent store sees through the wrapper
+func (cs *ConcurrentStorage) HasData() (bool, error) {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+
+	cs.unsavedMutex.Lock()
+	queued := len(cs.unsavedTasks) > 0
+	cs.unsavedMutex.Unlock()
+	if queued {
+		return true, nil
+	}
+
+	return holdsTasks(context.Background(), cs.storage)
+}
+
 // ExportManager handles concurrent exports
 type ExportManager struct {
 	storage Storage
@@ -264,8 +395,10 @@ func NewExportManager(s Storage) *ExportManager {
 	}
 }
 
-// ConcurrentExport exports tasks to multiple formats concurrently
-func (em *ExportManager) ConcurrentExport(ctx context.Context, formats []string, baseFilename string) error {
+// ConcurrentExport exports tasks to multiple formats concurrently, running
+// at most workers exports at once (<= 0 uses the CPU count). Formats not
+// yet started when ctx is cancelled are skipped with the context error.
+func (em *ExportManager) ConcurrentExport(ctx context.Context, formats []string, baseFilename string, workers int) error {
 	if len(formats) == 0 {
 		return fmt.Errorf("no formats specified")
 	}
@@ -276,21 +409,37 @@ func (em *ExportManager) ConcurrentExport(ctx context.Context, formats []string,
 		return fmt.Errorf("failed to load tasks: %w", err)
 	}
 
-	// Create channels for results
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(formats))
+
+	// Create channels for jobs and results
 	type exportResult struct {
 		format string
 		err    error
 	}
 
+	jobs := make(chan string, len(formats))
+	for _, format := range formats {
+		jobs <- format
+	}
+	close(jobs)
+
 	results := make(chan exportResult, len(formats))
 
-	// Start export goroutines
-	for _, format := range formats {
-		go func(fmt string) {
-			filename := baseFilename + "." + fmt
-			err := em.exportFormat(tasks, fmt, filename)
-			results <- exportResult{format: fmt, err: err}
-		}(format)
+	// Start the worker pool
+	for i := 0; i < workers; i++ {
+		go func() {
+			for format := range jobs {
+				if err := ctx.Err(); err != nil {
+					results <- exportResult{format: format, err: err}
+					continue
+				}
+				err := em.exportFormat(tasks, format, baseFilename+"."+format)
+				results <- exportResult{format: format, err: err}
+			}
+		}()
 	}
 
 	// Collect results
@@ -303,139 +452,19 @@ func (em *ExportManager) ConcurrentExport(ctx context.Context, formats []string,
 	}
 
 	if len(errors) > 0 {
+		sort.Strings(errors)
 		return fmt.Errorf("export errors: %s", strings.Join(errors, "; "))
 	}
 
 	return nil
 }
 
-// exportFormat exports tasks to a specific format
+// exportFormat exports tasks to filename with the exporter registered for
+// format
 func (em *ExportManager) exportFormat(tasks []*task.Task, format, filename string) error {
-	switch strings.ToLower(format) {
-	case "json":
-		return em.exportJSON(tasks, filename)
-	case "csv":
-		return em.exportCSV(tasks, filename)
-	case "markdown", "md":
-		return em.exportMarkdown(tasks, filename)
-	default:
-		return fmt.Errorf("unsupported format: %s", format)
-	}
-}
-
-// Export methods (similar to CLI commands but for direct use)
-func (em *ExportManager) exportJSON(tasks []*task.Task, filename string) error {
-	data, err := json.MarshalIndent(tasks, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-	return os.WriteFile(filename, data, 0644)
-}
-
-func (em *ExportManager) exportCSV(tasks []*task.Task, filename string) error {
-	file, err := os.Create(filename)
+	e, err := LookupExporter(format)
 	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %w", err)
-	}
-	defer file.Close()
-
-	// Write CSV header
-	fmt.Fprintln(file, "ID,Title,Description,Priority,Completed,Due Date,Created,Updated")
-
-	// Write task data
-	for _, t := range tasks {
-		dueDate := ""
-		if !t.DueDate.IsZero() {
-			dueDate = t.DueDate.Format("2006-01-02 15:04")
-		}
-		fmt.Fprintf(file, "%s,%s,%s,%s,%t,%s,%s,%s\n",
-			t.ID,
-			strings.ReplaceAll(t.Title, ",", ";"),
-			strings.ReplaceAll(t.Description, ",", ";"),
-			t.Priority.String(),
-			t.Completed,
-			dueDate,
-			t.CreatedAt.Format("2006-01-02 15:04"),
-			t.UpdatedAt.Format("2006-01-02 15:04"),
-		)
-	}
-
-	return nil
-}
-
-func (em *ExportManager) exportMarkdown(tasks []*task.Task, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create Markdown file: %w", err)
-	}
-	defer file.Close()
-
-	// Write header
-	fmt.Fprintf(file, "# Task Export\n\n")
-	fmt.Fprintf(file, "Generated on: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
-
-	// Group tasks by completion status
-	completed := make([]*task.Task, 0)
-	pending := make([]*task.Task, 0)
-
-	for _, t := range tasks {
-		if t.Completed {
-			completed = append(completed, t)
-		} else {
-			pending = append(pending, t)
-		}
-	}
-
-	// Write pending tasks
-	if len(pending) > 0 {
-		fmt.Fprintf(file, "## Pending Tasks (%d)\n\n", len(pending))
-		for _, t := range pending {
-			em.writeMarkdownTask(file, t)
-		}
-		fmt.Fprintln(file)
-	}
-
-	// Write completed tasks
-	if len(completed) > 0 {
-		fmt.Fprintf(file, "## Completed Tasks (%d)\n\n", len(completed))
-		for _, t := range completed {
-			em.writeMarkdownTask(file, t)
-		}
-	}
-
-	return nil
-}
-
-func (em *ExportManager) writeMarkdownTask(file *os.File, t *task.Task) {
-	status := "❌"
-	if t.Completed {
-		status = "✅"
-	}
-
-	priorityEmoji := ""
-	switch t.Priority {
-	case task.High:
-		priorityEmoji = "🔴"
-	case task.Medium:
-		priorityEmoji = "🟡"
-	case task.Low:
-		priorityEmoji = "🟢"
-	}
-
-	fmt.Fprintf(file, "### %s %s %s\n\n", status, priorityEmoji, t.Title)
-
-	if t.Description != "" {
-		fmt.Fprintf(file, "**Description:** %s\n\n", t.Description)
-	}
-
-	if !t.DueDate.IsZero() {
-		fmt.Fprintf(file, "**Due:** %s\n\n", t.DueDate.Format("2006-01-02 15:04"))
-	}
-
-	fmt.Fprintf(file, "**ID:** `%s`  \n", t.ID)
-	fmt.Fprintf(file, "**Created:** %s  \n", t.CreatedAt.Format("2006-01-02 15:04"))
-	if t.UpdatedAt.After(t.CreatedAt) {
-		fmt.Fprintf(file, "**Updated:** %s  \n", t.UpdatedAt.Format("2006-01-02 15:04"))
+		return err
 	}
-	fmt.Fprintln(file)
+	return ExportFile(e, filename, tasks)
 }
@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+func TestConcurrentStorageMergeStrategies(t *testing.T) {
+	queued := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	external := queued.Add(time.Hour)
+
+	tests := []struct {
+		strategy  MergeStrategy
+		wantTitle string
+		wantErr   bool
+	}{
+		{MergeLastWriterWins, "Unsaved edit", false},
+		{MergeNewestWins, "External edit", false},
+		{MergeErrorOnConflict, "External edit", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			ctx := context.Background()
+			inner := NewInMemoryStorage()
+			if err := inner.Add(ctx, &task.Task{ID: "shared", Title: "External edit", UpdatedAt: external}); err != nil {
+				t.Fatalf("Unexpected error adding task: %v", err)
+			}
+
+			cs := NewConcurrentStorage(inner)
+			cs.SetMergeStrategy(tt.strategy)
+			cs.EnableAutoSave(time.Hour)
+			defer cs.DisableAutoSave()
+
+			// The queued edit predates the one another process stored
+			cs.QueueTaskForSave(&task.Task{ID: "shared", Title: "Unsaved edit", UpdatedAt: queued})
+			cs.QueueTaskForSave(&task.Task{ID: "new", Title: "New task", UpdatedAt: queued})
+
+			err := cs.saveUnsavedTasks()
+			var conflict *ConflictError
+			if tt.wantErr {
+				if !errors.As(err, &conflict) || conflict.ID != "shared" {
+					t.Fatalf("Expected a conflict on shared, got %v", err)
+				}
+				if _, err := cs.Load(ctx); !errors.As(err, &conflict) {
+					t.Errorf("Expected Load to report the conflict, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Unexpected error saving: %v", err)
+			}
+
+			stored, err := inner.GetByID(ctx, "shared")
+			if err != nil {
+				t.Fatalf("Unexpected error getting task: %v", err)
+			}
+			if stored.Title != tt.wantTitle {
+				t.Errorf("Expected stored title %q, got %q", tt.wantTitle, stored.Title)
+			}
+
+			// Tasks without a stored copy never conflict
+			_, err = inner.GetByID(ctx, "new")
+			if tt.wantErr && err == nil {
+				t.Error("Expected nothing to be saved on conflict")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected the new task to be saved: %v", err)
+			}
+		})
+	}
+}
+
+func TestConcurrentStorageDisableAutoSaveFlushes(t *testing.T) {
+	ctx := context.Background()
+	inner := NewInMemoryStorage()
+	cs := NewAutoSavingStorage(inner, time.Hour)
+
+	if err := cs.Add(ctx, &task.Task{ID: "queued", Title: "Queued"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	if _, err := inner.GetByID(ctx, "queued"); err == nil {
+		t.Fatal("Expected the task to wait in the queue")
+	}
+
+	cs.DisableAutoSave()
+	if _, err := inner.GetByID(ctx, "queued"); err != nil {
+		t.Errorf("Expected the queued task to be saved by DisableAutoSave: %v", err)
+	}
+}
+
+// unreadableStorage fails every Load, like a file this build can't parse
+type unreadableStorage struct {
+	*InMemoryStorage
+}
+
+func (unreadableStorage) Load(ctx context.Context) ([]*task.Task, error) {
+	return nil, errors.New("unsupported schema version 2")
+}
+
+func TestConcurrentStorageDisableAutoSaveReportsFailure(t *testing.T) {
+	ctx := context.Background()
+	inner := NewInMemoryStorage()
+	if err := inner.Add(ctx, &task.Task{ID: "stored", Title: "Stored"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	cs := NewAutoSavingStorage(unreadableStorage{inner}, time.Hour)
+
+	if err := cs.Add(ctx, &task.Task{ID: "queued", Title: "Queued"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	if err := cs.DisableAutoSave(); err == nil || !strings.Contains(err.Error(), "unsupported schema version 2") {
+		t.Fatalf("Expected the failed final save to be reported, got %v", err)
+	}
+
+	// The store is left alone and the task stays queued
+	tasks, _ := inner.Load(ctx)
+	if len(tasks) != 1 || tasks[0].ID != "stored" {
+		t.Errorf("Expected the stored task to survive, got %+v", tasks)
+	}
+	if len(cs.unsavedTasks) != 1 {
+		t.Errorf("Expected the task to stay queued, got %d queued", len(cs.unsavedTasks))
+	}
+}
+
+func TestConcurrentStorageAutoSaveCycles(t *testing.T) {
+	ctx := context.Background()
+	inner := NewInMemoryStorage()
+	cs := NewConcurrentStorage(inner)
+
+	// Disabling before enabling, twice in a row, or after a re-enable
+	// must neither panic nor block
+	cs.DisableAutoSave()
+	for i := 0; i < 2; i++ {
+		cs.EnableAutoSave(time.Hour)
+		cs.DisableAutoSave()
+		cs.DisableAutoSave()
+	}
+
+	// The worker started by a re-enable still saves on its ticks
+	cs.EnableAutoSave(10 * time.Millisecond)
+	defer cs.DisableAutoSave()
+	if err := cs.Add(ctx, &task.Task{ID: "ticked", Title: "Ticked"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := inner.GetByID(ctx, "ticked"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the re-enabled worker to save the queued task")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestConcurrentStorageDeferredDelete(t *testing.T) {
+	ctx := context.Background()
+	inner := NewInMemoryStorage()
+	if err := inner.Add(ctx, &task.Task{ID: "stored", Title: "Stored"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	cs := NewAutoSavingStorage(inner, time.Hour)
+	defer cs.DisableAutoSave()
+
+	// A queued add followed by a delete of the same ID
+	if err := cs.Add(ctx, &task.Task{ID: "queued", Title: "Queued"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	for _, id := range []string{"queued", "stored"} {
+		if err := cs.Delete(ctx, id); err != nil {
+			t.Fatalf("Unexpected error deleting %s: %v", id, err)
+		}
+	}
+	if err := cs.Delete(ctx, "stored"); err == nil {
+		t.Error("Expected error deleting a task twice")
+	}
+	if err := cs.Delete(ctx, "missing"); err == nil {
+		t.Error("Expected error deleting a missing task")
+	}
+
+	// Nothing reaches the inner store until the save, but reads see it
+	if _, err := inner.GetByID(ctx, "stored"); err != nil {
+		t.Errorf("Expected the delete to wait for the save: %v", err)
+	}
+	if _, err := cs.GetByID(ctx, "stored"); err == nil {
+		t.Error("Expected GetByID to hide a pending delete")
+	}
+	if tasks, err := cs.Load(ctx); err != nil || len(tasks) != 0 {
+		t.Errorf("Expected Load to hide pending deletes, got %d tasks (%v)", len(tasks), err)
+	}
+
+	if err := cs.saveUnsavedTasks(); err != nil {
+		t.Fatalf("Unexpected error saving: %v", err)
+	}
+	tasks, err := inner.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("Expected the save to leave no tasks, got %+v", tasks)
+	}
+
+	// Re-adding a deleted ID cancels the delete
+	if err := cs.Add(ctx, &task.Task{ID: "again", Title: "Again"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	if err := cs.Delete(ctx, "again"); err != nil {
+		t.Fatalf("Unexpected error deleting task: %v", err)
+	}
+	if err := cs.Add(ctx, &task.Task{ID: "again", Title: "Back"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	cs.DisableAutoSave()
+	if got, err := inner.GetByID(ctx, "again"); err != nil || got.Title != "Back" {
+		t.Errorf("Expected the re-added task to be saved, got %+v (%v)", got, err)
+	}
+}
+
+func TestParseMergeStrategy(t *testing.T) {
+	if m, err := ParseMergeStrategy("Newest-UpdatedAt-Wins"); err != nil || m != MergeNewestWins {
+		t.Errorf("Expected newest-updatedat-wins, got %q (err %v)", m, err)
+	}
+	if _, err := ParseMergeStrategy("coin-flip"); err == nil {
+		t.Error("Expected error for an unknown strategy")
+	}
+}
+
+func TestExportManagerCSVQuoting(t *testing.T) {
+	now := time.Now()
+	title := "Call \"Ann\", then\nBob"
+	var buf bytes.Buffer
+	if err := (CSVExporter{}).Export(&buf, []*task.Task{{ID: "tricky", Title: title, CreatedAt: now, UpdatedAt: now}}); err != nil {
+		t.Fatalf("Unexpected error exporting CSV: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got %v", err)
+	}
+	if len(records) != 2 || records[1][1] != title {
+		t.Errorf("Expected the title to survive unchanged, got %q", records)
+	}
+}
+
+func TestConcurrentStorageHasData(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	inner := NewJSONFileStorage(path)
+	cs := NewAutoSavingStorage(inner, time.Hour)
+	defer cs.DisableAutoSave()
+
+	if hasData, err := cs.HasData(); err != nil || hasData {
+		t.Errorf("Expected no data before any task, got %v (%v)", hasData, err)
+	}
+	if err := cs.Add(ctx, &task.Task{ID: "queued", Title: "Queued"}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	if hasData, err := cs.HasData(); err != nil || !hasData {
+		t.Errorf("Expected a queued task to count as data, got %v (%v)", hasData, err)
+	}
+
+	// A file that loads empty but is not is reported through the wrapper
+	if err := cs.Save(ctx, nil); err != nil {
+		t.Fatalf("Unexpected error saving: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("null"), 0644); err != nil {
+		t.Fatalf("Failed to write tasks file: %v", err)
+	}
+	if hasData, err := cs.HasData(); err != nil || !hasData {
+		t.Errorf("Expected the wrapped store's data to be reported, got %v (%v)", hasData, err)
+	}
+}
@@ -0,0 +1,256 @@
+package storage
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// Exporter writes tasks in one file format
+type Exporter interface {
+	// Name is the format name, also used as the file extension
+	Name() string
+	Export(w io.Writer, tasks []*task.Task) error
+}
+
+// ExporterRegistry looks up exporters by format name or alias
+type ExporterRegistry struct {
+	mutex     sync.RWMutex
+	exporters map[string]Exporter
+	names     []string
+}
+
+// NewExporterRegistry creates an empty registry
+func NewExporterRegistry() *ExporterRegistry {
+	return &ExporterRegistry{exporters: make(map[string]Exporter)}
+}
+
+// Register adds e under its name and any aliases. Like database/sql's
+// Register, it panics when a name is taken, since that is a programming
+// error.
+func (r *ExporterRegistry) Register(e Exporter, aliases ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, name := range append([]string{e.Name()}, aliases...) {
+		key := strings.ToLower(name)
+		if _, dup := r.exporters[key]; dup {
+			panic("storage: exporter registered twice for " + key)
+		}
+		r.exporters[key] = e
+	}
+	r.names = append(r.names, e.Name())
+	sort.Strings(r.names)
+}
+
+// Lookup returns the exporter for a format name or alias, ignoring case
+func (r *ExporterRegistry) Lookup(format string) (Exporter, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	e, ok := r.exporters[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported export format: %s (use: %s)", format, strings.Join(r.names, ", "))
+	}
+	return e, nil
+}
+
+// Formats lists the registered format names, without aliases
+func (r *ExporterRegistry) Formats() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return append([]string(nil), r.names...)
+}
+
+// exporters holds the formats ExportManager writes
+var exporters = NewExporterRegistry()
+
+func init() {
+	RegisterExporter(JSONExporter{})
+	RegisterExporter(CSVExporter{})
+	RegisterExporter(MarkdownExporter{}, "md")
+}
+
+// RegisterExporter makes a format available to ExportManager
+func RegisterExporter(e Exporter, aliases ...string) {
+	exporters.Register(e, aliases...)
+}
+
+// LookupExporter returns the ExportManager exporter for format
+func LookupExporter(format string) (Exporter, error) {
+	return exporters.Lookup(format)
+}
+
+// ExportFile writes tasks to filename with e, replacing the file
+func ExportFile(e Exporter, filename string, tasks []*task.Task) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create %s file: %w", e.Name(), err)
+	}
+	if err := e.Export(file, tasks); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s file: %w", e.Name(), err)
+	}
+	return nil
+}
+
+// JSONExporter writes tasks as an indented JSON array
+type JSONExporter struct{}
+
+// Name implements Exporter
+func (JSONExporter) Name() string { return "json" }
+
+// Export implements Exporter
+func (JSONExporter) Export(w io.Writer, tasks []*task.Task) error {
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// CSVExporter writes one row per task under a header row
+type CSVExporter struct{}
+
+// Name implements Exporter
+func (CSVExporter) Name() string { return "csv" }
+
+// Export implements Exporter
+func (CSVExporter) Export(out io.Writer, tasks []*task.Task) error {
+	// Fields with commas, quotes or line breaks are quoted by the writer
+	w := csv.NewWriter(out)
+	w.Write([]string{"ID", "Title", "Description", "Priority", "Completed", "Due Date", "Created", "Updated", "Tags"})
+
+	// Write task data
+	for _, t := range tasks {
+		dueDate := ""
+		if !t.DueDate.IsZero() {
+			dueDate = t.DueDate.Format("2006-01-02 15:04")
+		}
+		w.Write([]string{
+			t.ID,
+			t.Title,
+			t.Description,
+			t.Priority.String(),
+			strconv.FormatBool(t.Completed),
+			dueDate,
+			t.CreatedAt.Format("2006-01-02 15:04"),
+			t.UpdatedAt.Format("2006-01-02 15:04"),
+			strings.Join(t.Tags, ";"),
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// MarkdownExporter writes pending and completed tasks as Markdown sections
+type MarkdownExporter struct{}
+
+// Name implements Exporter
+func (MarkdownExporter) Name() string { return "markdown" }
+
+// Export implements Exporter
+func (MarkdownExporter) Export(w io.Writer, tasks []*task.Task) error {
+	// Write header
+	fmt.Fprintf(w, "# Task Export\n\n")
+	fmt.Fprintf(w, "Generated on: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	// Group tasks by completion status
+	completed := make([]*task.Task, 0)
+	pending := make([]*task.Task, 0)
+
+	for _, t := range tasks {
+		if t.Completed {
+			completed = append(completed, t)
+		} else {
+			pending = append(pending, t)
+		}
+	}
+
+	// Write pending tasks
+	if len(pending) > 0 {
+		fmt.Fprintf(w, "## Pending Tasks (%d)\n\n", len(pending))
+		for _, t := range pending {
+			writeMarkdownTask(w, t)
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Write completed tasks
+	if len(completed) > 0 {
+		fmt.Fprintf(w, "## Completed Tasks (%d)\n\n", len(completed))
+		for _, t := range completed {
+			writeMarkdownTask(w, t)
+		}
+	}
+
+	return nil
+}
+
+func writeMarkdownTask(w io.Writer, t *task.Task) {
+	status := "❌"
+	if t.Completed {
+		status = "✅"
+	}
+
+	priorityEmoji := ""
+	switch t.Priority {
+	case task.Urgent:
+		priorityEmoji = "🔥"
+	case task.High:
+		priorityEmoji = "🔴"
+	case task.Medium:
+		priorityEmoji = "🟡"
+	case task.Low:
+		priorityEmoji = "🟢"
+	}
+
+	fmt.Fprintf(w, "### %s %s %s\n\n", status, priorityEmoji, t.Title)
+
+	if t.Description != "" {
+		fmt.Fprintf(w, "**Description:** %s\n\n", t.Description)
+	}
+
+	if !t.DueDate.IsZero() {
+		fmt.Fprintf(w, "**Due:** %s\n\n", t.DueDate.Format("2006-01-02 15:04"))
+	}
+
+	if len(t.Tags) > 0 {
+		fmt.Fprintf(w, "**Tags:** %s\n\n", strings.Join(t.Tags, ", "))
+	}
+
+	if len(t.Notes) > 0 {
+		fmt.Fprintf(w, "**Notes:**\n\n")
+		for _, n := range t.NotesNewestFirst() {
+			fmt.Fprintf(w, "- %s: %s\n", n.CreatedAt.Format("2006-01-02 15:04"), n.Text)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "**ID:** `%s`  \n", t.ID)
+	fmt.Fprintf(w, "**Created:** %s  \n", t.CreatedAt.Format("2006-01-02 15:04"))
+	if t.UpdatedAt.After(t.CreatedAt) {
+		fmt.Fprintf(w, "**Updated:** %s  \n", t.UpdatedAt.Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fun/internal/task"
+)
+
+// titleExporter writes one title per line
+type titleExporter struct{}
+
+func (titleExporter) Name() string { return "titles" }
+
+func (titleExporter) Export(w io.Writer, tasks []*task.Task) error {
+	for _, t := range tasks {
+		if _, err := io.WriteString(w, t.Title+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExporterRegistry(t *testing.T) {
+	r := NewExporterRegistry()
+	r.Register(titleExporter{}, "txt")
+	r.Register(JSONExporter{})
+
+	for _, format := range []string{"titles", "TXT"} {
+		e, err := r.Lookup(format)
+		if err != nil {
+			t.Fatalf("Unexpected error looking up %s: %v", format, err)
+		}
+		var buf bytes.Buffer
+		if err := e.Export(&buf, []*task.Task{{Title: "Plan"}, {Title: "Ship"}}); err != nil {
+			t.Fatalf("Unexpected error exporting: %v", err)
+		}
+		if buf.String() != "Plan\nShip\n" {
+			t.Errorf("Expected one title per line, got %q", buf.String())
+		}
+	}
+
+	_, err := r.Lookup("pdf")
+	if err == nil || !strings.Contains(err.Error(), "unsupported export format: pdf") || !strings.Contains(err.Error(), "json, titles") {
+		t.Errorf("Expected an unsupported format error listing the formats, got %v", err)
+	}
+	if got := r.Formats(); strings.Join(got, ",") != "json,titles" {
+		t.Errorf("Expected formats without aliases, got %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a taken name to panic")
+		}
+	}()
+	r.Register(titleExporter{})
+}
+
+func TestExportManagerUsesRegisteredExporters(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStorage()
+	now := time.Now()
+	if err := store.Add(ctx, &task.Task{ID: "a", Title: "Plan", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+
+	base := filepath.Join(t.TempDir(), "tasks")
+	em := NewExportManager(store)
+	if err := em.ConcurrentExport(ctx, []string{"json", "md"}, base, 0); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+	for _, ext := range []string{"json", "md"} {
+		data, err := os.ReadFile(base + "." + ext)
+		if err != nil || !strings.Contains(string(data), "Plan") {
+			t.Errorf("Expected %s export to contain the task, got %q (%v)", ext, data, err)
+		}
+	}
+
+	err := em.ConcurrentExport(ctx, []string{"pdf"}, base, 0)
+	if err == nil || !strings.Contains(err.Error(), "unsupported export format: pdf") {
+		t.Errorf("Expected an unsupported format error, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Open creates a storage backend from a DSN of the form "<scheme>:<location>".
+// Supported schemes are "json:<path>", "gob:<path>", "sqlite:<path>" and
+// "memory:"; a DSN without a scheme is treated as a JSON file path.
+func Open(dsn string) (Storage, error) {
+	scheme, location, found := strings.Cut(dsn, ":")
+	if !found || len(scheme) == 1 { // no scheme, or a Windows drive letter
+		scheme, location = "json", dsn
+	}
+
+	switch strings.ToLower(scheme) {
+	case "json":
+		if location == "" {
+			return nil, fmt.Errorf("json storage requires a file path")
+		}
+		return NewJSONFileStorage(location), nil
+	case "gob":
+		if location == "" {
+			return nil, fmt.Errorf("gob storage requires a file path")
+		}
+		return NewGobStorage(location), nil
+	case "sqlite":
+		if location == "" {
+			return nil, fmt.Errorf("sqlite storage requires a file path")
+		}
+		return NewSQLiteStorage(location)
+	case "memory", "mem":
+		return NewInMemoryStorage(), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage scheme: %s", scheme)
+	}
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockRetryInterval is how often a busy lock is tried again
+const lockRetryInterval = 10 * time.Millisecond
+
+// fileLock is an advisory lock on a file, held until Unlock
+type fileLock struct {
+	file *os.File
+}
+
+// lockFile takes an exclusive advisory lock on path, creating the file if
+// needed. It waits while another process holds the lock and gives up with
+// the context error once ctx is done.
+func lockFile(ctx context.Context, path string) (*fileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			return &fileLock{file: file}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// Unlock releases the lock
+func (l *fileLock) Unlock() error {
+	// Closing the descriptor releases the lock too, but say so explicitly
+	unlockErr := unlock(l.file)
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	return unlockErr
+}
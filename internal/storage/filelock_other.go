@@ -0,0 +1,16 @@
+//go:build !unix
+
+package storage
+
+import "os"
+
+// tryLock always succeeds: without flock, only the in-process mutex
+// serializes writers on this platform
+func tryLock(file *os.File) (bool, error) {
+	return true, nil
+}
+
+// unlock is a no-op to match tryLock
+func unlock(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go-fun/internal/task"
+)
+
+// GobStorage implements Storage using an encoding/gob file, which loads and
+// saves large stores much faster than JSON at the cost of readability
+type GobStorage struct {
+	filePath string
+	mutex    sync.RWMutex
+}
+
+// NewGobStorage creates a new gob file storage instance
+func NewGobStorage(filePath string) *GobStorage {
+	return &GobStorage{
+		filePath: filePath,
+	}
+}
+
+// Load loads tasks from the gob file
+func (s *GobStorage) Load(ctx context.Context) ([]*task.Task, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.load()
+}
+
+// load reads the file; callers must hold the mutex
+func (s *GobStorage) load() ([]*task.Task, error) {
+	data, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		return []*task.Task{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", s.filePath, err)
+	}
+
+	if len(data) == 0 {
+		return []*task.Task{}, nil
+	}
+
+	var tasks []*task.Task
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode gob: %w", err)
+	}
+
+	if tasks == nil {
+		tasks = []*task.Task{}
+	}
+	return tasks, nil
+}
+
+// HasData reports whether the file holds any tasks
+func (s *GobStorage) HasData() (bool, error) {
+	info, err := os.Stat(s.filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to stat file %s: %w", s.filePath, err)
+	}
+	if info.Size() == 0 {
+		return false, nil
+	}
+
+	tasks, err := s.Load(context.Background())
+	if err != nil {
+		// Unreadable but non-empty still counts as data worth protecting
+		return true, nil
+	}
+	return len(tasks) > 0, nil
+}
+
+// Save saves tasks to the gob file
+func (s *GobStorage) Save(ctx context.Context, tasks []*task.Task) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.save(tasks)
+}
+
+// save writes the file atomically; callers must hold the mutex
+func (s *GobStorage) save(tasks []*task.Task) error {
+	// Create directory if it doesn't exist
+	dir := filepath.Dir(s.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(tasks); err != nil {
+		return fmt.Errorf("failed to encode gob: %w", err)
+	}
+
+	// Write to temporary file first, then rename (atomic operation)
+	tempFile := s.filePath + ".tmp"
+	if err := os.WriteFile(tempFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := os.Rename(tempFile, s.filePath); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+
+	return nil
+}
+
+// Add adds a new task to storage
+func (s *GobStorage) Add(ctx context.Context, t *task.Task) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tasks, err := s.load()
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid task: %w", err)
+	}
+
+	for _, existing := range tasks {
+		if existing.ID == t.ID {
+			return fmt.Errorf("task with ID %s already exists", t.ID)
+		}
+	}
+
+	tasks = append(tasks, t)
+	return s.save(tasks)
+}
+
+// Update updates an existing task
+func (s *GobStorage) Update(ctx context.Context, id string, t *task.Task) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tasks, err := s.load()
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid task: %w", err)
+	}
+
+	found := false
+	for i, existing := range tasks {
+		if existing.ID == id {
+			t.CreatedAt = existing.CreatedAt
+			t.ID = id
+			tasks[i] = t
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("task with ID %s not found", id)
+	}
+
+	return s.save(tasks)
+}
+
+// Delete deletes a task by ID
+func (s *GobStorage) Delete(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tasks, err := s.load()
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	found := false
+	for i, task := range tasks {
+		if task.ID == id {
+			tasks = append(tasks[:i], tasks[i+1:]...)
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("task with ID %s not found", id)
+	}
+
+	return s.save(tasks)
+}
+
+// GetByID retrieves a task by its ID
+func (s *GobStorage) GetByID(ctx context.Context, id string) (*task.Task, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	tasks, err := s.load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	for _, task := range tasks {
+		if task.ID == id {
+			return task, nil
+		}
+	}
+
+	return nil, fmt.Errorf("task with ID %s not found", id)
+}
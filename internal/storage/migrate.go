@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrDestinationNotEmpty is returned when migrating into a store that
+// already holds tasks without force
+var ErrDestinationNotEmpty = errors.New("destination already holds tasks (use --force to replace them)")
+
+// Migrate copies every task from one backend to another and returns the
+// number of tasks copied. A destination that already holds tasks is refused
+// with ErrDestinationNotEmpty unless force is set, in which case its
+// contents are replaced.
+func Migrate(ctx context.Context, from, to Storage, force bool) (int, error) {
+	if !force {
+		hasData, err := holdsTasks(ctx, to)
+		if err != nil {
+			return 0, fmt.Errorf("failed to check destination: %w", err)
+		}
+		if hasData {
+			return 0, ErrDestinationNotEmpty
+		}
+	}
+
+	tasks, err := from.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load source tasks: %w", err)
+	}
+
+	if err := to.Save(ctx, tasks); err != nil {
+		return 0, fmt.Errorf("failed to save destination tasks: %w", err)
+	}
+
+	return len(tasks), nil
+}
+
+// holdsTasks reports whether s has data, asking the backend directly when
+// it is a DataChecker and loading it otherwise
+func holdsTasks(ctx context.Context, s Storage) (bool, error) {
+	if checker, ok := s.(DataChecker); ok {
+		return checker.HasData()
+	}
+	tasks, err := s.Load(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(tasks) > 0, nil
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"go-fun/internal/task"
+)
+
+// schemaVersion is the version of tasks.json this build writes. Bump it
+// when a change to task.Task means old files can't be read as they are,
+// and add the step upgrading them to schemaMigrations.
+const schemaVersion = 1
+
+// schemaMigrations[v] rewrites a file at version v as version v+1. Files are
+// migrated in memory when loaded and written at schemaVersion on the next
+// save.
+var schemaMigrations = map[int]func(data []byte) ([]byte, error){
+	// Version 0 was a bare array of tasks
+	0: func(data []byte) ([]byte, error) {
+		return json.Marshal(struct {
+			Version int             `json:"version"`
+			Tasks   json.RawMessage `json:"tasks"`
+		}{1, data})
+	},
+}
+
+// jsonEnvelope is the layout of tasks.json: the tasks plus the schema
+// version they were written with
+type jsonEnvelope struct {
+	Version int          `json:"version"`
+	Tasks   []*task.Task `json:"tasks"`
+}
+
+// DecodeTasks reads the contents of a tasks.json file of any known version,
+// legacy bare arrays included
+func DecodeTasks(data []byte) ([]*task.Task, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	version := 0
+	if data[0] != '[' && !bytes.Equal(data, []byte("null")) {
+		var header struct {
+			Version *int `json:"version"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+		if header.Version == nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON: missing schema version")
+		}
+		version = *header.Version
+	}
+	if version < 0 || version > schemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d (this build reads up to %d)", version, schemaVersion)
+	}
+
+	for v := version; v < schemaVersion; v++ {
+		migrated, err := schemaMigrations[v](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate schema version %d: %w", v, err)
+		}
+		data = migrated
+	}
+
+	var envelope jsonEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return envelope.Tasks, nil
+}
+
+// encodeTasks writes tasks at the current schema version
+func encodeTasks(tasks []*task.Task, compact bool) ([]byte, error) {
+	if tasks == nil {
+		tasks = []*task.Task{}
+	}
+	envelope := jsonEnvelope{Version: schemaVersion, Tasks: tasks}
+	if compact {
+		return json.Marshal(envelope)
+	}
+	return json.MarshalIndent(envelope, "", "  ")
+}
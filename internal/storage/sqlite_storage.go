@@ -0,0 +1,435 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-fun/internal/task"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// sqliteMigrations are applied in order; PRAGMA user_version records how
+// many have run. Append new statements rather than editing old ones.
+var sqliteMigrations = []string{
+	`CREATE TABLE tasks (
+		id           TEXT PRIMARY KEY,
+		title        TEXT NOT NULL,
+		description  TEXT NOT NULL DEFAULT '',
+		priority     INTEGER NOT NULL,
+		due_date     TEXT NOT NULL DEFAULT '',
+		completed    INTEGER NOT NULL DEFAULT 0,
+		completed_at TEXT NOT NULL DEFAULT '',
+		created_at   TEXT NOT NULL,
+		updated_at   TEXT NOT NULL,
+		tags         TEXT NOT NULL DEFAULT '[]',
+		related_to   TEXT NOT NULL DEFAULT '[]',
+		depends_on   TEXT NOT NULL DEFAULT '[]',
+		recurrence   TEXT NOT NULL DEFAULT '',
+		parent_id    TEXT NOT NULL DEFAULT ''
+	)`,
+	`ALTER TABLE tasks ADD COLUMN estimate_minutes INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE tasks ADD COLUMN was_late INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE tasks ADD COLUMN start_date TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN notes TEXT NOT NULL DEFAULT ''`,
+}
+
+// sqliteColumns lists the task columns in scan and insert order
+const sqliteColumns = `id, title, description, priority, due_date, completed, completed_at,
+	created_at, updated_at, tags, related_to, depends_on, recurrence, parent_id, estimate_minutes, was_late,
+	start_date, notes`
+
+// sqliteInsert inserts one row with every column in sqliteColumns
+var sqliteInsert = "INSERT INTO tasks (" + sqliteColumns + ") VALUES (?" +
+	strings.Repeat(", ?", strings.Count(sqliteColumns, ",")) + ")"
+
+// SQLiteStorage implements Storage on a SQLite database with one row per
+// task, so single-task operations do not rewrite the whole store
+type SQLiteStorage struct {
+	db *sql.DB
+}
+
+// NewSQLiteStorage opens (creating if needed) the database at path and
+// brings its schema up to date
+func NewSQLiteStorage(path string) (*SQLiteStorage, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	// SQLite allows one writer at a time; a single connection serializes
+	// access instead of failing with "database is locked"
+	db.SetMaxOpenConns(1)
+
+	s := &SQLiteStorage{db: db}
+	if err := s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close releases the database handle
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+// migrate applies any schema migrations the database has not seen yet
+func (s *SQLiteStorage) migrate(ctx context.Context) error {
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, sqliteMigrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+		// PRAGMA does not accept bound parameters
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Load streams every task from the database in insertion order
+func (s *SQLiteStorage) Load(ctx context.Context) ([]*task.Task, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+sqliteColumns+" FROM tasks ORDER BY rowid")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []*task.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// Save replaces every task in the database within one transaction
+func (s *SQLiteStorage) Save(ctx context.Context, tasks []*task.Task) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM tasks"); err != nil {
+		return fmt.Errorf("failed to clear tasks: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, sqliteInsert)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, t := range tasks {
+		args, err := taskArgs(t)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return fmt.Errorf("failed to insert task %s: %w", t.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tasks: %w", err)
+	}
+	return nil
+}
+
+// Add inserts a single task
+func (s *SQLiteStorage) Add(ctx context.Context, t *task.Task) error {
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid task: %w", err)
+	}
+
+	args, err := taskArgs(t)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, sqliteInsert+" ON CONFLICT(id) DO NOTHING", args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert task: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("task with ID %s already exists", t.ID)
+	}
+	return nil
+}
+
+// Update rewrites a single task, preserving its ID and creation time
+func (s *SQLiteStorage) Update(ctx context.Context, id string, t *task.Task) error {
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid task: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var createdAt string
+	err = tx.QueryRowContext(ctx, "SELECT created_at FROM tasks WHERE id = ?", id).Scan(&createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("task with ID %s not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	// Preserve original creation time
+	if t.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return err
+	}
+	t.ID = id // Ensure ID doesn't change
+
+	args, err := taskArgs(t)
+	if err != nil {
+		return err
+	}
+	// Every column except id and created_at, then the id for the WHERE clause
+	updateArgs := append(append([]any{}, args[1:7]...), args[8:]...)
+	updateArgs = append(updateArgs, id)
+
+	_, err = tx.ExecContext(ctx, `UPDATE tasks SET title = ?, description = ?, priority = ?, due_date = ?,
+		completed = ?, completed_at = ?, updated_at = ?, tags = ?, related_to = ?, depends_on = ?,
+		recurrence = ?, parent_id = ?, estimate_minutes = ?, was_late = ?,
+		start_date = ?, notes = ? WHERE id = ?`,
+		updateArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit update: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a single task
+func (s *SQLiteStorage) Delete(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM tasks WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("task with ID %s not found", id)
+	}
+	return nil
+}
+
+// GetByID reads a single task
+func (s *SQLiteStorage) GetByID(ctx context.Context, id string) (*task.Task, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+sqliteColumns+" FROM tasks WHERE id = ?", id)
+	t, err := scanTask(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("task with ID %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// HasData reports whether the database holds any tasks
+func (s *SQLiteStorage) HasData() (bool, error) {
+	var exists bool
+	if err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM tasks)").Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	return exists, nil
+}
+
+// taskArgs flattens a task into values matching sqliteColumns
+func taskArgs(t *task.Task) ([]any, error) {
+	tags, err := marshalStrings(t.Tags)
+	if err != nil {
+		return nil, err
+	}
+	relatedTo, err := marshalStrings(t.RelatedTo)
+	if err != nil {
+		return nil, err
+	}
+	dependsOn, err := marshalStrings(t.DependsOn)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrence := ""
+	if t.Recurrence != nil {
+		data, err := json.Marshal(t.Recurrence)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal recurrence: %w", err)
+		}
+		recurrence = string(data)
+	}
+
+	notes := ""
+	if len(t.Notes) > 0 {
+		data, err := json.Marshal(t.Notes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal notes: %w", err)
+		}
+		notes = string(data)
+	}
+
+	return []any{
+		t.ID,
+		t.Title,
+		t.Description,
+		int(t.Priority),
+		formatSQLiteTime(t.DueDate),
+		t.Completed,
+		formatSQLiteTime(t.CompletedAt),
+		formatSQLiteTime(t.CreatedAt),
+		formatSQLiteTime(t.UpdatedAt),
+		tags,
+		relatedTo,
+		dependsOn,
+		recurrence,
+		t.ParentID,
+		t.EstimateMinutes,
+		t.WasLate,
+		formatSQLiteTime(t.StartDate),
+		notes,
+	}, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask reads one row selected with sqliteColumns
+func scanTask(row rowScanner) (*task.Task, error) {
+	var (
+		t                                      task.Task
+		priority                               int
+		due, completedAt, createdAt, updatedAt string
+		start, notes                           string
+		tags, relatedTo, dependsOn, recurrence string
+	)
+	err := row.Scan(&t.ID, &t.Title, &t.Description, &priority, &due, &t.Completed, &completedAt,
+		&createdAt, &updatedAt, &tags, &relatedTo, &dependsOn, &recurrence, &t.ParentID,
+		&t.EstimateMinutes, &t.WasLate, &start, &notes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan task: %w", err)
+	}
+	t.Priority = task.Priority(priority)
+
+	for _, f := range []struct {
+		dst *time.Time
+		src string
+	}{{&t.DueDate, due}, {&t.CompletedAt, completedAt}, {&t.CreatedAt, createdAt}, {&t.UpdatedAt, updatedAt}, {&t.StartDate, start}} {
+		if *f.dst, err = parseSQLiteTime(f.src); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range []struct {
+		dst *[]string
+		src string
+	}{{&t.Tags, tags}, {&t.RelatedTo, relatedTo}, {&t.DependsOn, dependsOn}} {
+		if *f.dst, err = unmarshalStrings(f.src); err != nil {
+			return nil, err
+		}
+	}
+
+	if recurrence != "" {
+		t.Recurrence = &task.Recurrence{}
+		if err := json.Unmarshal([]byte(recurrence), t.Recurrence); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal recurrence of %s: %w", t.ID, err)
+		}
+	}
+
+	if notes != "" {
+		if err := json.Unmarshal([]byte(notes), &t.Notes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal notes of %s: %w", t.ID, err)
+		}
+	}
+
+	return &t, nil
+}
+
+// formatSQLiteTime stores times as RFC 3339 text, with the zero time empty
+func formatSQLiteTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// parseSQLiteTime reverses formatSQLiteTime
+func parseSQLiteTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse stored time %q: %w", s, err)
+	}
+	return t, nil
+}
+
+// marshalStrings stores a string list as a JSON array column
+func marshalStrings(values []string) (string, error) {
+	if len(values) == 0 {
+		return "[]", nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal list: %w", err)
+	}
+	return string(data), nil
+}
+
+// unmarshalStrings reads a JSON array column, returning nil for an empty list
+// to match tasks loaded from JSON
+func unmarshalStrings(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" || s == "[]" {
+		return nil, nil
+	}
+	var values []string
+	if err := json.Unmarshal([]byte(s), &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal list: %w", err)
+	}
+	return values, nil
+}
@@ -1,12 +1,14 @@
 package storage
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 
 	"go-fun/internal/task"
 )
@@ -21,10 +23,25 @@ type Storage interface {
 	GetByID(ctx context.Context, id string) (*task.Task, error)
 }
 
+// DataChecker is implemented by backends that can tell whether their
+// underlying store holds data without parsing it
+type DataChecker interface {
+	HasData() (bool, error)
+}
+
 // JSONFileStorage implements Storage using JSON file persistence
 type JSONFileStorage struct {
 	filePath string
 	mutex    sync.RWMutex
+
+	// cache keeps the last parse so a GetByID followed by an Update reads
+	// the file once. It has its own lock because reads share mutex.
+	cache      *jsonCache
+	cacheMutex sync.Mutex
+
+	// Compact writes the file without indentation, trading readability for
+	// size. Load reads either layout.
+	Compact bool
 }
 
 // NewJSONFileStorage creates a new JSON file storage instance
@@ -39,9 +56,55 @@ func (s *JSONFileStorage) Load(ctx context.Context) ([]*task.Task, error) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
+	return s.load(ctx)
+}
+
+// jsonCache is one parse of the file, valid while its modification time and
+// size are unchanged
+type jsonCache struct {
+	modTime time.Time
+	size    int64
+	tasks   []*task.Task
+	byID    map[string]*task.Task
+}
+
+// load reads the file; callers must hold the mutex. The tasks are copies the
+// caller may change freely.
+func (s *JSONFileStorage) load(ctx context.Context) ([]*task.Task, error) {
+	c, err := s.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*task.Task, len(c.tasks))
+	for i, t := range c.tasks {
+		tasks[i] = copyTask(t)
+	}
+	return tasks, nil
+}
+
+// cached returns the parsed file, reading it again only when its mtime or
+// size changed since the last parse. The entry is shared, so callers must
+// copy tasks before handing them out.
+func (s *JSONFileStorage) cached(ctx context.Context) (*jsonCache, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Check if file exists
-	if _, err := os.Stat(s.filePath); os.IsNotExist(err) {
-		return []*task.Task{}, nil
+	info, err := os.Stat(s.filePath)
+	if os.IsNotExist(err) {
+		return &jsonCache{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file %s: %w", s.filePath, err)
+	}
+
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	if c := s.cache; c != nil && c.modTime.Equal(info.ModTime()) && c.size == info.Size() {
+		return c, nil
 	}
 
 	data, err := os.ReadFile(s.filePath)
@@ -49,16 +112,68 @@ func (s *JSONFileStorage) Load(ctx context.Context) ([]*task.Task, error) {
 		return nil, fmt.Errorf("failed to read file %s: %w", s.filePath, err)
 	}
 
-	if len(data) == 0 {
-		return []*task.Task{}, nil
+	c := &jsonCache{modTime: info.ModTime(), size: info.Size()}
+	if c.tasks, err = DecodeTasks(data); err != nil {
+		return nil, err
 	}
 
-	var tasks []*task.Task
-	if err := json.Unmarshal(data, &tasks); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	c.byID = make(map[string]*task.Task, len(c.tasks))
+	for _, t := range c.tasks {
+		// The first of any duplicates wins, as a linear scan would find
+		if _, ok := c.byID[t.ID]; !ok {
+			c.byID[t.ID] = t
+		}
 	}
+	s.cache = c
+	return c, nil
+}
 
-	return tasks, nil
+// invalidate drops the cached parse so the next read goes to disk
+func (s *JSONFileStorage) invalidate() {
+	s.cacheMutex.Lock()
+	s.cache = nil
+	s.cacheMutex.Unlock()
+}
+
+// copyTask copies a task deeply enough that changes to the copy never reach
+// the cache
+func copyTask(t *task.Task) *task.Task {
+	c := *t
+	c.Tags = slices.Clone(t.Tags)
+	c.RelatedTo = slices.Clone(t.RelatedTo)
+	c.DependsOn = slices.Clone(t.DependsOn)
+	c.Notes = slices.Clone(t.Notes)
+	if t.Recurrence != nil {
+		r := *t.Recurrence
+		c.Recurrence = &r
+	}
+	return &c
+}
+
+// HasData reports whether the file holds anything beyond an empty task list
+func (s *JSONFileStorage) HasData() (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	data, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read file %s: %w", s.filePath, err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("[]")) {
+		return false, nil
+	}
+	// An empty task list as Save writes it, in either layout
+	for _, compact := range []bool{true, false} {
+		if empty, err := encodeTasks(nil, compact); err == nil && bytes.Equal(trimmed, empty) {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 // Save saves tasks to the JSON file
@@ -66,23 +181,65 @@ func (s *JSONFileStorage) Save(ctx context.Context, tasks []*task.Task) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.save(ctx, tasks)
+}
+
+// lock takes the advisory lock on the file's .lock sibling, so another
+// process can't save between our load and save; callers must hold the
+// mutex. The cache is dropped because the file may have changed while we
+// waited.
+func (s *JSONFileStorage) lock(ctx context.Context) (func(), error) {
+	l, err := lockFile(ctx, s.filePath+".lock")
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return func() { l.Unlock() }, nil
+}
+
+// save writes the file atomically; callers must hold the mutex. A cancelled
+// ctx stops it before the file is replaced.
+func (s *JSONFileStorage) save(ctx context.Context, tasks []*task.Task) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Whatever happens below, the cached parse no longer matches the file
+	s.invalidate()
+
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(s.filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
-	data, err := json.MarshalIndent(tasks, "", "  ")
+	data, err := encodeTasks(tasks, s.Compact)
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
+	// Marshaling a large store takes a while, so check again
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Write to temporary file first, then rename (atomic operation)
 	tempFile := s.filePath + ".tmp"
 	if err := os.WriteFile(tempFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write temporary file: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		os.Remove(tempFile)
+		return err
+	}
+
 	if err := os.Rename(tempFile, s.filePath); err != nil {
 		// Clean up temp file if rename fails
 		os.Remove(tempFile)
@@ -94,7 +251,16 @@ func (s *JSONFileStorage) Save(ctx context.Context, tasks []*task.Task) error {
 
 // Add adds a new task to storage
 func (s *JSONFileStorage) Add(ctx context.Context, t *task.Task) error {
-	tasks, err := s.Load(ctx)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tasks, err := s.load(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load tasks: %w", err)
 	}
@@ -112,12 +278,21 @@ func (s *JSONFileStorage) Add(ctx context.Context, t *task.Task) error {
 	}
 
 	tasks = append(tasks, t)
-	return s.Save(ctx, tasks)
+	return s.save(ctx, tasks)
 }
 
 // Update updates an existing task
 func (s *JSONFileStorage) Update(ctx context.Context, id string, t *task.Task) error {
-	tasks, err := s.Load(ctx)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tasks, err := s.load(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load tasks: %w", err)
 	}
@@ -143,12 +318,21 @@ func (s *JSONFileStorage) Update(ctx context.Context, id string, t *task.Task) e
 		return fmt.Errorf("task with ID %s not found", id)
 	}
 
-	return s.Save(ctx, tasks)
+	return s.save(ctx, tasks)
 }
 
 // Delete deletes a task by ID
 func (s *JSONFileStorage) Delete(ctx context.Context, id string) error {
-	tasks, err := s.Load(ctx)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tasks, err := s.load(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load tasks: %w", err)
 	}
@@ -166,28 +350,31 @@ func (s *JSONFileStorage) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("task with ID %s not found", id)
 	}
 
-	return s.Save(ctx, tasks)
+	return s.save(ctx, tasks)
 }
 
 // GetByID retrieves a task by its ID
 func (s *JSONFileStorage) GetByID(ctx context.Context, id string) (*task.Task, error) {
-	tasks, err := s.Load(ctx)
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	c, err := s.cached(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load tasks: %w", err)
 	}
 
-	for _, task := range tasks {
-		if task.ID == id {
-			return task, nil
-		}
+	if t, ok := c.byID[id]; ok {
+		return copyTask(t), nil
 	}
 
 	return nil, fmt.Errorf("task with ID %s not found", id)
 }
 
-// InMemoryStorage is a simple in-memory storage for testing
+// InMemoryStorage is a simple in-memory storage for testing, indexed by ID
+// so lookups stay constant-time on large benchmarks
 type InMemoryStorage struct {
 	tasks []*task.Task
+	index map[string]int // task ID to its position in tasks
 	mutex sync.RWMutex
 }
 
@@ -195,6 +382,14 @@ type InMemoryStorage struct {
 func NewInMemoryStorage() *InMemoryStorage {
 	return &InMemoryStorage{
 		tasks: make([]*task.Task, 0),
+		index: make(map[string]int),
+	}
+}
+
+// reindex rebuilds the ID index for positions from onward
+func (s *InMemoryStorage) reindex(from int) {
+	for i := from; i < len(s.tasks); i++ {
+		s.index[s.tasks[i].ID] = i
 	}
 }
 
@@ -217,6 +412,8 @@ func (s *InMemoryStorage) Save(ctx context.Context, tasks []*task.Task) error {
 	// Store a copy to prevent external modifications
 	s.tasks = make([]*task.Task, len(tasks))
 	copy(s.tasks, tasks)
+	s.index = make(map[string]int, len(tasks))
+	s.reindex(0)
 	return nil
 }
 
@@ -230,12 +427,11 @@ func (s *InMemoryStorage) Add(ctx context.Context, t *task.Task) error {
 	}
 
 	// Check for duplicate ID
-	for _, existing := range s.tasks {
-		if existing.ID == t.ID {
-			return fmt.Errorf("task with ID %s already exists", t.ID)
-		}
+	if _, exists := s.index[t.ID]; exists {
+		return fmt.Errorf("task with ID %s already exists", t.ID)
 	}
 
+	s.index[t.ID] = len(s.tasks)
 	s.tasks = append(s.tasks, t)
 	return nil
 }
@@ -249,21 +445,14 @@ func (s *InMemoryStorage) Update(ctx context.Context, id string, t *task.Task) e
 		return fmt.Errorf("invalid task: %w", err)
 	}
 
-	found := false
-	for i, existing := range s.tasks {
-		if existing.ID == id {
-			t.CreatedAt = existing.CreatedAt
-			t.ID = id
-			s.tasks[i] = t
-			found = true
-			break
-		}
-	}
-
+	i, found := s.index[id]
 	if !found {
 		return fmt.Errorf("task with ID %s not found", id)
 	}
 
+	t.CreatedAt = s.tasks[i].CreatedAt
+	t.ID = id
+	s.tasks[i] = t
 	return nil
 }
 
@@ -272,19 +461,15 @@ func (s *InMemoryStorage) Delete(ctx context.Context, id string) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	found := false
-	for i, task := range s.tasks {
-		if task.ID == id {
-			s.tasks = append(s.tasks[:i], s.tasks[i+1:]...)
-			found = true
-			break
-		}
-	}
-
+	i, found := s.index[id]
 	if !found {
 		return fmt.Errorf("task with ID %s not found", id)
 	}
 
+	// Shift the tail down to keep insertion order, then fix its positions
+	s.tasks = append(s.tasks[:i], s.tasks[i+1:]...)
+	delete(s.index, id)
+	s.reindex(i)
 	return nil
 }
 
@@ -293,10 +478,8 @@ func (s *InMemoryStorage) GetByID(ctx context.Context, id string) (*task.Task, e
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	for _, task := range s.tasks {
-		if task.ID == id {
-			return task, nil
-		}
+	if i, found := s.index[id]; found {
+		return s.tasks[i], nil
 	}
 
 	return nil, fmt.Errorf("task with ID %s not found", id)
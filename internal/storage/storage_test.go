@@ -1,10 +1,15 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -98,6 +103,89 @@ func TestInMemoryStorage(t *testing.T) {
 	}
 }
 
+func TestInMemoryStorageBulkAddUniqueIDs(t *testing.T) {
+	storage := NewInMemoryStorage()
+	ctx := context.Background()
+
+	const n = 10000
+	seen := make(map[string]bool, n)
+	for i := 0; i < n; i++ {
+		tt := task.NewTask("Bulk task", "", task.Medium, time.Time{}, nil)
+		if !strings.HasPrefix(tt.ID, "task_") {
+			t.Fatalf("Expected a task_ prefix, got %s", tt.ID)
+		}
+		if seen[tt.ID] {
+			t.Fatalf("Duplicate ID %s after %d tasks", tt.ID, i)
+		}
+		seen[tt.ID] = true
+		if err := storage.Add(ctx, tt); err != nil {
+			t.Fatalf("Unexpected error adding task %d: %v", i, err)
+		}
+	}
+}
+
+func TestInMemoryStorageIndexAfterDeletes(t *testing.T) {
+	storage := NewInMemoryStorage()
+	ctx := context.Background()
+	now := time.Now()
+
+	for i := 0; i < 6; i++ {
+		tk := &task.Task{ID: fmt.Sprintf("test-%d", i), Title: "Task", CreatedAt: now, UpdatedAt: now}
+		if err := storage.Add(ctx, tk); err != nil {
+			t.Fatalf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	for _, id := range []string{"test-2", "test-0", "test-5"} {
+		if err := storage.Delete(ctx, id); err != nil {
+			t.Fatalf("Unexpected error deleting %s: %v", id, err)
+		}
+	}
+
+	tasks, err := storage.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	var ids []string
+	for _, tk := range tasks {
+		ids = append(ids, tk.ID)
+	}
+	expected := []string{"test-1", "test-3", "test-4"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("Expected order %v after deletes, got %v", expected, ids)
+	}
+
+	if len(storage.index) != len(expected) {
+		t.Errorf("Expected %d index entries, got %d", len(expected), len(storage.index))
+	}
+	for i, id := range expected {
+		if pos, ok := storage.index[id]; !ok || pos != i {
+			t.Errorf("Expected %s indexed at %d, got %d (present: %v)", id, i, pos, ok)
+		}
+		got, err := storage.GetByID(ctx, id)
+		if err != nil || got.ID != id {
+			t.Errorf("Expected GetByID(%s) to find it, got %v, %v", id, got, err)
+		}
+	}
+	if _, err := storage.GetByID(ctx, "test-2"); err == nil {
+		t.Error("Expected deleted task to be gone from the index")
+	}
+
+	// Updates and re-adds go through the shifted positions
+	if err := storage.Update(ctx, "test-4", &task.Task{Title: "Updated", UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+	if got, _ := storage.GetByID(ctx, "test-4"); got.Title != "Updated" {
+		t.Errorf("Expected updated title, got %q", got.Title)
+	}
+	if err := storage.Add(ctx, &task.Task{ID: "test-2", Title: "Again", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error re-adding deleted ID: %v", err)
+	}
+	if pos := storage.index["test-2"]; pos != 3 {
+		t.Errorf("Expected re-added task at position 3, got %d", pos)
+	}
+}
+
 func TestJSONFileStorage(t *testing.T) {
 	// Create temporary directory for test
 	tempDir, err := os.MkdirTemp("", "go-fun-test-*")
@@ -193,6 +281,175 @@ func TestJSONFileStorage(t *testing.T) {
 	}
 }
 
+func TestJSONFileStorageCompact(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	ctx := context.Background()
+
+	now := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	var tasks []*task.Task
+	for i := 0; i < 20; i++ {
+		tasks = append(tasks, &task.Task{
+			ID:        fmt.Sprintf("task-%d", i),
+			Title:     fmt.Sprintf("Task %d", i),
+			Priority:  task.Medium,
+			Tags:      []string{"work"},
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	indentedPath := filepath.Join(tempDir, "indented.json")
+	if err := NewJSONFileStorage(indentedPath).Save(ctx, tasks); err != nil {
+		t.Fatalf("Unexpected error saving indented tasks: %v", err)
+	}
+
+	compactPath := filepath.Join(tempDir, "compact.json")
+	compact := NewJSONFileStorage(compactPath)
+	compact.Compact = true
+	if err := compact.Save(ctx, tasks); err != nil {
+		t.Fatalf("Unexpected error saving compact tasks: %v", err)
+	}
+
+	indentedInfo, err := os.Stat(indentedPath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading indented file: %v", err)
+	}
+	compactInfo, err := os.Stat(compactPath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading compact file: %v", err)
+	}
+	if compactInfo.Size() >= indentedInfo.Size() {
+		t.Errorf("Expected compact file smaller than %d bytes, got %d", indentedInfo.Size(), compactInfo.Size())
+	}
+
+	// Both layouts load, whichever mode the reader is in
+	for _, path := range []string{compactPath, indentedPath} {
+		reader := NewJSONFileStorage(path)
+		reader.Compact = true
+		loaded, err := reader.Load(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error loading %s: %v", path, err)
+		}
+		if !reflect.DeepEqual(loaded, tasks) {
+			t.Errorf("Expected %s to round-trip", path)
+		}
+	}
+}
+
+func TestJSONFileStorageCache(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	filePath := filepath.Join(tempDir, "tasks.json")
+	s := NewJSONFileStorage(filePath)
+	if err := s.Save(ctx, []*task.Task{{ID: "a", Title: "Original", Tags: []string{"x"}}}); err != nil {
+		t.Fatalf("Unexpected error saving tasks: %v", err)
+	}
+
+	// Changes to a returned task stay out of the cache
+	got, err := s.GetByID(ctx, "a")
+	if err != nil {
+		t.Fatalf("Unexpected error getting task: %v", err)
+	}
+	got.Title = "Scribbled"
+	got.Tags[0] = "y"
+	again, err := s.GetByID(ctx, "a")
+	if err != nil {
+		t.Fatalf("Unexpected error getting task: %v", err)
+	}
+	if again.Title != "Original" || again.Tags[0] != "x" {
+		t.Errorf("Expected the cached task to be untouched, got %+v", again)
+	}
+
+	// Another process rewrites the file with content of the same size; a
+	// newer mtime is enough to notice
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading file: %v", err)
+	}
+	data = bytes.Replace(data, []byte("Original"), []byte("External"), 1)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatalf("Unexpected error writing file: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filePath, later, later); err != nil {
+		t.Fatalf("Unexpected error touching file: %v", err)
+	}
+
+	got, err = s.GetByID(ctx, "a")
+	if err != nil {
+		t.Fatalf("Unexpected error getting task: %v", err)
+	}
+	if got.Title != "External" {
+		t.Errorf("Expected the external change to be read, got title %q", got.Title)
+	}
+
+	// A save through the storage is seen right away
+	if err := s.Save(ctx, []*task.Task{{ID: "b", Title: "Saved"}}); err != nil {
+		t.Fatalf("Unexpected error saving tasks: %v", err)
+	}
+	if _, err := s.GetByID(ctx, "a"); err == nil {
+		t.Error("Expected the replaced task to be gone after Save")
+	}
+	tasks, err := s.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "b" {
+		t.Errorf("Expected only task b, got %+v", tasks)
+	}
+}
+
+func TestJSONFileStorageCancelledContext(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	filePath := filepath.Join(tempDir, "tasks.json")
+	s := NewJSONFileStorage(filePath)
+	if err := s.Save(context.Background(), []*task.Task{{ID: "kept", Title: "Kept"}}); err != nil {
+		t.Fatalf("Unexpected error saving tasks: %v", err)
+	}
+	before, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading file: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := s.Load(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Load to return context.Canceled, got %v", err)
+	}
+	if err := s.Save(ctx, []*task.Task{{ID: "replaced", Title: "Replaced"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Save to return context.Canceled, got %v", err)
+	}
+	if err := s.Add(ctx, &task.Task{ID: "added", Title: "Added"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Add to return context.Canceled, got %v", err)
+	}
+
+	after, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading file: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("Expected the file to be untouched")
+	}
+	if _, err := os.Stat(filePath + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected no temporary file to be left behind")
+	}
+}
+
 func TestJSONFileStorageConcurrentAccess(t *testing.T) {
 	// Create temporary directory for test
 	tempDir, err := os.MkdirTemp("", "go-fun-test-concurrent-*")
@@ -205,8 +462,7 @@ func TestJSONFileStorageConcurrentAccess(t *testing.T) {
 	storage := NewJSONFileStorage(filePath)
 	ctx := context.Background()
 
-	// Test concurrent writes - note that without proper locking,
-	// some writes may be lost due to race conditions
+	// Concurrent writes must not lose each other's tasks
 	numGoroutines := 10
 	done := make(chan error, numGoroutines)
 
@@ -236,16 +492,74 @@ func TestJSONFileStorageConcurrentAccess(t *testing.T) {
 		}
 	}
 
-	// Verify tasks were saved (may be less than expected due to race conditions)
+	// Verify every task was saved
 	tasks, err := storage.Load(ctx)
 	if err != nil {
 		t.Fatalf("Unexpected error loading tasks: %v", err)
 	}
-	if len(tasks) == 0 {
-		t.Error("Expected at least some tasks to be saved")
+	if len(tasks) != numGoroutines {
+		t.Errorf("Expected exactly %d tasks, got %d", numGoroutines, len(tasks))
+	}
+}
+
+func TestJSONFileStorageSeparateInstances(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tasks.json")
+	ctx := context.Background()
+
+	// Each writer opens the file afresh, as a separate go-fun process would,
+	// so only the file lock keeps them from saving over each other
+	const writers, perWriter = 2, 25
+	done := make(chan error, writers)
+	for w := 0; w < writers; w++ {
+		go func(w int) {
+			for i := 0; i < perWriter; i++ {
+				now := time.Now()
+				err := NewJSONFileStorage(filePath).Add(ctx, &task.Task{
+					ID: fmt.Sprintf("w%d-%d", w, i), Title: "Task", Priority: task.Medium, CreatedAt: now, UpdatedAt: now,
+				})
+				if err != nil {
+					done <- err
+					return
+				}
+			}
+			done <- nil
+		}(w)
+	}
+	for w := 0; w < writers; w++ {
+		if err := <-done; err != nil {
+			t.Errorf("Unexpected error adding task: %v", err)
+		}
+	}
+
+	tasks, err := NewJSONFileStorage(filePath).Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	if len(tasks) != writers*perWriter {
+		t.Errorf("Expected %d tasks, got %d", writers*perWriter, len(tasks))
+	}
+}
+
+func TestJSONFileStorageLockRespectsContext(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tasks.json")
+	held, err := lockFile(context.Background(), filePath+".lock")
+	if err != nil {
+		t.Fatalf("Unexpected error taking lock: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	now := time.Now()
+	err = NewJSONFileStorage(filePath).Add(ctx, &task.Task{ID: "blocked", Title: "Blocked", CreatedAt: now, UpdatedAt: now})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the wait for the lock to time out, got %v", err)
+	}
+
+	if err := held.Unlock(); err != nil {
+		t.Fatalf("Unexpected error releasing lock: %v", err)
 	}
-	if len(tasks) > numGoroutines {
-		t.Errorf("Expected at most %d tasks, got %d", numGoroutines, len(tasks))
+	if err := NewJSONFileStorage(filePath).Add(context.Background(), &task.Task{ID: "free", Title: "Free", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Errorf("Unexpected error adding after the lock is released: %v", err)
 	}
 }
 
@@ -317,6 +631,343 @@ func TestJSONFileStorageErrorHandling(t *testing.T) {
 	}
 }
 
+func TestOpenSQLite(t *testing.T) {
+	s, err := Open("sqlite:" + filepath.Join(t.TempDir(), "tasks.db"))
+	if err != nil {
+		t.Fatalf("Unexpected error opening sqlite DSN: %v", err)
+	}
+	defer s.(*SQLiteStorage).Close()
+
+	if _, err := Open("sqlite:"); err == nil {
+		t.Error("Expected error for sqlite DSN without a path")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		dsn     string
+		want    string
+		wantErr bool
+	}{
+		{"memory:", "*storage.InMemoryStorage", false},
+		{"json:/tmp/tasks.json", "*storage.JSONFileStorage", false},
+		{"/tmp/tasks.json", "*storage.JSONFileStorage", false},
+		{"tasks.json", "*storage.JSONFileStorage", false},
+		{"gob:/tmp/tasks.gob", "*storage.GobStorage", false},
+		{"json:", "", true},
+		{"gob:", "", true},
+		{"redis://localhost", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			s, err := Open(tt.dsn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Open(%q) error = %v, wantErr %v", tt.dsn, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := fmt.Sprintf("%T", s); got != tt.want {
+				t.Errorf("Open(%q) = %s, want %s", tt.dsn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGobStorageRoundTrip(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-gob-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	due := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	created := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	want := []*task.Task{
+		{
+			ID:          "gob-1",
+			Title:       "Full task",
+			Description: "Every field set",
+			Priority:    task.High,
+			DueDate:     due,
+			Completed:   true,
+			CompletedAt: due.Add(-time.Hour),
+			CreatedAt:   created,
+			UpdatedAt:   created.Add(time.Hour),
+			Tags:        []string{"home", "work"},
+			RelatedTo:   []string{"gob-2"},
+			DependsOn:   []string{"gob-2"},
+		},
+		{ID: "gob-2", Title: "Bare task", Priority: task.Low, CreatedAt: created, UpdatedAt: created},
+	}
+
+	gobStorage := NewGobStorage(filepath.Join(tempDir, "tasks.gob"))
+	if err := gobStorage.Save(ctx, want); err != nil {
+		t.Fatalf("Unexpected error saving tasks: %v", err)
+	}
+
+	got, err := gobStorage.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d tasks, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("Task %d did not round-trip:\n got %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+
+	// Migrating to JSON and back preserves the tasks
+	jsonStorage := NewJSONFileStorage(filepath.Join(tempDir, "tasks.json"))
+	if n, err := Migrate(ctx, gobStorage, jsonStorage, false); err != nil || n != 2 {
+		t.Fatalf("Migrate to JSON = %d, %v", n, err)
+	}
+	back := NewGobStorage(filepath.Join(tempDir, "back.gob"))
+	if _, err := Migrate(ctx, jsonStorage, back, false); err != nil {
+		t.Fatalf("Unexpected error migrating back: %v", err)
+	}
+	migrated, err := back.GetByID(ctx, "gob-1")
+	if err != nil {
+		t.Fatalf("Unexpected error getting migrated task: %v", err)
+	}
+	if !migrated.DueDate.Equal(due) || len(migrated.Tags) != 2 {
+		t.Errorf("Migrated task lost fields: %+v", migrated)
+	}
+
+	// A destination with tasks is only replaced with force
+	empty := NewInMemoryStorage()
+	if _, err := Migrate(ctx, empty, back, false); !errors.Is(err, ErrDestinationNotEmpty) {
+		t.Fatalf("Expected ErrDestinationNotEmpty, got %v", err)
+	}
+	if kept, err := back.Load(ctx); err != nil || len(kept) != 2 {
+		t.Fatalf("Expected the refused migration to keep 2 tasks, got %d, %v", len(kept), err)
+	}
+	if _, err := Migrate(ctx, empty, back, true); err != nil {
+		t.Fatalf("Unexpected error forcing migration: %v", err)
+	}
+	if kept, err := back.Load(ctx); err != nil || len(kept) != 0 {
+		t.Errorf("Expected the forced migration to replace the tasks, got %d, %v", len(kept), err)
+	}
+}
+
+func TestGobStorageCRUD(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-gob-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	storage := NewGobStorage(filepath.Join(tempDir, "tasks.gob"))
+	ctx := context.Background()
+
+	testTask := task.NewTask("Gob task", "Stored as gob", task.Medium, time.Time{}, nil)
+	if err := storage.Add(ctx, testTask); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	if err := storage.Add(ctx, testTask); err == nil {
+		t.Error("Expected error adding a duplicate ID")
+	}
+
+	testTask.Title = "Renamed"
+	if err := storage.Update(ctx, testTask.ID, testTask); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+	got, err := storage.GetByID(ctx, testTask.ID)
+	if err != nil || got.Title != "Renamed" {
+		t.Fatalf("Expected renamed task, got %+v, %v", got, err)
+	}
+
+	if err := storage.Delete(ctx, testTask.ID); err != nil {
+		t.Fatalf("Unexpected error deleting task: %v", err)
+	}
+	if hasData, _ := storage.HasData(); hasData {
+		t.Error("Expected no data after deleting the only task")
+	}
+}
+
+func TestSQLiteStorage(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "go-fun-sqlite-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	dbPath := filepath.Join(tempDir, "tasks.db")
+	storage, err := NewSQLiteStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Unexpected error opening database: %v", err)
+	}
+	defer storage.Close()
+	ctx := context.Background()
+
+	// Test empty storage
+	tasks, err := storage.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading empty storage: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("Expected 0 tasks, got %d", len(tasks))
+	}
+	if hasData, err := storage.HasData(); err != nil || hasData {
+		t.Errorf("Expected an empty database to hold no data, got %v (%v)", hasData, err)
+	}
+
+	// Test adding a task
+	created := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	testTask := &task.Task{
+		ID:              "test-1",
+		Title:           "Test Task",
+		Description:     "Test Description",
+		Priority:        task.High,
+		DueDate:         created.Add(24 * time.Hour),
+		CreatedAt:       created,
+		UpdatedAt:       created,
+		Tags:            []string{"home", "urgent"},
+		DependsOn:       []string{"test-2"},
+		Recurrence:      &task.Recurrence{Interval: task.Weekly, Count: 3},
+		EstimateMinutes: 90,
+		StartDate:       created.Add(2 * time.Hour),
+		Notes:           []task.Note{{Text: "Bought the paint", CreatedAt: created.Add(time.Hour)}},
+	}
+
+	if err := storage.Add(ctx, testTask); err != nil {
+		t.Fatalf("Unexpected error adding task: %v", err)
+	}
+	if err := storage.Add(ctx, testTask); err == nil {
+		t.Error("Expected error adding a duplicate ID")
+	}
+
+	// Test getting task by ID, including tags
+	retrievedTask, err := storage.GetByID(ctx, testTask.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error getting task by ID: %v", err)
+	}
+	if !reflect.DeepEqual(retrievedTask.Tags, testTask.Tags) {
+		t.Errorf("Expected tags %v, got %v", testTask.Tags, retrievedTask.Tags)
+	}
+	if !retrievedTask.DueDate.Equal(testTask.DueDate) || !retrievedTask.CreatedAt.Equal(created) {
+		t.Errorf("Expected times to round-trip, got %+v", retrievedTask)
+	}
+	if retrievedTask.Recurrence == nil || *retrievedTask.Recurrence != *testTask.Recurrence {
+		t.Errorf("Expected recurrence to round-trip, got %+v", retrievedTask.Recurrence)
+	}
+	if !retrievedTask.StartDate.Equal(testTask.StartDate) {
+		t.Errorf("Expected start date %v, got %v", testTask.StartDate, retrievedTask.StartDate)
+	}
+	if len(retrievedTask.Notes) != 1 || retrievedTask.Notes[0].Text != "Bought the paint" || !retrievedTask.Notes[0].CreatedAt.Equal(testTask.Notes[0].CreatedAt) {
+		t.Errorf("Expected notes to round-trip, got %+v", retrievedTask.Notes)
+	}
+	if retrievedTask.EstimateMinutes != testTask.EstimateMinutes {
+		t.Errorf("Expected estimate %d, got %d", testTask.EstimateMinutes, retrievedTask.EstimateMinutes)
+	}
+	if !reflect.DeepEqual(retrievedTask.DependsOn, testTask.DependsOn) {
+		t.Errorf("Expected dependencies to round-trip, got %v", retrievedTask.DependsOn)
+	}
+
+	// Test updating task keeps the creation time
+	updatedTask := *testTask
+	updatedTask.Title = "Updated Task"
+	updatedTask.Tags = nil
+	updatedTask.CreatedAt = time.Now()
+	updatedTask.UpdatedAt = time.Now()
+
+	if err := storage.Update(ctx, testTask.ID, &updatedTask); err != nil {
+		t.Fatalf("Unexpected error updating task: %v", err)
+	}
+
+	retrievedTask, err = storage.GetByID(ctx, testTask.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error getting updated task: %v", err)
+	}
+	if retrievedTask.Title != "Updated Task" || retrievedTask.Tags != nil {
+		t.Errorf("Expected updated title and no tags, got %q %v", retrievedTask.Title, retrievedTask.Tags)
+	}
+	if !retrievedTask.CreatedAt.Equal(created) {
+		t.Errorf("Expected creation time preserved, got %v", retrievedTask.CreatedAt)
+	}
+
+	if hasData, err := storage.HasData(); err != nil || !hasData {
+		t.Errorf("Expected the database to hold data, got %v (%v)", hasData, err)
+	}
+
+	// Tasks persist across reopening the database
+	storage.Close()
+	storage, err = NewSQLiteStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening database: %v", err)
+	}
+	if _, err := storage.GetByID(ctx, testTask.ID); err != nil {
+		t.Fatalf("Expected task after reopening: %v", err)
+	}
+
+	// Save replaces everything, keeping the given order
+	second := &task.Task{ID: "test-2", Title: "Second", Priority: task.Low, CreatedAt: created, UpdatedAt: created}
+	if err := storage.Save(ctx, []*task.Task{second, retrievedTask}); err != nil {
+		t.Fatalf("Unexpected error saving tasks: %v", err)
+	}
+	tasks, err = storage.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != "test-2" || tasks[1].ID != "test-1" {
+		t.Errorf("Expected tasks in saved order, got %d tasks", len(tasks))
+	}
+
+	// Test deleting task
+	if err := storage.Delete(ctx, testTask.ID); err != nil {
+		t.Fatalf("Unexpected error deleting task: %v", err)
+	}
+	if err := storage.Delete(ctx, testTask.ID); err == nil {
+		t.Error("Expected error deleting a missing task")
+	}
+	if _, err := storage.GetByID(ctx, testTask.ID); err == nil {
+		t.Error("Expected error getting a deleted task")
+	}
+	if err := storage.Update(ctx, testTask.ID, testTask); err == nil {
+		t.Error("Expected error updating a deleted task")
+	}
+}
+
+func TestSQLiteStorageConcurrentAccess(t *testing.T) {
+	storage, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	if err != nil {
+		t.Fatalf("Unexpected error opening database: %v", err)
+	}
+	defer storage.Close()
+	ctx := context.Background()
+
+	numGoroutines := 10
+	done := make(chan error, numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		go func(id int) {
+			done <- storage.Add(ctx, &task.Task{
+				ID:        fmt.Sprintf("test-%d", id),
+				Title:     fmt.Sprintf("Test Task %d", id),
+				Priority:  task.Medium,
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			})
+		}(i)
+	}
+	for i := 0; i < numGoroutines; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("Error in goroutine %d: %v", i, err)
+		}
+	}
+
+	tasks, err := storage.Load(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error loading tasks: %v", err)
+	}
+	if len(tasks) != numGoroutines {
+		t.Errorf("Expected exactly %d tasks, got %d", numGoroutines, len(tasks))
+	}
+}
+
 // Benchmark tests
 func BenchmarkInMemoryStorageAdd(b *testing.B) {
 	storage := NewInMemoryStorage()
@@ -387,3 +1038,171 @@ func BenchmarkInMemoryStorageLoad(b *testing.B) {
 		storage.Load(ctx)
 	}
 }
+
+// benchmarkFileLoad measures Load on a store holding n tasks
+func benchmarkFileLoad(b *testing.B, s Storage, n int) {
+	ctx := context.Background()
+	now := time.Now()
+
+	tasks := make([]*task.Task, n)
+	for i := range tasks {
+		tasks[i] = &task.Task{
+			ID:          fmt.Sprintf("test-%d", i),
+			Title:       "Benchmark Task",
+			Description: "Benchmark Description",
+			Priority:    task.Medium,
+			DueDate:     now.Add(time.Duration(i) * time.Minute),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Tags:        []string{"bench"},
+		}
+	}
+	if err := s.Save(ctx, tasks); err != nil {
+		b.Fatalf("Failed to save tasks: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Measure parsing, not the JSON backend's cache
+		if js, ok := s.(*JSONFileStorage); ok {
+			js.invalidate()
+		}
+		if _, err := s.Load(ctx); err != nil {
+			b.Fatalf("Failed to load tasks: %v", err)
+		}
+	}
+}
+
+// BenchmarkJSONFileStorageGetByID10k compares lookups that reparse the file
+// with ones served from the cache
+func BenchmarkJSONFileStorageGetByID10k(b *testing.B) {
+	s := NewJSONFileStorage(filepath.Join(b.TempDir(), "tasks.json"))
+	ctx := context.Background()
+	now := time.Now()
+
+	const n = 10000
+	tasks := make([]*task.Task, n)
+	for i := range tasks {
+		tasks[i] = &task.Task{ID: fmt.Sprintf("test-%d", i), Title: "Benchmark Task", CreatedAt: now, UpdatedAt: now}
+	}
+	if err := s.Save(ctx, tasks); err != nil {
+		b.Fatalf("Failed to save tasks: %v", err)
+	}
+
+	for _, bm := range []struct {
+		name  string
+		cache bool
+	}{{"uncached", false}, {"cached", true}} {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if !bm.cache {
+					s.invalidate()
+				}
+				if _, err := s.GetByID(ctx, tasks[i%n].ID); err != nil {
+					b.Fatalf("Failed to get task: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkJSONFileStorageLoad100k(b *testing.B) {
+	benchmarkFileLoad(b, NewJSONFileStorage(filepath.Join(b.TempDir(), "tasks.json")), 100000)
+}
+
+func BenchmarkGobStorageLoad100k(b *testing.B) {
+	benchmarkFileLoad(b, NewGobStorage(filepath.Join(b.TempDir(), "tasks.gob")), 100000)
+}
+
+func BenchmarkInMemoryStorageGetByID100k(b *testing.B) {
+	storage := NewInMemoryStorage()
+	ctx := context.Background()
+	now := time.Now()
+
+	const n = 100000
+	tasks := make([]*task.Task, n)
+	for i := range tasks {
+		tasks[i] = &task.Task{ID: fmt.Sprintf("test-%d", i), Title: "Benchmark Task", CreatedAt: now, UpdatedAt: now}
+	}
+	if err := storage.Save(ctx, tasks); err != nil {
+		b.Fatalf("Failed to save tasks: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := storage.GetByID(ctx, tasks[i%n].ID); err != nil {
+			b.Fatalf("Failed to get task: %v", err)
+		}
+	}
+}
+
+func TestJSONFileStorageSchemaVersions(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"v0 bare array", `[{"id":"a","title":"Legacy","priority":2,"completed":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}]`},
+		{"v1 envelope", `{"version":1,"tasks":[{"id":"a","title":"Legacy","priority":2,"completed":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "tasks.json")
+			if err := os.WriteFile(filePath, []byte(tt.data), 0644); err != nil {
+				t.Fatalf("Unexpected error writing file: %v", err)
+			}
+
+			s := NewJSONFileStorage(filePath)
+			tasks, err := s.Load(ctx)
+			if err != nil {
+				t.Fatalf("Unexpected error loading tasks: %v", err)
+			}
+			if len(tasks) != 1 || tasks[0].ID != "a" || tasks[0].Title != "Legacy" || tasks[0].Priority != task.High {
+				t.Fatalf("Expected the stored task, got %+v", tasks)
+			}
+
+			// Loading alone leaves the file as it was; the next save upgrades it
+			if data, _ := os.ReadFile(filePath); string(data) != tt.data {
+				t.Errorf("Expected load not to rewrite the file, got %s", data)
+			}
+			if err := s.Save(ctx, tasks); err != nil {
+				t.Fatalf("Unexpected error saving tasks: %v", err)
+			}
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("Unexpected error reading file: %v", err)
+			}
+			var envelope struct {
+				Version int               `json:"version"`
+				Tasks   []json.RawMessage `json:"tasks"`
+			}
+			if err := json.Unmarshal(data, &envelope); err != nil || envelope.Version != schemaVersion || len(envelope.Tasks) != 1 {
+				t.Errorf("Expected a version %d envelope with one task, got %s (%v)", schemaVersion, data, err)
+			}
+		})
+	}
+}
+
+func TestJSONFileStorageSchemaErrors(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"version":99,"tasks":[]}`, "unsupported schema version 99"},
+		{`{"version":-1,"tasks":[]}`, "unsupported schema version -1"},
+		{`{"tasks":[]}`, "missing schema version"},
+	}
+
+	for _, tt := range tests {
+		filePath := filepath.Join(t.TempDir(), "tasks.json")
+		if err := os.WriteFile(filePath, []byte(tt.data), 0644); err != nil {
+			t.Fatalf("Unexpected error writing file: %v", err)
+		}
+		_, err := NewJSONFileStorage(filePath).Load(ctx)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Loading %s: expected error containing %q, got %v", tt.data, tt.want, err)
+		}
+	}
+}
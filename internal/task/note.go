@@ -0,0 +1,49 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxNoteLength matches the description limit
+const maxNoteLength = 500
+
+// Note is a dated comment appended to a task as work progresses
+type Note struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks that the note has text within the length limit
+func (n Note) Validate() error {
+	if strings.TrimSpace(n.Text) == "" {
+		return fmt.Errorf("note text cannot be empty")
+	}
+	if len(n.Text) > maxNoteLength {
+		return fmt.Errorf("note cannot exceed %d characters", maxNoteLength)
+	}
+	return nil
+}
+
+// AddNote appends a note stamped with the current time
+func (t *Task) AddNote(text string) error {
+	now := time.Now()
+	n := Note{Text: strings.TrimSpace(text), CreatedAt: now}
+	if err := n.Validate(); err != nil {
+		return err
+	}
+	t.Notes = append(t.Notes, n)
+	t.UpdatedAt = now
+	return nil
+}
+
+// NotesNewestFirst returns a copy of the notes, most recent first. Notes
+// sharing a timestamp keep the later one first.
+func (t *Task) NotesNewestFirst() []Note {
+	notes := slices.Clone(t.Notes)
+	slices.Reverse(notes)
+	slices.SortStableFunc(notes, func(a, b Note) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return notes
+}
@@ -0,0 +1,147 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence intervals
+const (
+	Daily   = "daily"
+	Weekly  = "weekly"
+	Monthly = "monthly"
+	Yearly  = "yearly"
+)
+
+// Recurrence describes how a task repeats once completed
+type Recurrence struct {
+	Interval string `json:"interval"`
+	// Count is how many occurrences remain including this one; 0 repeats forever
+	Count int `json:"count,omitempty"`
+	// AnchorDay is the day of the month monthly and yearly series fall on,
+	// kept so a date clamped to a short month springs back afterwards
+	AnchorDay int `json:"anchor_day,omitempty"`
+}
+
+// ParseRecurrence parses "<interval>" or "<interval>:<count>", e.g. "weekly"
+// or "monthly:12"
+func ParseRecurrence(s string) (*Recurrence, error) {
+	interval, countStr, hasCount := strings.Cut(strings.ToLower(strings.TrimSpace(s)), ":")
+
+	r := &Recurrence{Interval: interval}
+	if hasCount {
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("invalid recurrence count: %q", countStr)
+		}
+		r.Count = count
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate checks that the interval is known and the count is not negative
+func (r *Recurrence) Validate() error {
+	switch r.Interval {
+	case Daily, Weekly, Monthly, Yearly:
+	default:
+		return fmt.Errorf("invalid recurrence interval: %q. Use: daily, weekly, monthly, yearly", r.Interval)
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("recurrence count cannot be negative: %d", r.Count)
+	}
+	return nil
+}
+
+// String returns the recurrence in the form ParseRecurrence accepts
+func (r *Recurrence) String() string {
+	if r.Count > 0 {
+		return fmt.Sprintf("%s:%d", r.Interval, r.Count)
+	}
+	return r.Interval
+}
+
+// Advance moves from forward by n intervals. Months and years clamp to the
+// last day of a shorter month, so Jan 31 becomes Feb 28 (or 29) rather than
+// spilling into March. Each step is measured from from, so clamping in one
+// month does not carry into the next, and a from clamped to its month's end
+// moves on from AnchorDay instead.
+func (r *Recurrence) Advance(from time.Time, n int) time.Time {
+	switch r.Interval {
+	case Daily:
+		return from.AddDate(0, 0, n)
+	case Weekly:
+		return from.AddDate(0, 0, 7*n)
+	case Monthly:
+		return addMonthsClamped(from, n, r.dayOf(from))
+	case Yearly:
+		return addMonthsClamped(from, 12*n, r.dayOf(from))
+	}
+	return time.Time{}
+}
+
+// dayOf returns the day of the month a series at from falls on: AnchorDay
+// when from is the end of a month too short for it, and from's own day
+// otherwise, such as after the task was moved to another day
+func (r *Recurrence) dayOf(from time.Time) int {
+	if day := from.Day(); r.AnchorDay <= day || day != daysIn(from) {
+		return day
+	}
+	return r.AnchorDay
+}
+
+// daysIn returns the number of days in t's month
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+// addMonthsClamped adds months to t and sets the day, clamping it to the
+// target month
+func addMonthsClamped(t time.Time, months, day int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	return first.AddDate(0, 0, min(day, daysIn(first))-1)
+}
+
+// IsRecurring reports whether completing the task should spawn another
+func (t *Task) IsRecurring() bool {
+	return t.Recurrence != nil && t.Recurrence.Count != 1
+}
+
+// NextOccurrence returns the due date of the occurrence after from, or the
+// zero time if the task does not recur
+func (t *Task) NextOccurrence(from time.Time) time.Time {
+	if !t.IsRecurring() {
+		return time.Time{}
+	}
+	return t.Recurrence.Advance(from, 1)
+}
+
+// Spawn creates the next occurrence of a recurring task with a new ID, an
+// advanced due date and one fewer remaining occurrence. It returns nil if
+// the task does not recur. Undated tasks advance from now. Monthly and
+// yearly occurrences carry the series' day of the month forward.
+func (t *Task) Spawn(now time.Time) *Task {
+	if !t.IsRecurring() {
+		return nil
+	}
+
+	from := t.DueDate
+	if from.IsZero() {
+		from = now
+	}
+
+	next := t.Clone(t.NextOccurrence(from))
+	next.Recurrence = &Recurrence{Interval: t.Recurrence.Interval}
+	if t.Recurrence.Interval == Monthly || t.Recurrence.Interval == Yearly {
+		next.Recurrence.AnchorDay = t.Recurrence.dayOf(from)
+	}
+	if t.Recurrence.Count > 0 {
+		next.Recurrence.Count = t.Recurrence.Count - 1
+	}
+	return next
+}
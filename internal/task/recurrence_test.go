@@ -0,0 +1,143 @@
+package task
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Recurrence
+		wantErr bool
+	}{
+		{"weekly", Recurrence{Interval: Weekly}, false},
+		{"Monthly:12", Recurrence{Interval: Monthly, Count: 12}, false},
+		{"daily:0", Recurrence{}, true},
+		{"hourly", Recurrence{}, true},
+		{"weekly:x", Recurrence{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRecurrence(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRecurrence(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && *got != tt.want {
+				t.Errorf("ParseRecurrence(%q) = %+v, want %+v", tt.input, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskNextOccurrence(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 9, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		interval string
+		from     time.Time
+		want     time.Time
+	}{
+		{"daily", Daily, date(2025, 2, 28), date(2025, 3, 1)},
+		{"daily into leap day", Daily, date(2024, 2, 28), date(2024, 2, 29)},
+		{"weekly", Weekly, date(2025, 12, 29), date(2026, 1, 5)},
+		{"monthly", Monthly, date(2025, 3, 15), date(2025, 4, 15)},
+		{"month end", Monthly, date(2025, 1, 31), date(2025, 2, 28)},
+		{"month end in leap year", Monthly, date(2024, 1, 31), date(2024, 2, 29)},
+		{"thirty-day month", Monthly, date(2025, 3, 31), date(2025, 4, 30)},
+		{"december", Monthly, date(2025, 12, 31), date(2026, 1, 31)},
+		{"leap day yearly", Yearly, date(2024, 2, 29), date(2025, 2, 28)},
+		{"yearly", Yearly, date(2025, 6, 1), date(2026, 6, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{Recurrence: &Recurrence{Interval: tt.interval}}
+			if got := task.NextOccurrence(tt.from); !got.Equal(tt.want) {
+				t.Errorf("NextOccurrence(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+
+	if got := (&Task{}).NextOccurrence(date(2025, 1, 1)); !got.IsZero() {
+		t.Errorf("Expected zero time for a non-recurring task, got %v", got)
+	}
+}
+
+func TestTaskSpawn(t *testing.T) {
+	due := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+	original := NewTask("Pay rent", "Monthly", High, due, []string{"home"})
+	original.Recurrence = &Recurrence{Interval: Monthly, Count: 2}
+
+	next := original.Spawn(time.Now())
+	if next == nil {
+		t.Fatal("Expected a spawned occurrence")
+	}
+	if next.ID == original.ID {
+		t.Error("Expected spawned occurrence to have a new ID")
+	}
+	if want := time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC); !next.DueDate.Equal(want) {
+		t.Errorf("Expected due %v, got %v", want, next.DueDate)
+	}
+	if next.Recurrence.Count != 1 || next.Completed {
+		t.Errorf("Unexpected spawned occurrence: %+v", next)
+	}
+
+	// The last occurrence does not spawn another
+	if last := next.Spawn(time.Now()); last != nil {
+		t.Errorf("Expected no occurrence after the last one, got %+v", last)
+	}
+}
+
+func TestTaskSpawnKeepsMonthEnd(t *testing.T) {
+	due := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+	current := NewTask("Pay rent", "", High, due, nil)
+	current.Recurrence = &Recurrence{Interval: Monthly}
+
+	// The day clamped for February springs back to the 31st and the 30th
+	for _, want := range []time.Time{
+		time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 30, 9, 0, 0, 0, time.UTC),
+	} {
+		current = current.Spawn(time.Now())
+		if !current.DueDate.Equal(want) {
+			t.Fatalf("Expected due %v, got %v", want, current.DueDate)
+		}
+	}
+
+	// A task moved to another day follows its new day
+	current.DueDate = time.Date(2025, 4, 15, 9, 0, 0, 0, time.UTC)
+	if next := current.Spawn(time.Now()); next.DueDate.Day() != 15 {
+		t.Errorf("Expected a moved task to stay on the 15th, got %v", next.DueDate)
+	}
+}
+
+func TestTaskRecurrenceJSON(t *testing.T) {
+	original := NewTask("Weekly report", "", Medium, time.Time{}, nil)
+	original.Recurrence = &Recurrence{Interval: Weekly, Count: 3}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Unexpected error marshaling task: %v", err)
+	}
+
+	var decoded Task
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unexpected error unmarshaling task: %v", err)
+	}
+	if decoded.Recurrence == nil || *decoded.Recurrence != *original.Recurrence {
+		t.Errorf("Recurrence did not round-trip: %+v", decoded.Recurrence)
+	}
+
+	plain, _ := json.Marshal(NewTask("Once", "", Low, time.Time{}, nil))
+	if strings.Contains(string(plain), "recurrence") {
+		t.Errorf("Expected non-recurring task to omit recurrence, got %s", plain)
+	}
+}
@@ -1,17 +1,26 @@
 package task
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
+	"unicode"
 )
 
 // Priority represents the priority level of a task
 type Priority int
 
+// Stored priorities are these numbers, so new levels go at the end: Urgent
+// was added after High and existing files keep their meaning.
 const (
 	Low Priority = iota
 	Medium
 	High
+	Urgent
 )
 
 // String returns the string representation of Priority
@@ -23,22 +32,55 @@ func (p Priority) String() string {
 		return "Medium"
 	case High:
 		return "High"
+	case Urgent:
+		return "Urgent"
 	default:
 		return "Unknown"
 	}
 }
 
+// ParsePriority parses a priority name or its abbreviation
+func ParsePriority(s string) (Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "low", "l":
+		return Low, nil
+	case "medium", "med", "m":
+		return Medium, nil
+	case "high", "h":
+		return High, nil
+	case "urgent", "u":
+		return Urgent, nil
+	default:
+		return Medium, fmt.Errorf("invalid priority: %s. Use: low, medium, high, urgent", s)
+	}
+}
+
 // Task represents a single todo item
 type Task struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Priority    Priority  `json:"priority"`
-	DueDate     time.Time `json:"due_date"`
-	Completed   bool      `json:"completed"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Tags        []string  `json:"tags,omitempty"`
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Priority    Priority    `json:"priority"`
+	DueDate     time.Time   `json:"due_date"`
+	Completed   bool        `json:"completed"`
+	CompletedAt time.Time   `json:"completed_at,omitzero"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Tags        []string    `json:"tags,omitempty"`
+	RelatedTo   []string    `json:"related_to,omitempty"`
+	DependsOn   []string    `json:"depends_on,omitempty"`
+	Recurrence  *Recurrence `json:"recurrence,omitempty"`
+	ParentID    string      `json:"parent_id,omitempty"`
+	// EstimateMinutes is the expected effort, zero when not estimated
+	EstimateMinutes int `json:"estimate_minutes,omitempty"`
+	// WasLate records whether the task was completed after its due date. It
+	// is fixed at completion so later due-date edits don't rewrite history.
+	WasLate bool `json:"was_late,omitempty"`
+	// StartDate hides the task from list until it becomes actionable, zero
+	// when it can be worked on right away
+	StartDate time.Time `json:"start_date,omitzero"`
+	// Notes are dated comments, in the order they were added
+	Notes []Note `json:"notes,omitempty"`
 }
 
 // NewTask creates a new task with the given parameters
@@ -57,8 +99,23 @@ func NewTask(title, description string, priority Priority, dueDate time.Time, ta
 	}
 }
 
-// Validate checks if the task has valid data
+// Clone returns a fresh pending copy of the task with a new ID and the given
+// due date. Tags, parent and estimate carry over; links, dependencies and
+// recurrence do not.
+func (t *Task) Clone(dueDate time.Time) *Task {
+	c := NewTask(t.Title, t.Description, t.Priority, dueDate, append([]string(nil), t.Tags...))
+	c.ParentID = t.ParentID
+	c.EstimateMinutes = t.EstimateMinutes
+	return c
+}
+
+// Validate checks if the task has valid data. The title is normalized first:
+// surrounding whitespace is trimmed and tabs or line breaks become spaces.
 func (t *Task) Validate() error {
+	t.Title = normalizeTitle(t.Title)
+	if err := checkTitleChars(t.Title); err != nil {
+		return err
+	}
 	if t.Title == "" {
 		return fmt.Errorf("task title cannot be empty")
 	}
@@ -68,35 +125,226 @@ func (t *Task) Validate() error {
 	if len(t.Description) > 500 {
 		return fmt.Errorf("task description cannot exceed 500 characters")
 	}
+	if t.EstimateMinutes < 0 {
+		return fmt.Errorf("task estimate cannot be negative")
+	}
+	if !t.StartDate.IsZero() && !t.DueDate.IsZero() && t.StartDate.After(t.DueDate) {
+		return fmt.Errorf("task start date cannot be after its due date")
+	}
+	if t.ParentID != "" && t.ParentID == t.ID {
+		return fmt.Errorf("task cannot be its own parent")
+	}
+	if t.Recurrence != nil {
+		if err := t.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, n := range t.Notes {
+		if err := n.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeTitle trims a title and turns tabs and line breaks into spaces
+func normalizeTitle(title string) string {
+	title = strings.ReplaceAll(title, "\r\n", "\n")
+	title = strings.Map(func(r rune) rune {
+		switch r {
+		case '\t', '\n', '\r', '\v', '\f':
+			return ' '
+		}
+		return r
+	}, title)
+	return strings.TrimSpace(title)
+}
+
+// checkTitleChars rejects control characters, which would corrupt exports
+// and terminal output
+func checkTitleChars(title string) error {
+	for _, r := range title {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("task title cannot contain control character %U", r)
+		}
+	}
 	return nil
 }
 
+// ValidateStrictTitle rejects what Validate would quietly normalize: tabs,
+// line breaks and surrounding whitespace
+func ValidateStrictTitle(title string) error {
+	switch {
+	case strings.ContainsAny(title, "\n\r"):
+		return fmt.Errorf("task title cannot contain a line break")
+	case strings.Contains(title, "\t"):
+		return fmt.Errorf("task title cannot contain a tab")
+	case strings.TrimSpace(title) != title:
+		return fmt.Errorf("task title cannot start or end with whitespace")
+	}
+	return checkTitleChars(title)
+}
+
 // Complete marks the task as completed
 func (t *Task) Complete() {
+	now := time.Now()
 	t.Completed = true
-	t.UpdatedAt = time.Now()
+	t.CompletedAt = now
+	t.WasLate = t.completedLate()
+	t.UpdatedAt = now
 }
 
 // Uncomplete marks the task as not completed
 func (t *Task) Uncomplete() {
 	t.Completed = false
+	t.CompletedAt = time.Time{}
+	t.WasLate = false
 	t.UpdatedAt = time.Now()
 }
 
-// Update updates the task with new information
-func (t *Task) Update(title, description string, priority Priority, dueDate time.Time) error {
+// completedLate reports whether CompletedAt falls after the due date
+func (t *Task) completedLate() bool {
+	return !t.DueDate.IsZero() && t.CompletedAt.After(t.DueDate)
+}
+
+// Update updates the task with new information. Tags are normalized with
+// NormalizeTags and checked with ValidateTags; nil tags leave the current
+// ones unchanged.
+func (t *Task) Update(title, description string, priority Priority, dueDate time.Time, tags []string) error {
+	if tags != nil {
+		tags = NormalizeTags(tags)
+		if err := ValidateTags(tags); err != nil {
+			return err
+		}
+	}
+
 	t.Title = title
 	t.Description = description
 	t.Priority = priority
 	t.DueDate = dueDate
+	if tags != nil {
+		t.Tags = tags
+	}
 	t.UpdatedAt = time.Now()
 
 	return t.Validate()
 }
 
+// NormalizeTags lowercases and trims tags, drops empty and duplicate ones and
+// sorts the rest, returning nil when none are left
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var out []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Limits on tags, so a stray comma-separated paste can't attach hundreds
+const (
+	maxTags      = 20
+	maxTagLength = 30
+)
+
+// ValidateTags checks the tag count and that each tag is a short word
+// without whitespace or control characters. It runs where tags come in,
+// flags, Update and imports, rather than in Validate, so tasks stored
+// before the limits existed can still be changed.
+func ValidateTags(tags []string) error {
+	if len(tags) > maxTags {
+		return fmt.Errorf("task cannot have more than %d tags", maxTags)
+	}
+	for _, tag := range tags {
+		if tag == "" {
+			return fmt.Errorf("task tag cannot be empty")
+		}
+		if len(tag) > maxTagLength {
+			return fmt.Errorf("task tag %q cannot exceed %d characters", tag, maxTagLength)
+		}
+		for _, r := range tag {
+			if unicode.IsSpace(r) || unicode.IsControl(r) {
+				return fmt.Errorf("task tag %q cannot contain whitespace or control characters", tag)
+			}
+		}
+	}
+	return nil
+}
+
+// AddRelated links the task to another task ID, reporting whether it changed
+func (t *Task) AddRelated(id string) bool {
+	return t.addRef(&t.RelatedTo, id)
+}
+
+// RemoveRelated unlinks the task from another task ID, reporting whether it changed
+func (t *Task) RemoveRelated(id string) bool {
+	return t.removeRef(&t.RelatedTo, id)
+}
+
+// AddDependency records that the task is blocked by another task ID
+func (t *Task) AddDependency(id string) bool {
+	return t.addRef(&t.DependsOn, id)
+}
+
+// RemoveDependency removes a blocker, reporting whether it changed
+func (t *Task) RemoveDependency(id string) bool {
+	return t.removeRef(&t.DependsOn, id)
+}
+
+// RemoveReferences drops every dependency and link to the given ID
+func (t *Task) RemoveReferences(id string) bool {
+	removedDep := t.RemoveDependency(id)
+	removedRel := t.RemoveRelated(id)
+	return removedDep || removedRel
+}
+
+// addRef appends id to refs unless already present
+func (t *Task) addRef(refs *[]string, id string) bool {
+	for _, existing := range *refs {
+		if existing == id {
+			return false
+		}
+	}
+	*refs = append(*refs, id)
+	t.UpdatedAt = time.Now()
+	return true
+}
+
+// removeRef removes id from refs, leaving nil when the list empties
+func (t *Task) removeRef(refs *[]string, id string) bool {
+	for i, existing := range *refs {
+		if existing == id {
+			*refs = append((*refs)[:i], (*refs)[i+1:]...)
+			if len(*refs) == 0 {
+				*refs = nil
+			}
+			t.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
 // IsOverdue checks if the task is overdue
 func (t *Task) IsOverdue() bool {
-	return !t.Completed && !t.DueDate.IsZero() && t.DueDate.Before(time.Now())
+	return t.IsOverdueAt(time.Now())
+}
+
+// IsOverdueAt checks if the task is overdue relative to now
+func (t *Task) IsOverdueAt(now time.Time) bool {
+	return !t.Completed && !t.DueDate.IsZero() && t.DueDate.Before(now)
+}
+
+// IsActionable reports whether the task can be worked on at now, which is
+// false only while its start date lies in the future
+func (t *Task) IsActionable(now time.Time) bool {
+	return !t.StartDate.After(now)
 }
 
 // IsDueToday checks if the task is due today
@@ -111,17 +359,52 @@ func (t *Task) IsDueToday() bool {
 	return today.Equal(dueDate)
 }
 
+// DefaultDueSoonWindow is the horizon IsDueSoon uses
+const DefaultDueSoonWindow = 7 * 24 * time.Hour
+
 // IsDueSoon checks if the task is due within the next 7 days
 func (t *Task) IsDueSoon() bool {
+	return t.IsDueSoonWithin(DefaultDueSoonWindow)
+}
+
+// IsDueSoonWithin checks if the task is due after now and before now+d
+func (t *Task) IsDueSoonWithin(d time.Duration) bool {
+	return t.isDueSoonAt(time.Now(), d)
+}
+
+// isDueSoonAt checks if the task is due after now and before now+d
+func (t *Task) isDueSoonAt(now time.Time, d time.Duration) bool {
 	if t.DueDate.IsZero() || t.Completed {
 		return false
 	}
 
-	sevenDaysFromNow := time.Now().Add(7 * 24 * time.Hour)
-	return t.DueDate.Before(sevenDaysFromNow) && t.DueDate.After(time.Now())
+	return t.DueDate.Before(now.Add(d)) && t.DueDate.After(now)
+}
+
+// NewID returns a fresh unique task ID
+func NewID() string {
+	return generateID()
 }
 
-// generateID generates a simple unique ID for the task
+// lastIDNanos is the timestamp of the most recent generated ID
+var lastIDNanos atomic.Int64
+
+// generateID generates a unique ID for the task: the creation time in
+// nanoseconds, bumped so it never repeats within a process, plus a random
+// suffix so IDs from separate processes don't collide either
 func generateID() string {
-	return fmt.Sprintf("task_%d", time.Now().UnixNano())
+	nanos := time.Now().UnixNano()
+	for {
+		last := lastIDNanos.Load()
+		if nanos <= last {
+			nanos = last + 1
+		}
+		if lastIDNanos.CompareAndSwap(last, nanos) {
+			break
+		}
+	}
+
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return fmt.Sprintf("task_%d_%s", nanos, hex.EncodeToString(suffix))
 }
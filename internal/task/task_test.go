@@ -1,6 +1,10 @@
 package task
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -11,7 +15,7 @@ func TestNewTask(t *testing.T) {
 	priority := High
 	dueDate := time.Now().Add(24 * time.Hour)
 
-	task := NewTask(title, description, priority, dueDate)
+	task := NewTask(title, description, priority, dueDate, nil)
 
 	if task.Title != title {
 		t.Errorf("Expected title %s, got %s", title, task.Title)
@@ -96,6 +100,83 @@ func TestTaskValidate(t *testing.T) {
 	}
 }
 
+func TestTaskValidateTags(t *testing.T) {
+	many := make([]string, 0, 21)
+	for i := 0; i < 21; i++ {
+		many = append(many, fmt.Sprintf("tag%d", i))
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		wantErr bool
+	}{
+		{name: "no tags", tags: nil},
+		{name: "at the limit", tags: many[:20]},
+		{name: "too many tags", tags: many, wantErr: true},
+		{name: "longest tag", tags: []string{strings.Repeat("a", 30)}},
+		{name: "tag too long", tags: []string{strings.Repeat("a", 31)}, wantErr: true},
+		{name: "empty tag", tags: []string{"work", ""}, wantErr: true},
+		{name: "space in tag", tags: []string{"two words"}, wantErr: true},
+		{name: "tab in tag", tags: []string{"work\t"}, wantErr: true},
+		{name: "control character", tags: []string{"wo\x00rk"}, wantErr: true},
+		{name: "punctuation", tags: []string{"q3-report", "a,b", "über"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTags(tt.tags); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Stored tasks from before the limits still validate, but new tags
+	// given to Update are checked
+	legacy := &Task{Title: "Legacy", Tags: []string{"work stuff"}}
+	if err := legacy.Validate(); err != nil {
+		t.Errorf("Expected a stored legacy tag to pass Validate, got %v", err)
+	}
+	if err := legacy.Update("Legacy", "", Medium, time.Time{}, []string{"two words"}); err == nil {
+		t.Error("Expected Update to reject a tag with a space")
+	}
+	if legacy.Tags[0] != "work stuff" {
+		t.Errorf("Expected a rejected Update to keep the tags, got %v", legacy.Tags)
+	}
+}
+
+func TestTaskValidateTitleCharacters(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		want      string
+		wantErr   bool
+		strictErr bool
+	}{
+		{name: "tab and newline", title: "Buy\tmilk\nand eggs", want: "Buy milk and eggs", strictErr: true},
+		{name: "trailing spaces", title: "Buy milk   ", want: "Buy milk", strictErr: true},
+		{name: "control character", title: "Buy\x00milk", wantErr: true, strictErr: true},
+		{name: "clean", title: "Buy milk", want: "Buy milk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateStrictTitle(tt.title); (err != nil) != tt.strictErr {
+				t.Errorf("ValidateStrictTitle() error = %v, wantErr %v", err, tt.strictErr)
+			}
+
+			task := &Task{Title: tt.title}
+			err := task.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Task.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && task.Title != tt.want {
+				t.Errorf("Expected title %q, got %q", tt.want, task.Title)
+			}
+		})
+	}
+}
+
 func TestTaskComplete(t *testing.T) {
 	task := &Task{
 		ID:        "test-id",
@@ -114,11 +195,46 @@ func TestTaskComplete(t *testing.T) {
 		t.Error("Expected task to be completed")
 	}
 
+	if task.CompletedAt.IsZero() {
+		t.Error("Expected CompletedAt to be set")
+	}
+
 	if !task.UpdatedAt.After(originalUpdatedAt) {
 		t.Error("Expected UpdatedAt to be updated")
 	}
 }
 
+func TestTaskCompleteWasLate(t *testing.T) {
+	late := &Task{ID: "late", Title: "Late", DueDate: time.Now().Add(-time.Hour)}
+	late.Complete()
+	if !late.WasLate {
+		t.Error("Expected completing after the due date to set WasLate")
+	}
+
+	// Moving the due date afterwards does not change the record
+	late.DueDate = time.Now().Add(24 * time.Hour)
+	if !late.WasLate {
+		t.Error("Expected WasLate to stay frozen after the due date changes")
+	}
+
+	onTime := &Task{ID: "on-time", Title: "On time", DueDate: time.Now().Add(time.Hour)}
+	onTime.Complete()
+	if onTime.WasLate {
+		t.Error("Expected completing before the due date to leave WasLate unset")
+	}
+
+	undated := &Task{ID: "undated", Title: "Undated"}
+	undated.Complete()
+	if undated.WasLate {
+		t.Error("Expected a task without a due date never to be late")
+	}
+
+	late.Uncomplete()
+	if late.WasLate {
+		t.Error("Expected Uncomplete to clear WasLate")
+	}
+}
+
 func TestTaskUncomplete(t *testing.T) {
 	task := &Task{
 		ID:        "test-id",
@@ -137,6 +253,10 @@ func TestTaskUncomplete(t *testing.T) {
 		t.Error("Expected task to be uncompleted")
 	}
 
+	if !task.CompletedAt.IsZero() {
+		t.Error("Expected CompletedAt to be cleared")
+	}
+
 	if !task.UpdatedAt.After(originalUpdatedAt) {
 		t.Error("Expected UpdatedAt to be updated")
 	}
@@ -163,7 +283,7 @@ func TestTaskUpdate(t *testing.T) {
 	// Small delay to ensure UpdatedAt changes
 	time.Sleep(time.Millisecond)
 
-	err := task.Update(newTitle, newDescription, newPriority, newDueDate)
+	err := task.Update(newTitle, newDescription, newPriority, newDueDate, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -193,6 +313,41 @@ func TestTaskUpdate(t *testing.T) {
 	}
 }
 
+func TestTaskUpdateTags(t *testing.T) {
+	task := NewTask("Title", "", Medium, time.Time{}, []string{"keep"})
+
+	if err := task.Update("Title", "", Medium, time.Time{}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(task.Tags, []string{"keep"}) {
+		t.Errorf("Expected nil tags to keep the existing ones, got %v", task.Tags)
+	}
+
+	if err := task.Update("Title", "", Medium, time.Time{}, []string{"Work", " home ", "work", "", "home"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(task.Tags, []string{"home", "work"}) {
+		t.Errorf("Expected normalized tags [home work], got %v", task.Tags)
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		in       []string
+		expected []string
+	}{
+		{[]string{"b", "a", "b"}, []string{"a", "b"}},
+		{[]string{" Go ", "GO", "go"}, []string{"go"}},
+		{[]string{"", "  "}, nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTags(tt.in); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("NormalizeTags(%q) = %q, want %q", tt.in, got, tt.expected)
+		}
+	}
+}
+
 func TestTaskIsOverdue(t *testing.T) {
 	now := time.Now()
 
@@ -354,6 +509,102 @@ func TestTaskIsDueSoon(t *testing.T) {
 	}
 }
 
+func TestTaskIsDueSoonWithinBoundaries(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	window := 14 * 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		due      time.Time
+		expected bool
+	}{
+		{"exactly now", now, false},
+		{"just after now", now.Add(time.Second), true},
+		{"just inside the edge", now.Add(window - time.Second), true},
+		{"exactly at the edge", now.Add(window), false},
+		{"beyond the default but inside the window", now.Add(10 * 24 * time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{DueDate: tt.due}
+			if got := task.isDueSoonAt(now, window); got != tt.expected {
+				t.Errorf("isDueSoonAt() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+
+	task := &Task{DueDate: time.Now().Add(10 * 24 * time.Hour)}
+	if task.IsDueSoon() {
+		t.Error("Expected IsDueSoon to keep the 7 day default")
+	}
+	if !task.IsDueSoonWithin(window) {
+		t.Error("Expected IsDueSoonWithin to honour a longer window")
+	}
+}
+
+func TestTaskIsActionable(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		expected bool
+	}{
+		{"no start date", time.Time{}, true},
+		{"started yesterday", now.Add(-24 * time.Hour), true},
+		{"starts exactly now", now, true},
+		{"starts in a second", now.Add(time.Second), false},
+		{"starts next week", now.AddDate(0, 0, 7), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{StartDate: tt.start}
+			if got := task.IsActionable(now); got != tt.expected {
+				t.Errorf("IsActionable() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTaskValidateStartAfterDue(t *testing.T) {
+	due := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	task := &Task{Title: "Taxes", DueDate: due, StartDate: due.Add(time.Hour)}
+	if err := task.Validate(); err == nil {
+		t.Error("Expected error for a start date after the due date")
+	}
+
+	task.StartDate = due
+	if err := task.Validate(); err != nil {
+		t.Errorf("Unexpected error for a start date on the due date: %v", err)
+	}
+}
+
+func TestTaskRelated(t *testing.T) {
+	task := &Task{Title: "Test Task"}
+
+	if !task.AddRelated("other") {
+		t.Error("Expected first AddRelated to report a change")
+	}
+	if task.AddRelated("other") {
+		t.Error("Expected duplicate AddRelated to be a no-op")
+	}
+	if len(task.RelatedTo) != 1 {
+		t.Errorf("Expected 1 related ID, got %v", task.RelatedTo)
+	}
+
+	if !task.RemoveRelated("other") {
+		t.Error("Expected RemoveRelated to report a change")
+	}
+	if task.RemoveRelated("other") {
+		t.Error("Expected second RemoveRelated to be a no-op")
+	}
+	if task.RelatedTo != nil {
+		t.Errorf("Expected related list to be cleared, got %v", task.RelatedTo)
+	}
+}
+
 func TestPriorityString(t *testing.T) {
 	tests := []struct {
 		priority Priority
@@ -362,6 +613,7 @@ func TestPriorityString(t *testing.T) {
 		{Low, "Low"},
 		{Medium, "Medium"},
 		{High, "High"},
+		{Urgent, "Urgent"},
 		{Priority(999), "Unknown"},
 	}
 
@@ -375,6 +627,35 @@ func TestPriorityString(t *testing.T) {
 	}
 }
 
+func TestParsePriorityUrgent(t *testing.T) {
+	for _, input := range []string{"urgent", "U", " Urgent "} {
+		p, err := ParsePriority(input)
+		if err != nil || p != Urgent {
+			t.Errorf("ParsePriority(%q) = %v, %v; expected Urgent", input, p, err)
+		}
+	}
+}
+
+func TestPriorityStoredValuesUnchanged(t *testing.T) {
+	// Files written before Urgent existed store High as 2; adding a level
+	// must not shift the existing numbers
+	data := `[{"id":"a","title":"Old high","priority":2},{"id":"b","title":"Old low","priority":0},{"id":"c","title":"New","priority":3}]`
+
+	var tasks []*Task
+	if err := json.Unmarshal([]byte(data), &tasks); err != nil {
+		t.Fatalf("Unexpected error unmarshaling tasks: %v", err)
+	}
+	expected := []Priority{High, Low, Urgent}
+	for i, want := range expected {
+		if tasks[i].Priority != want {
+			t.Errorf("Task %s: expected %v, got %v", tasks[i].ID, want, tasks[i].Priority)
+		}
+	}
+	if !(Urgent > High && High > Medium && Medium > Low) {
+		t.Error("Expected Urgent to rank above High")
+	}
+}
+
 // Benchmark tests
 func BenchmarkNewTask(b *testing.B) {
 	title := "Benchmark Task"
@@ -384,7 +665,7 @@ func BenchmarkNewTask(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = NewTask(title, description, priority, dueDate)
+		_ = NewTask(title, description, priority, dueDate, nil)
 	}
 }
 
@@ -413,3 +694,60 @@ func BenchmarkTaskComplete(b *testing.B) {
 		task.Uncomplete() // Reset for next iteration
 	}
 }
+
+func TestTaskAddNote(t *testing.T) {
+	task := &Task{Title: "Taxes"}
+	if err := task.AddNote("  Called the accountant  "); err != nil {
+		t.Fatalf("Unexpected error adding note: %v", err)
+	}
+	if err := task.AddNote("Sent the forms"); err != nil {
+		t.Fatalf("Unexpected error adding note: %v", err)
+	}
+
+	if len(task.Notes) != 2 {
+		t.Fatalf("Expected 2 notes, got %d", len(task.Notes))
+	}
+	if task.Notes[0].Text != "Called the accountant" || task.Notes[1].Text != "Sent the forms" {
+		t.Errorf("Expected notes appended in order and trimmed, got %+v", task.Notes)
+	}
+	if task.Notes[0].CreatedAt.IsZero() || task.UpdatedAt.IsZero() {
+		t.Error("Expected the note and task to be stamped")
+	}
+
+	if err := task.AddNote("   "); err == nil {
+		t.Error("Expected error for an empty note")
+	}
+	if err := task.AddNote(strings.Repeat("a", 501)); err == nil {
+		t.Error("Expected error for a note over 500 characters")
+	}
+	if len(task.Notes) != 2 {
+		t.Errorf("Expected rejected notes to be dropped, got %d notes", len(task.Notes))
+	}
+
+	task.Notes = append(task.Notes, Note{Text: strings.Repeat("a", 501)})
+	if err := task.Validate(); err == nil {
+		t.Error("Expected Validate to reject an overlong note")
+	}
+}
+
+func TestTaskNotesNewestFirst(t *testing.T) {
+	base := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	task := &Task{Notes: []Note{
+		{Text: "first", CreatedAt: base},
+		{Text: "third", CreatedAt: base.Add(2 * time.Hour)},
+		{Text: "second", CreatedAt: base.Add(time.Hour)},
+		{Text: "also third", CreatedAt: base.Add(2 * time.Hour)},
+	}}
+
+	var got []string
+	for _, n := range task.NotesNewestFirst() {
+		got = append(got, n.Text)
+	}
+	want := []string{"also third", "third", "second", "first"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NotesNewestFirst() = %v, expected %v", got, want)
+	}
+	if task.Notes[0].Text != "first" {
+		t.Error("Expected NotesNewestFirst to leave the task's notes untouched")
+	}
+}
@@ -4,15 +4,22 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"go-fun/internal/cli"
+	"go-fun/internal/config"
+	"go-fun/internal/filter"
 	"go-fun/internal/storage"
 	"go-fun/internal/task"
 )
@@ -27,8 +34,26 @@ var (
 	version = flag.Bool("version", false, "Show version information")
 	help    = flag.Bool("help", false, "Show help information")
 	dataDir = flag.String("data-dir", "", "Directory to store task data (default: ~/.go-fun)")
+	yes     = flag.Bool("yes", false, "Answer yes to every confirmation prompt")
+	backend = flag.String("backend", "json", "Storage backend: json, gob, or sqlite")
+	strict  = flag.Bool("strict-titles", false, "Reject titles with tabs, line breaks or surrounding whitespace")
+	compact = flag.Bool("compact", false, "Write tasks.json without indentation")
+	dueSoon = flag.Int("due-soon-days", 7, "Days ahead a task counts as due soon in stats")
+	timeout = flag.Duration("timeout", 30*time.Second, "Deadline for the command, e.g. 5s or 10m (0 disables)")
+	noColor = flag.Bool("no-color", false, "Disable ANSI colors (also disabled by NO_COLOR or when not a terminal)")
+
+	// autoSave queues writes through storage.ConcurrentStorage; queued tasks
+	// are flushed on exit, including on SIGINT and SIGTERM
+	autoSave = flag.Duration("autosave", 0, "Save writes in the background every interval, e.g. 5s (0 saves immediately)")
+
+	// configPath is resolved from the data directory at startup
+	configPath string
 )
 
+func init() {
+	flag.BoolVar(yes, "y", false, "Shorthand for -yes")
+}
+
 func main() {
 	// Parse global flags
 	flag.Parse()
@@ -47,68 +72,248 @@ func main() {
 	// Set up data directory
 	dataPath := getDataPath()
 
+	// Load user configuration
+	configPath = filepath.Join(dataPath, "config.json")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
 	// Initialize storage
-	jsonStorage := storage.NewJSONFileStorage(filepath.Join(dataPath, "tasks.json"))
+	store, err := openStorage(dataPath, *backend)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if js, ok := store.(*storage.JSONFileStorage); ok {
+		js.Compact = *compact
+	}
+	shutdown := storageShutdown(store)
+	if *autoSave > 0 {
+		autoSaver := storage.NewAutoSavingStorage(store, *autoSave)
+		closeStore := shutdown
+		shutdown = sync.OnceValue(func() error {
+			err := autoSaver.DisableAutoSave()
+			if closeErr := closeStore(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return fmt.Errorf("failed to save queued tasks: %w", err)
+			}
+			return nil
+		})
+		store = autoSaver
+	}
 
 	// Create task manager
-	taskManager := cli.NewTaskManager(jsonStorage)
-
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	taskManager := cli.NewTaskManager(store)
+	taskManager.SetConfig(cfg)
+	taskManager.SetColor(cli.ColorEnabled(isTerminal(os.Stdout), *noColor))
+	taskManager.SetAssumeYes(*yes)
+	taskManager.SetStrictTitles(*strict || cfg.StrictTitles)
+	taskManager.SetDueSoonWindow(time.Duration(*dueSoon) * 24 * time.Hour)
+	taskManager.SetUndoLog(filepath.Join(dataPath, "undo.jsonl"))
 
 	// Execute command
 	command := args[0]
 	commandArgs := args[1:]
 
-	if err := executeCommand(ctx, taskManager, command, commandArgs); err != nil {
+	// Create context with timeout
+	ctx, cancel := commandContext(context.Background(), command)
+	defer cancel()
+	go handleSignals(cancel, shutdown)
+
+	// log.Fatalf skips deferred calls, so flush before reporting the error
+	err = executeCommand(ctx, taskManager, cfg, command, commandArgs)
+	if shutdownErr := shutdown(); err == nil {
+		err = shutdownErr
+	}
+	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
 }
 
-func executeCommand(ctx context.Context, tm *cli.TaskManager, command string, args []string) error {
+// storageShutdown returns a function closing the store if it holds
+// resources; it is safe to call more than once
+func storageShutdown(store storage.Storage) func() error {
+	closer, ok := store.(io.Closer)
+	if !ok {
+		return func() error { return nil }
+	}
+	return sync.OnceValue(closer.Close)
+}
+
+// shutdownGrace is how long a command has to stop after SIGINT or SIGTERM
+// before queued writes are flushed and the process exits regardless
+const shutdownGrace = 10 * time.Second
+
+// handleSignals cancels the command context on SIGINT or SIGTERM. Commands
+// that watch the context return and main flushes as usual; if the command
+// is still running after shutdownGrace, or a second signal arrives, queued
+// writes are flushed here and the process exits.
+func handleSignals(cancel context.CancelFunc, shutdown func() error) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	<-signals
+	cancel()
+	select {
+	case <-signals:
+	case <-time.After(shutdownGrace):
+	}
+	if err := shutdown(); err != nil {
+		log.Printf("Error: %v", err)
+	}
+	os.Exit(1)
+}
+
+// backendFiles maps each -backend choice to its file in the data directory
+var backendFiles = map[string]string{
+	"json":   "tasks.json",
+	"gob":    "tasks.gob",
+	"sqlite": "tasks.db",
+}
+
+// openStorage opens the selected backend inside the data directory
+func openStorage(dataPath, backend string) (storage.Storage, error) {
+	file, ok := backendFiles[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown backend: %q. Use: json, gob, sqlite", backend)
+	}
+	return storage.Open(backend + ":" + filepath.Join(dataPath, file))
+}
+
+// longRunningCommands serve until stopped, so the default timeout does not
+// apply to them unless -timeout is given explicitly
+var longRunningCommands = map[string]bool{
+	"interactive": true,
+	"rpc":         true,
+	"serve":       true,
+	"shell":       true,
+	"watch":       true,
+}
+
+// commandContext returns the context a command runs under, derived from
+// parent and honoring -timeout
+func commandContext(parent context.Context, command string) (context.Context, context.CancelFunc) {
+	explicit := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "timeout" {
+			explicit = true
+		}
+	})
+
+	if *timeout <= 0 || (longRunningCommands[command] && !explicit) {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, *timeout)
+}
+
+func executeCommand(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, command string, args []string) error {
 	switch command {
 	case "add":
-		return handleAdd(ctx, tm, args)
+		return handleAdd(ctx, tm, cfg, args)
 	case "list", "ls":
-		return handleList(ctx, tm, args)
+		return handleList(ctx, tm, cfg, args)
 	case "complete", "done":
-		return handleComplete(ctx, tm, args)
-	case "uncomplete", "undo":
+		return handleComplete(ctx, tm, cfg, args)
+	case "complete-by":
+		return handleCompleteBy(ctx, tm, cfg, args)
+	case "undo":
+		return handleUndo(ctx, tm, args)
+	case "uncomplete":
 		return handleUncomplete(ctx, tm, args)
 	case "delete", "rm":
-		return handleDelete(ctx, tm, args)
-	case "update", "edit":
-		return handleUpdate(ctx, tm, args)
+		return handleDelete(ctx, tm, cfg, args)
+	case "rollover":
+		return handleRollover(ctx, tm, args)
+	case "dedupe":
+		return handleDedupe(ctx, tm, args)
+	case "plan-today":
+		return handlePlanToday(ctx, tm, args)
+	case "reschedule-overdue":
+		return handleRescheduleOverdue(ctx, tm, args)
+	case "set-due":
+		return handleSetDue(ctx, tm, args)
+	case "priority":
+		return handlePriority(ctx, tm, cfg, args)
+	case "due":
+		return handleDue(ctx, tm, args)
+	case "purge":
+		return handlePurge(ctx, tm, args)
+	case "backup":
+		return handleBackup(ctx, tm, args)
+	case "restore":
+		return handleRestore(ctx, tm, args)
+	case "update":
+		return handleUpdate(ctx, tm, cfg, args)
+	case "edit":
+		return handleEdit(ctx, tm, cfg, args)
+	case "depend":
+		return handleDepend(ctx, tm, args)
+	case "deps":
+		return handleDeps(ctx, tm, args)
+	case "move-to":
+		return handleMoveTo(ctx, tm, args)
+	case "repeat":
+		return handleRepeat(ctx, tm, args)
+	case "storage-migrate":
+		return handleStorageMigrate(ctx, tm, args)
+	case "link":
+		return handleLink(ctx, tm, args)
+	case "unlink":
+		return handleUnlink(ctx, tm, args)
 	case "show", "get":
 		return handleShow(ctx, tm, args)
+	case "note":
+		return handleNote(ctx, tm, args)
+	case "overdue":
+		return handleOverdue(ctx, tm, args)
+	case "remind":
+		return handleRemind(ctx, tm, args)
+	case "histogram":
+		return handleHistogram(ctx, tm, args)
+	case "random":
+		return handleRandom(ctx, tm, cfg, args)
+	case "extremes":
+		return handleExtremes(ctx, tm, args)
+	case "workload":
+		return handleWorkload(ctx, tm, cfg, args)
 	case "stats":
 		return handleStats(ctx, tm, args)
+	case "tags":
+		return handleTags(ctx, tm, args)
+	case "tag":
+		return handleTag(ctx, tm, args)
+	case "import":
+		return handleImport(ctx, tm, args)
+	case "changes":
+		return handleChanges(ctx, tm, args)
 	case "export":
 		return handleExport(ctx, tm, args)
 	case "export-all":
 		return handleExportAll(ctx, tm, args)
+	case "export-by-tag":
+		return handleExportByTag(ctx, tm, args)
+	case "rpc":
+		return handleRPC(ctx, tm, args)
+	case "shell", "interactive":
+		return handleShell(ctx, tm, cfg, args)
+	case "serve":
+		return handleServe(ctx, tm, args)
 	case "watch":
-		return handleWatch(ctx, tm, args)
+		return handleWatch(ctx, tm, cfg, args)
+	case "relabel":
+		return handleRelabel(tm, cfg, args)
+	case "view":
+		return handleView(ctx, tm, cfg, args)
+	case "workspace":
+		return handleWorkspace(tm, args)
 	default:
 		return fmt.Errorf("unknown command: %s. Use 'go-fun -help' for usage", command)
 	}
 }
 
-func normalizeTags(in []string) []string {
-	set := make(map[string]struct{}, len(in))
-	for _, v := range in {
-		set[v] = struct{}{}
-	}
-	out := make([]string, 0, len(set))
-	for v := range set {
-		out = append(out, v)
-	}
-	sort.Strings(out)
-	return out
-}
-
-func handleAdd(ctx context.Context, tm *cli.TaskManager, args []string) error {
+func handleAdd(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
 	flagSet := flag.NewFlagSet("add", flag.ContinueOnError)
 
 	title := ""
@@ -133,7 +338,7 @@ func handleAdd(ctx context.Context, tm *cli.TaskManager, args []string) error {
 	flagSet.StringVar(&dueDateStr, "D", dueDateStr, duedateDesc)
 	flagSet.StringVar(&dueDateStr, "duedate", dueDateStr, duedateDesc)
 
-	priorityDesc := "Priority for the task (l, m, h)"
+	priorityDesc := "Priority for the task (l, m, h, u)"
 	flagSet.StringVar(&priorityStr, "p", priorityStr, priorityDesc)
 	flagSet.StringVar(&priorityStr, "priority", priorityStr, priorityDesc)
 
@@ -141,10 +346,34 @@ func handleAdd(ctx context.Context, tm *cli.TaskManager, args []string) error {
 	flagSet.Var(&tags, "T", tagDesc)
 	flagSet.Var(&tags, "tag", tagDesc)
 
+	recurStr := ""
+	recurDesc := "Repeat when completed: daily, weekly, monthly, yearly, optionally :count"
+	flagSet.StringVar(&recurStr, "r", recurStr, recurDesc)
+	flagSet.StringVar(&recurStr, "recur", recurStr, recurDesc)
+
+	parentID := flagSet.String("parent", "", "Make the task a subtask of this task ID")
+
+	estimateStr := flagSet.String("estimate", "", "Expected effort, in minutes or as a duration like 1h30m")
+
+	startStr := flagSet.String("start", "", "Hide the task from list until this date")
+
+	tagFromBranch := flagSet.Bool("tag-from-branch", cfg.TagFromBranch, "Tag the task with the current git branch")
+
+	inputPath := flagSet.String("input", "", "Read a JSON array of tasks from a file (- for stdin)")
+	upsert := flagSet.Bool("upsert", false, "With --input, update tasks whose ID already exists")
+
 	if err := flagSet.Parse(args); err != nil {
 		return err
 	}
 
+	// --input --upsert
+	if *inputPath != "" {
+		return addFromInput(ctx, tm, *inputPath, *upsert)
+	}
+	if *upsert {
+		return fmt.Errorf("--upsert requires --input")
+	}
+
 	// -t --title
 	if title == "" {
 		return fmt.Errorf("title is required")
@@ -155,16 +384,11 @@ func handleAdd(ctx context.Context, tm *cli.TaskManager, args []string) error {
 	}
 	// -p --priority
 	if priorityStr != "" {
-		switch strings.ToLower(priorityStr) {
-		case "low", "l":
-			priority = task.Low
-		case "medium", "med", "m":
-			priority = task.Medium
-		case "high", "h":
-			priority = task.High
-		default:
-			return fmt.Errorf("invalid priority: %s. Use: low, medium, high", priorityStr)
+		parsed, err := cfg.ParsePriority(priorityStr)
+		if err != nil {
+			return err
 		}
+		priority = parsed
 	}
 	// -D --duedate
 	if dueDateStr != "" {
@@ -175,286 +399,1579 @@ func handleAdd(ctx context.Context, tm *cli.TaskManager, args []string) error {
 		dueDate = parsedDate
 	}
 
+	// --tag-from-branch
+	if *tagFromBranch {
+		if branch := tm.BranchTag(ctx); branch != "" {
+			tags = append(tags, branch)
+		}
+	}
+
 	// -T --tag
-	normalizedTags := normalizeTags(tags)
+	newTask := task.NewTask(title, description, priority, dueDate, task.NormalizeTags(tags))
+	newTask.ParentID = *parentID
+
+	// --estimate
+	if *estimateStr != "" {
+		estimate, err := cli.ParseEstimate(*estimateStr)
+		if err != nil {
+			return err
+		}
+		newTask.EstimateMinutes = estimate
+	}
+
+	// --start
+	if *startStr != "" {
+		start, err := parseStart(*startStr)
+		if err != nil {
+			return err
+		}
+		newTask.StartDate = start
+	}
+
+	// -r --recur
+	if recurStr != "" {
+		recurrence, err := task.ParseRecurrence(recurStr)
+		if err != nil {
+			return err
+		}
+		newTask.Recurrence = recurrence
+	}
 
-	return tm.Add(ctx, title, description, priority, dueDate, normalizedTags)
+	return tm.AddTask(ctx, newTask)
 }
 
-func handleList(ctx context.Context, tm *cli.TaskManager, args []string) error {
-	var filterPriority *task.Priority
-	searchTerm := ""
-	showCompleted := false
-	showDue := ""
+func addFromInput(ctx context.Context, tm *cli.TaskManager, path string, upsert bool) error {
+	if path == "-" {
+		return tm.AddFromJSON(ctx, os.Stdin, upsert)
+	}
 
-	// Parse flags
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open input: %w", err)
+	}
+	defer file.Close()
+
+	return tm.AddFromJSON(ctx, file, upsert)
+}
+
+func handleList(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	opts, err := parseListOptions(cfg, withoutFlags(args, nil, []string{"-o", "--output"}))
+	if err != nil {
+		return err
+	}
 	for i, arg := range args {
+		if (arg == "-o" || arg == "--output") && i+1 < len(args) {
+			if err := tm.SetOutputFormat(args[i+1]); err != nil {
+				return err
+			}
+		}
+	}
+	return tm.List(ctx, opts)
+}
+
+// parseListOptions reads the list filters shared by list, watch and the
+// bulk commands. Unknown arguments and flags missing their value are
+// errors, so a typo never widens a filter; callers with flags of their own
+// strip them first with withoutFlags.
+func parseListOptions(cfg *config.Config, args []string) (cli.ListOptions, error) {
+	var opts cli.ListOptions
+	var minPriority, maxPriority string
+	dueFlags := filter.DueFlags{SoonDays: *dueSoon}
+
+	// Parse flags
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// value returns the argument after a flag that takes one
+		value := func() (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("flag needs a value: %s", arg)
+			}
+			i++
+			return args[i], nil
+		}
+
 		switch arg {
 		case "-c", "--completed":
-			showCompleted = true
+			opts.ShowCompleted = true
 		case "-d", "--due":
-			if i+1 < len(args) {
-				showDue = args[i+1]
+			v, err := value()
+			if err != nil {
+				return opts, err
 			}
+			opts.Due = v
+			dueFlags.Due = v
+		case "--overdue":
+			dueFlags.Overdue = true
+		case "--today":
+			dueFlags.Today = true
+		case "--due-soon":
+			dueFlags.DueSoon = true
 		case "-p", "--priority":
-			if i+1 < len(args) {
-				switch strings.ToLower(args[i+1]) {
-				case "low", "l":
-					p := task.Low
-					filterPriority = &p
-				case "medium", "med", "m":
-					p := task.Medium
-					filterPriority = &p
-				case "high", "h":
-					p := task.High
-					filterPriority = &p
-				}
+			v, err := value()
+			if err != nil {
+				return opts, err
+			}
+			f, err := filter.ParsePriorityFilter(v, cfg.ParsePriority)
+			if err != nil {
+				return opts, err
+			}
+			opts.Priority = &f
+		case "-s", "--search", "--sort", "--weekday", "--min-priority", "--max-priority", "-T", "--tag":
+			v, err := value()
+			if err != nil {
+				return opts, err
+			}
+			switch arg {
+			case "-s", "--search":
+				opts.Search = v
+			case "--sort":
+				opts.Sort = v
+			case "--weekday":
+				opts.Weekday = v
+			case "--min-priority":
+				minPriority = v
+			case "--max-priority":
+				maxPriority = v
+			default:
+				opts.Tag = v
+			}
+		case "--tags":
+			opts.SearchTags = true
+		case "--regex":
+			opts.Regex = true
+		case "--only-ids":
+			opts.OnlyIDs = true
+		case "--tree":
+			opts.Tree = true
+		case "--explain":
+			opts.Explain = true
+		case "--reverse":
+			opts.Reverse = true
+		case "--limit", "--offset":
+			v, err := value()
+			if err != nil {
+				return opts, err
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return opts, fmt.Errorf("invalid %s: %s", arg, v)
 			}
-		case "-s", "--search":
-			if i+1 < len(args) {
-				searchTerm = args[i+1]
+			if arg == "--limit" {
+				opts.Limit = n
+			} else if n < 0 {
+				return opts, fmt.Errorf("--offset cannot be negative: %d", n)
+			} else {
+				opts.Offset = n
 			}
+		case "--all", "--scheduled":
+			opts.Scheduled = true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return opts, fmt.Errorf("unknown flag: %s", arg)
+			}
+			return opts, fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	if minPriority != "" || maxPriority != "" {
+		r, err := filter.ParsePriorityRangeFilter(minPriority, maxPriority, cfg.ParsePriority)
+		if err != nil {
+			return opts, err
 		}
+		opts.PriorityRange = &r
+	}
+
+	f, err := dueFlags.Filter()
+	if err != nil {
+		return opts, err
 	}
+	opts.DueFilter = f
 
-	return tm.List(ctx, showCompleted, filterPriority, searchTerm, showDue)
+	return opts, nil
 }
 
-func handleComplete(ctx context.Context, tm *cli.TaskManager, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: complete <task-id>")
+// withoutFlags returns args without the given boolean flags and without the
+// value flags and the argument after each, for commands that read those
+// flags themselves before passing the rest to parseListOptions
+func withoutFlags(args, bools, values []string) []string {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case slices.Contains(bools, args[i]):
+		case slices.Contains(values, args[i]):
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest
+}
+
+func handleComplete(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	// --all completes without asking; --due or --tag alone confirm first
+	all := slices.Contains(args, "--all")
+	filtered := slices.ContainsFunc(args, func(arg string) bool {
+		return slices.Contains([]string{"-d", "--due", "-T", "--tag"}, arg)
+	})
+	if all || filtered {
+		opts, err := parseListOptions(cfg, withoutFlags(args, []string{"--all", "--require-subtasks"}, nil))
+		if err != nil {
+			return err
+		}
+		tm.SetRequireSubtasks(cfg.RequireSubtasks || slices.Contains(args, "--require-subtasks"))
+		_, err = tm.CompleteMatching(ctx, opts, !all)
+		return err
+	}
+
+	flagSet := flag.NewFlagSet("complete", flag.ContinueOnError)
+	requireSubtasks := flagSet.Bool("require-subtasks", cfg.RequireSubtasks, "Refuse to complete a task with open subtasks")
+	autoCompleteParent := flagSet.Bool("auto-complete-parent", cfg.AutoCompleteParent, "Complete the parent once its last subtask is done")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: complete [--require-subtasks] [--auto-complete-parent] <task-id> | --all [list filters] | --due <filter> [--tag <tag>]")
 	}
 
-	return tm.Complete(ctx, args[0])
+	tm.SetRequireSubtasks(*requireSubtasks)
+	tm.SetAutoCompleteParent(*autoCompleteParent)
+	return tm.Complete(ctx, positional[0])
 }
 
-func handleUncomplete(ctx context.Context, tm *cli.TaskManager, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: uncomplete <task-id>")
+func handleCompleteBy(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: complete-by <title search>")
 	}
 
-	return tm.Uncomplete(ctx, args[0])
+	tm.SetRequireSubtasks(cfg.RequireSubtasks)
+	tm.SetAutoCompleteParent(cfg.AutoCompleteParent)
+	return tm.CompleteBy(ctx, strings.Join(args, " "))
 }
 
-func handleDelete(ctx context.Context, tm *cli.TaskManager, args []string) error {
+func handleUncomplete(ctx context.Context, tm *cli.TaskManager, args []string) error {
 	if len(args) != 1 {
-		return fmt.Errorf("usage: delete <task-id>")
+		return fmt.Errorf("usage: uncomplete <task-id>")
 	}
 
-	return tm.Delete(ctx, args[0])
+	return tm.Uncomplete(ctx, args[0])
 }
 
-func handleUpdate(ctx context.Context, tm *cli.TaskManager, args []string) error {
-	if len(args) < 2 {
-		return fmt.Errorf("usage: update <task-id> <title> [description] [priority] [due-date]")
+func handleDelete(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	if slices.Contains(args, "--all") {
+		opts, err := parseListOptions(cfg, withoutFlags(args, []string{"--all", "--yes", "--cascade", "--force"}, nil))
+		if err != nil {
+			return err
+		}
+		if !*yes && !slices.Contains(args, "--yes") {
+			return fmt.Errorf("bulk delete needs --yes to confirm; preview the matches with list and the same filters")
+		}
+		_, err = tm.DeleteMatching(ctx, opts, slices.Contains(args, "--cascade"), slices.Contains(args, "--force"))
+		return err
 	}
 
-	id := args[0]
-	title := args[1]
-	description := ""
-	priority := task.Medium
-	dueDate := time.Time{}
+	flagSet := flag.NewFlagSet("delete", flag.ContinueOnError)
+	cascade := flagSet.Bool("cascade", false, "Also delete the task's subtasks")
 
-	if len(args) > 2 {
-		description = args[2]
-	}
-	if len(args) > 3 {
-		switch strings.ToLower(args[3]) {
-		case "low", "l":
-			priority = task.Low
-		case "medium", "med", "m":
-			priority = task.Medium
-		case "high", "h":
-			priority = task.High
-		default:
-			return fmt.Errorf("invalid priority: %s. Use: low, medium, high", args[3])
-		}
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: delete [--cascade] <task-id> | --all --yes [--cascade] [--force] [list filters]")
 	}
-	if len(args) > 4 {
-		parsedDate, err := parseDate(args[4])
+	id := positional[0]
+
+	if *cascade {
+		id, err = tm.ResolveID(ctx, id)
 		if err != nil {
-			return fmt.Errorf("invalid date format: %w", err)
+			return err
 		}
-		dueDate = parsedDate
+		if err := tm.ConfirmOrAbort(fmt.Sprintf("Delete task %s and all of its subtasks?", id)); err != nil {
+			return err
+		}
+		return tm.DeleteCascade(ctx, id)
+	}
+	return tm.Delete(ctx, id)
+}
+
+func handleSetDue(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("set-due", flag.ContinueOnError)
+	relativeTo := flagSet.String("relative-to", "created", "Base timestamp (created, updated, now)")
+	where := flagSet.String("where", "no-due", "Which pending tasks to change (no-due, all)")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: set-due [--relative-to created|updated|now] [--where no-due|all] <offset>")
+	}
+
+	offset, err := cli.ParseOffset(positional[0])
+	if err != nil {
+		return err
 	}
 
-	return tm.Update(ctx, id, title, description, priority, dueDate)
+	_, err = tm.SetDueRelative(ctx, *relativeTo, offset, *where)
+	return err
 }
 
-func handleShow(ctx context.Context, tm *cli.TaskManager, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: show <task-id>")
+func handleUndo(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: undo")
+	}
+	_, err := tm.Undo(ctx)
+	return err
+}
+
+func handleDedupe(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("dedupe", flag.ContinueOnError)
+	auto := flagSet.Bool("auto", false, "Keep the oldest task of each group without asking")
+
+	if _, err := parseFlags(flagSet, args); err != nil {
+		return err
 	}
 
-	return tm.Show(ctx, args[0])
+	_, err := tm.Dedupe(ctx, *auto)
+	return err
 }
 
-func handleStats(ctx context.Context, tm *cli.TaskManager, args []string) error {
-	return tm.Stats(ctx)
+func handlePlanToday(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("plan-today", flag.ContinueOnError)
+	allOverdue := flagSet.Bool("all-overdue", false, "Also select every overdue task")
+
+	ids, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 && !*allOverdue {
+		return fmt.Errorf("usage: plan-today [--all-overdue] [task-id...]")
+	}
+
+	_, err = tm.PlanToday(ctx, ids, *allOverdue)
+	return err
 }
 
-func handleExport(ctx context.Context, tm *cli.TaskManager, args []string) error {
-	if len(args) < 2 {
-		return fmt.Errorf("usage: export <format> <filename>")
+func handleRescheduleOverdue(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("reschedule-overdue", flag.ContinueOnError)
+	perDay := flagSet.Int("per-day", 5, "Maximum number of tasks moved to each day")
+	starting := flagSet.String("starting", "tomorrow", "First day to move tasks to")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("usage: reschedule-overdue [--per-day N] [--starting date]")
 	}
 
-	format := args[0]
-	filename := args[1]
+	start, err := parseDate(*starting)
+	if err != nil {
+		return err
+	}
 
-	return tm.ExportTasks(ctx, format, filename)
+	_, err = tm.RescheduleOverdue(ctx, start, *perDay)
+	return err
 }
 
-func handleExportAll(ctx context.Context, tm *cli.TaskManager, args []string) error {
-	if len(args) < 2 {
-		return fmt.Errorf("usage: export-all <formats> <base-filename>")
+func handleBackup(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: backup <directory>")
 	}
 
-	// Parse formats (comma-separated)
-	formatsStr := args[0]
-	baseFilename := args[1]
+	_, err := tm.Backup(ctx, args[0])
+	return err
+}
 
-	formats := strings.Split(formatsStr, ",")
-	for i, format := range formats {
-		formats[i] = strings.TrimSpace(format)
+func handleRestore(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: restore <backup-file>")
+	}
+
+	if err := tm.ConfirmOrAbort("Replace all tasks with " + args[0] + "?"); err != nil {
+		return err
 	}
 
-	fmt.Printf("🚀 Starting concurrent export to %d formats...\n", len(formats))
-	return tm.ConcurrentExport(ctx, formats, baseFilename)
+	_, err := tm.Restore(ctx, args[0])
+	return err
 }
 
-func handleWatch(ctx context.Context, tm *cli.TaskManager, args []string) error {
-	// todo: playground area...
-	return nil
+func handlePurge(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("purge", flag.ContinueOnError)
+	force := flagSet.Bool("force", false, "Skip the store sanity check")
+
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	if err := tm.ConfirmOrAbort("Permanently remove all completed tasks?"); err != nil {
+		return err
+	}
+
+	_, err := tm.Purge(ctx, *force)
+	return err
 }
 
-func parseDate(dateStr string) (time.Time, error) {
-	// Handle special cases first
-	switch strings.ToLower(dateStr) {
-	case "today":
-		return time.Now().Truncate(24 * time.Hour), nil
-	case "tomorrow":
-		return time.Now().Add(24 * time.Hour).Truncate(24 * time.Hour), nil
+func handleRollover(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("rollover", flag.ContinueOnError)
+	dryRun := flagSet.Bool("dry-run", false, "Preview which tasks would move")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
 	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: rollover [--dry-run] <archive.json>")
+	}
+
+	_, err = tm.Rollover(ctx, positional[0], *dryRun)
+	return err
+}
+
+func handleUpdate(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	flagSet := flag.NewFlagSet("update", flag.ContinueOnError)
+
+	// The same flags as add; positional fields are still accepted after the ID
+	titleDesc := "New title"
+	title := flagSet.String("t", "", titleDesc)
+	flagSet.StringVar(title, "title", "", titleDesc)
+
+	descDesc := "New description"
+	description := flagSet.String("d", "", descDesc)
+	flagSet.StringVar(description, "desc", "", descDesc)
+	flagSet.StringVar(description, "description", "", descDesc)
 
-	// Try different date formats
-	formats := []string{
-		"2006-01-02",
-		"2006-01-02 15:04",
-		"2006-01-02 15:04:05",
-		"01/02/2006",
-		"01/02/2006 15:04",
+	priorityDesc := "New priority (l, m, h, u)"
+	priorityStr := flagSet.String("p", "", priorityDesc)
+	flagSet.StringVar(priorityStr, "priority", "", priorityDesc)
+
+	dueDesc := "New due date (none to clear)"
+	dueStr := flagSet.String("D", "", dueDesc)
+	flagSet.StringVar(dueStr, "duedate", "", dueDesc)
+
+	var tags cli.TagList
+	tagDesc := "Replace the tags (comma-separated or repeated)"
+	flagSet.Var(&tags, "T", tagDesc)
+	flagSet.Var(&tags, "tag", tagDesc)
+	startStr := flagSet.String("start", "", "Set the start date (none to clear)")
+
+	args, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	usage := fmt.Errorf("usage: update [-t title] [-d description] [-p priority] [-D due-date] [-T tags] [--start date] <task-id> [title] [description] [priority] [due-date]")
+	if len(args) < 1 || len(args) > 5 {
+		return usage
 	}
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
-			return t, nil
+	// Only the fields given, by flag or position, are changed
+	shortNames := map[string]string{"title": "t", "desc": "d", "description": "d", "priority": "p", "duedate": "D"}
+	set := make(map[string]bool)
+	flagSet.Visit(func(f *flag.Flag) {
+		if short, ok := shortNames[f.Name]; ok {
+			set[short] = true
+		}
+		set[f.Name] = true
+	})
+	for i, field := range []struct {
+		name  string
+		value *string
+	}{{"t", title}, {"d", description}, {"p", priorityStr}, {"D", dueStr}} {
+		if len(args) > i+1 {
+			*field.value = args[i+1]
+			set[field.name] = true
 		}
 	}
 
-	// Try parsing as duration (e.g., "1d", "2h", "30m")
-	// Handle "d" suffix for days
-	if strings.HasSuffix(dateStr, "d") {
-		if days, err := strconv.Atoi(strings.TrimSuffix(dateStr, "d")); err == nil {
-			return time.Now().Add(time.Duration(days) * 24 * time.Hour), nil
+	id := args[0]
+	var update cli.TaskUpdate
+	if set["t"] {
+		update.Title = title
+	}
+	if set["d"] {
+		update.Description = description
+	}
+	if set["p"] {
+		parsed, err := cfg.ParsePriority(*priorityStr)
+		if err != nil {
+			return err
 		}
+		update.Priority = &parsed
 	}
-
-	// Try standard duration parsing
-	if duration, err := time.ParseDuration(dateStr); err == nil {
-		return time.Now().Add(duration), nil
+	if set["D"] {
+		var due time.Time
+		if !strings.EqualFold(*dueStr, "none") {
+			if due, err = parseDate(*dueStr); err != nil {
+				return fmt.Errorf("invalid date format: %w", err)
+			}
+		}
+		update.DueDate = &due
 	}
+	// Without -T the tags stay as they are
+	update.Tags = tags
 
-	// Try parsing as days from now (e.g., "3" means 3 days from now)
-	if days, err := strconv.Atoi(dateStr); err == nil {
-		return time.Now().Add(time.Duration(days) * 24 * time.Hour), nil
+	if *startStr != "" {
+		start, err := parseStart(*startStr)
+		if err != nil {
+			return err
+		}
+		update.StartDate = &start
 	}
 
-	return time.Time{}, fmt.Errorf("unable to parse date: %s", dateStr)
+	if !set["t"] && !set["d"] && !set["p"] && !set["D"] && tags == nil && update.StartDate == nil {
+		return usage
+	}
+	return tm.UpdateFields(ctx, id, update)
 }
 
-func getDataPath() string {
-	if *dataDir != "" {
-		return *dataDir
+func handlePriority(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: priority <task-id> <level>")
 	}
 
-	homeDir, err := os.UserHomeDir()
+	priority, err := cfg.ParsePriority(args[1])
 	if err != nil {
-		log.Fatalf("Failed to get home directory: %v", err)
+		return err
 	}
+	return tm.SetPriority(ctx, args[0], priority)
+}
 
-	dataPath := filepath.Join(homeDir, ".go-fun")
-	if err := os.MkdirAll(dataPath, 0755); err != nil {
-		log.Fatalf("Failed to create data directory: %v", err)
+func handleDue(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: due <task-id> <date|none>")
 	}
 
-	return dataPath
+	var due time.Time
+	if !strings.EqualFold(args[1], "none") {
+		parsed, err := parseDate(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid date format: %w", err)
+		}
+		due = parsed
+	}
+	return tm.SetDueDate(ctx, args[0], due)
 }
 
-func showVersion() {
-	fmt.Printf("%s version %s\n", appName, appVersion)
-	fmt.Printf("Description: %s\n", appDescription)
+// parseStart parses a --start value, where "none" clears the start date
+func parseStart(s string) (time.Time, error) {
+	if strings.EqualFold(s, "none") {
+		return time.Time{}, nil
+	}
+	start, err := parseDate(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start date: %w", err)
+	}
+	return start, nil
 }
 
-func showHelp() {
-	fmt.Printf("%s - %s\n\n", appName, appDescription)
-	fmt.Println("Usage:")
-	fmt.Printf("  %s [global-flags] <command> [command-flags] [arguments]\n\n", appName)
+func handleEdit(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	flagSet := flag.NewFlagSet("edit", flag.ContinueOnError)
+	preview := flagSet.Bool("preview", false, "Show the changes and ask before saving")
 
-	fmt.Println("Global Flags:")
-	fmt.Println("  -version     Show version information")
-	fmt.Println("  -help        Show this help message")
-	fmt.Println("  -data-dir    Directory to store task data (default: ~/.go-fun)")
-	fmt.Println()
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+
+	// edit <task-id> <title> ... keeps working as an alias of update
+	if len(positional) > 1 && !*preview {
+		return handleUpdate(ctx, tm, cfg, positional)
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: edit [--preview] <task-id>")
+	}
+
+	return tm.Edit(ctx, positional[0], runEditor, *preview)
+}
+
+// runEditor opens path in $EDITOR (default vi) attached to the terminal
+func runEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// $EDITOR may carry arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func handleDepend(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: depend <task-id> <blocker-id>")
+	}
+
+	return tm.AddDependency(ctx, args[0], args[1])
+}
+
+func handleDeps(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("deps", flag.ContinueOnError)
+	check := flagSet.Bool("check", false, "Report dangling dependencies and cycles")
+	fix := flagSet.Bool("fix", false, "Remove dangling dependencies and break cycles")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 || *check == *fix {
+		return fmt.Errorf("usage: deps --check | --fix")
+	}
+
+	problems, err := tm.Deps(ctx, *fix)
+	if err != nil {
+		return err
+	}
+	// A non-zero exit lets CI fail on a broken store
+	if *check && len(problems) > 0 {
+		return fmt.Errorf("found %d dependency problems; run deps --fix to repair", len(problems))
+	}
+	return nil
+}
+
+func handleMoveTo(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: move-to <dsn> <task-id>")
+	}
+
+	dest, err := storage.Open(args[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = tm.MoveTo(ctx, dest, args[1])
+	return err
+}
+
+func handleRepeat(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("repeat", flag.ContinueOnError)
+	daily := flagSet.Bool("daily", false, "Space copies one day apart")
+	weekly := flagSet.Bool("weekly", false, "Space copies one week apart")
+	monthly := flagSet.Bool("monthly", false, "Space copies one month apart")
+	count := flagSet.Int("count", 1, "Number of copies to create")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: repeat <task-id> --daily|--weekly|--monthly [--count N]")
+	}
+
+	var intervals []string
+	if *daily {
+		intervals = append(intervals, task.Daily)
+	}
+	if *weekly {
+		intervals = append(intervals, task.Weekly)
+	}
+	if *monthly {
+		intervals = append(intervals, task.Monthly)
+	}
+	if len(intervals) != 1 {
+		return fmt.Errorf("choose exactly one of --daily, --weekly, --monthly")
+	}
+
+	_, err = tm.Repeat(ctx, positional[0], intervals[0], *count)
+	return err
+}
+
+func handleStorageMigrate(ctx context.Context, tm *cli.TaskManager, args []string) (err error) {
+	flagSet := flag.NewFlagSet("storage-migrate", flag.ContinueOnError)
+	force := flagSet.Bool("force", false, "Replace a destination that already holds tasks")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: storage-migrate [--force] <from-dsn> <to-dsn>")
+	}
+
+	from, err := storage.Open(positional[0])
+	if err != nil {
+		return err
+	}
+	defer closeStore(from, &err)
+	to, err := storage.Open(positional[1])
+	if err != nil {
+		return err
+	}
+	defer closeStore(to, &err)
+
+	n, err := storage.Migrate(ctx, from, to, *force)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Migrated %d tasks from %s to %s\n", tm.Icons().Success, n, positional[0], positional[1])
+	return nil
+}
+
+// closeStore closes a store opened by a command, storing a failure in *errp
+// unless it already holds an error
+func closeStore(store storage.Storage, errp *error) {
+	if err := storageShutdown(store)(); err != nil && *errp == nil {
+		*errp = fmt.Errorf("failed to close store: %w", err)
+	}
+}
+
+func handleLink(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: link <task-id> <task-id>")
+	}
+
+	return tm.Link(ctx, args[0], args[1])
+}
+
+func handleUnlink(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: unlink <task-id> <task-id>")
+	}
+
+	return tm.Unlink(ctx, args[0], args[1])
+}
+
+func handleNote(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	if len(args) < 3 || args[0] != "add" {
+		return fmt.Errorf("usage: note add <task-id> <text>")
+	}
+
+	return tm.AddNote(ctx, args[1], strings.Join(args[2:], " "))
+}
+
+func handleShow(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("show", flag.ContinueOnError)
+	output := ""
+	outputDesc := "Output format (text, json)"
+	flagSet.StringVar(&output, "o", output, outputDesc)
+	flagSet.StringVar(&output, "output", output, outputDesc)
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: show [-o json] <task-id>")
+	}
+	if err := tm.SetOutputFormat(output); err != nil {
+		return err
+	}
+
+	return tm.Show(ctx, positional[0])
+}
+
+func handleOverdue(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	return tm.Overdue(ctx)
+}
+
+func handleRemind(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("remind", flag.ContinueOnError)
+	within := flagSet.Duration("within", 24*time.Hour, "Remind about tasks due within this window, e.g. 2h")
+	notifyCmd := flagSet.String("notify-cmd", "", "Run this command once per due task with its title and due date")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 || *within <= 0 {
+		return fmt.Errorf("usage: remind [--within <duration>] [--notify-cmd <command>]")
+	}
+
+	var notifier cli.Notifier
+	if *notifyCmd != "" {
+		notifier = cli.CommandNotifier{Command: *notifyCmd}
+	}
+	return tm.Remind(ctx, *within, notifier)
+}
+
+func handleRandom(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	flagSet := flag.NewFlagSet("random", flag.ContinueOnError)
+	priority := flagSet.String("priority", "", "Only pick tasks with this priority (e.g. high or >=medium)")
+	flagSet.StringVar(priority, "p", "", "Shorthand for --priority")
+	tag := flagSet.String("tag", "", "Only pick tasks with this tag")
+	flagSet.StringVar(tag, "t", "", "Shorthand for --tag")
+
+	if _, err := parseFlags(flagSet, args); err != nil {
+		return err
+	}
+
+	opts := cli.RandomOptions{Tag: *tag}
+	if *priority != "" {
+		f, err := filter.ParsePriorityFilter(*priority, cfg.ParsePriority)
+		if err != nil {
+			return err
+		}
+		opts.Priority = &f
+	}
+
+	return tm.Random(ctx, opts)
+}
+
+func handleExtremes(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	return tm.Extremes(ctx)
+}
+
+func handleWorkload(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	flagSet := flag.NewFlagSet("workload", flag.ContinueOnError)
+	next := flagSet.Int("next", 7, "Number of days to show, starting today")
+	capacityStr := flagSet.String("capacity", "", "Daily capacity, in minutes or as a duration (default: config daily_capacity_minutes)")
+
+	if _, err := parseFlags(flagSet, args); err != nil {
+		return err
+	}
+
+	capacity := cfg.DailyCapacityMinutes
+	if *capacityStr != "" {
+		parsed, err := cli.ParseEstimate(*capacityStr)
+		if err != nil {
+			return fmt.Errorf("invalid capacity: %w", err)
+		}
+		capacity = parsed
+	}
+
+	return tm.Workload(ctx, *next, capacity)
+}
+
+func handleChanges(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("changes", flag.ContinueOnError)
+	since := flagSet.String("since", "", "JSON export to compare the current tasks against")
+
+	if _, err := parseFlags(flagSet, args); err != nil {
+		return err
+	}
+	if *since == "" {
+		return fmt.Errorf("usage: changes --since <export.json>")
+	}
+
+	return tm.Changes(ctx, *since)
+}
+
+func handleHistogram(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("histogram", flag.ContinueOnError)
+	by := flagSet.String("by", "day", "Bucket period (day, week)")
+	last := flagSet.Int("last", 14, "Number of periods to show")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+
+	field := "created"
+	if len(positional) > 0 {
+		field = positional[0]
+	}
+
+	return tm.Histogram(ctx, field, *by, *last)
+}
+
+func handleStats(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("stats", flag.ContinueOnError)
+	asJSON := flagSet.Bool("json", false, "Print the statistics as JSON")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("usage: stats [--json]")
+	}
+	if *asJSON {
+		if err := tm.SetOutputFormat(cli.OutputJSON); err != nil {
+			return err
+		}
+	}
+
+	return tm.Stats(ctx)
+}
+
+func handleTags(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("tags", flag.ContinueOnError)
+	completed := flagSet.Bool("completed", false, "Also count completed tasks")
+	flagSet.BoolVar(completed, "c", false, "Also count completed tasks")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("usage: tags [-c|--completed]")
+	}
+
+	_, err = tm.ListTags(ctx, *completed)
+	return err
+}
+
+func handleTag(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	var err error
+	switch {
+	case len(args) == 3 && args[0] == "rename":
+		_, err = tm.RenameTag(ctx, args[1], args[2])
+	case len(args) == 2 && args[0] == "delete":
+		_, err = tm.RemoveTag(ctx, args[1])
+	default:
+		err = fmt.Errorf("usage: tag rename <old> <new> | tag delete <tag>")
+	}
+	return err
+}
+
+func handleImport(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("import", flag.ContinueOnError)
+	strategy := flagSet.String("merge", cli.MergeSkip, "On ID clash: skip, overwrite, or rename")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: import [--merge skip|overwrite|rename] <format> <filename>")
+	}
+
+	_, err = tm.ImportTasks(ctx, positional[0], positional[1], *strategy)
+	return err
+}
+
+func handleExport(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("export", flag.ContinueOnError)
+	summary := flagSet.Bool("summary", false, "Prepend counts (JSON envelope or CSV comment line)")
+	noEmptySections := flagSet.Bool("no-empty-sections", true, "Markdown: omit sections that have no tasks")
+	priorityNumeric := flagSet.Bool("priority-numeric", false, "CSV: write priorities as numbers (1=low ... 4=urgent)")
+	appendMode := flagSet.Bool("append", false, "JSON: merge into the file by ID (newer wins); JSONL: add lines")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		return fmt.Errorf("usage: export [--summary] [--no-empty-sections=false] [--priority-numeric] [--append] <format> <filename>")
+	}
+
+	format := positional[0]
+	filename := positional[1]
+
+	return tm.ExportTasks(ctx, format, filename, cli.ExportOptions{
+		Summary:           *summary,
+		KeepEmptySections: !*noEmptySections,
+		PriorityNumeric:   *priorityNumeric,
+		Append:            *appendMode,
+	})
+}
+
+func handleExportAll(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("export-all", flag.ContinueOnError)
+	outputDir := flagSet.String("output-dir", "", "Directory to write the exports into (created if needed)")
+	workers := flagSet.Int("workers", 0, "Maximum number of exports run at once (default: CPU count)")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		return fmt.Errorf("usage: export-all [--output-dir dir] [--workers N] <formats> <base-filename>")
+	}
+
+	// Parse formats (comma-separated)
+	formatsStr := positional[0]
+	baseFilename := positional[1]
+
+	formats := strings.Split(formatsStr, ",")
+	for i, format := range formats {
+		formats[i] = strings.TrimSpace(format)
+	}
+
+	fmt.Printf("%s Starting concurrent export to %d formats...\n", tm.Icons().Starting, len(formats))
+	return tm.ConcurrentExport(ctx, formats, *outputDir, baseFilename, *workers)
+}
+
+func handleExportByTag(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("export-by-tag", flag.ContinueOnError)
+	maxOpen := flagSet.Int("max-open", 0, "Maximum number of files open at once (default: CPU count)")
+
+	positional, err := parseFlags(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: export-by-tag [--max-open N] <format> <directory>")
+	}
+
+	return tm.ExportByTag(ctx, positional[0], positional[1], *maxOpen)
+}
+
+func handleRPC(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	return tm.ServeRPC(ctx, os.Stdin, os.Stdout)
+}
+
+func handleShell(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: shell")
+	}
+
+	fmt.Println("Type a command without the go-fun prefix; help lists them, quit leaves")
+	return tm.Shell(ctx, func(ctx context.Context, command string, args []string) error {
+		switch command {
+		case "help":
+			showHelp()
+			return nil
+		case "shell", "interactive":
+			return fmt.Errorf("already in the shell")
+		}
+
+		// Each command gets its own timeout and starts from text output,
+		// as it would as a separate run; cancelling the shell stops it too
+		ctx, cancel := commandContext(ctx, command)
+		defer cancel()
+		if err := tm.SetOutputFormat(cli.OutputText); err != nil {
+			return err
+		}
+		return executeCommand(ctx, tm, cfg, command, args)
+	})
+}
+
+func handleServe(ctx context.Context, tm *cli.TaskManager, args []string) error {
+	flagSet := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flagSet.String("addr", "localhost:8080", "Address to listen on")
+
+	if _, err := parseFlags(flagSet, args); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	return tm.Serve(ctx, *addr)
+}
+
+func handleWatch(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	interval := time.Second
+	for i, arg := range args {
+		if arg == "--interval" && i+1 < len(args) {
+			parsed, err := time.ParseDuration(args[i+1])
+			if err != nil {
+				return fmt.Errorf("invalid interval: %w", err)
+			}
+			interval = parsed
+		}
+	}
+
+	// Stop cleanly on Ctrl-C instead of being killed mid-render
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	opts, err := parseListOptions(cfg, withoutFlags(args, nil, []string{"--interval"}))
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(getDataPath(), backendFiles[*backend])
+	return tm.Watch(ctx, path, interval, opts)
+}
+
+func handleRelabel(tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: relabel <priority> [label]")
+	}
+
+	priority, err := task.ParsePriority(args[0])
+	if err != nil {
+		return err
+	}
+
+	label := ""
+	if len(args) == 2 {
+		label = strings.TrimSpace(args[1])
+	}
+	cfg.SetPriorityLabel(priority, label)
+
+	if err := cfg.Save(configPath); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if label == "" {
+		fmt.Printf("%s %s priority label reset\n", tm.Icons().Success, priority.String())
+	} else {
+		fmt.Printf("%s %s priority now displays as %q\n", tm.Icons().Success, priority.String(), label)
+	}
+	return nil
+}
+
+func handleWorkspace(tm *cli.TaskManager, args []string) error {
+	usage := fmt.Errorf("usage: workspace use <name> | workspace list | workspace current")
+	if len(args) == 0 {
+		return usage
+	}
+
+	root := getDataRoot()
+	switch args[0] {
+	case "use":
+		if len(args) != 2 {
+			return usage
+		}
+		if err := config.UseWorkspace(root, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("%s Using workspace %q (%s)\n", tm.Icons().Success, args[1], config.WorkspaceDir(root, args[1]))
+		return nil
+	case "list":
+		current, err := config.CurrentWorkspace(root)
+		if err != nil {
+			return err
+		}
+		names, err := config.Workspaces(root)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			marker := " "
+			if name == current {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, name)
+		}
+		return nil
+	case "current":
+		current, err := config.CurrentWorkspace(root)
+		if err != nil {
+			return err
+		}
+		fmt.Println(current)
+		return nil
+	default:
+		return usage
+	}
+}
+
+func handleView(ctx context.Context, tm *cli.TaskManager, cfg *config.Config, args []string) error {
+	usage := fmt.Errorf("usage: view save <name> -- <list flags> | view list | view delete <name> | view <name> [list flags]")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "save":
+		if len(args) < 2 {
+			return usage
+		}
+		name, flags := args[1], args[2:]
+		if len(flags) > 0 && flags[0] == "--" {
+			flags = flags[1:]
+		}
+		if slices.Contains([]string{"save", "list", "delete"}, name) {
+			return fmt.Errorf("view name %q is reserved", name)
+		}
+		// Reject bad filters now rather than every time the view runs
+		if _, err := parseListOptions(cfg, flags); err != nil {
+			return err
+		}
+		cfg.SetView(name, flags)
+		if err := cfg.Save(configPath); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("%s Saved view %q\n", tm.Icons().Success, name)
+		return nil
+	case "delete":
+		if len(args) != 2 {
+			return usage
+		}
+		if !cfg.DeleteView(args[1]) {
+			return fmt.Errorf("no view named %q", args[1])
+		}
+		if err := cfg.Save(configPath); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("%s Deleted view %q\n", tm.Icons().Success, args[1])
+		return nil
+	case "list":
+		if len(cfg.Views) == 0 {
+			fmt.Println("No saved views.")
+		}
+		for _, name := range cfg.ViewNames() {
+			fmt.Printf("%s: %s\n", name, strings.Join(cfg.Views[name], " "))
+		}
+		return nil
+	default:
+		viewArgs, err := cfg.ViewArgs(args[0], args[1:])
+		if err != nil {
+			return err
+		}
+		return handleList(ctx, tm, cfg, viewArgs)
+	}
+}
+
+// parseFlags parses a command's flags, allowing them before, between, or
+// after positional arguments, and returns the positional arguments in order
+func parseFlags(flagSet *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flagSet.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flagSet.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func parseDate(dateStr string) (time.Time, error) {
+	return cli.ParseDate(dateStr, time.Now())
+}
+
+// getDataRoot returns the directory holding every workspace
+func getDataRoot() string {
+	if *dataDir != "" {
+		return *dataDir
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		log.Fatalf("Failed to get home directory: %v", err)
+	}
+	return filepath.Join(homeDir, ".go-fun")
+}
+
+// getDataPath returns the active workspace's directory, creating it
+func getDataPath() string {
+	root := getDataRoot()
+	workspace, err := config.CurrentWorkspace(root)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	dataPath := config.WorkspaceDir(root, workspace)
+	if err := os.MkdirAll(dataPath, 0755); err != nil {
+		log.Fatalf("Failed to create data directory: %v", err)
+	}
+
+	return dataPath
+}
+
+// isTerminal reports whether f is attached to a character device such as a TTY
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func showVersion() {
+	fmt.Printf("%s version %s\n", appName, appVersion)
+	fmt.Printf("Description: %s\n", appDescription)
+}
+
+func showHelp() {
+	fmt.Printf("%s - %s\n\n", appName, appDescription)
+	fmt.Println("Usage:")
+	fmt.Printf("  %s [global-flags] <command> [command-flags] [arguments]\n\n", appName)
+
+	fmt.Println("Global Flags:")
+	fmt.Println("  -version     Show version information")
+	fmt.Println("  -help        Show this help message")
+	fmt.Println("  -data-dir    Directory to store task data (default: ~/.go-fun)")
+	fmt.Println("  -backend     Storage backend: json (default), gob, or sqlite")
+	fmt.Println("  -compact     Write tasks.json without indentation (smaller, less readable)")
+	fmt.Println("  -timeout     Deadline for the command, e.g. 5s or 10m (default: 30s, 0 disables)")
+	fmt.Println("  -no-color    Disable ANSI colors; also off when NO_COLOR is set or output is not a terminal")
+	fmt.Println("  -autosave    Save writes in the background every interval, e.g. 5s; flushed on exit")
+	fmt.Println("               rpc, serve and watch run without a deadline unless -timeout is given")
+	fmt.Println("  -yes, -y     Answer yes to every confirmation prompt (delete --cascade, purge, restore)")
+	fmt.Println("               Unlike a command's --force, this skips prompts, not safety checks")
+	fmt.Println("  -due-soon-days  Days ahead a task counts as due soon in stats (default: 7)")
+	fmt.Println("  -strict-titles  Reject titles with tabs, line breaks or surrounding whitespace")
+	fmt.Println("               (default: trim them and turn tabs and line breaks into spaces)")
+	fmt.Println()
 
 	fmt.Println("Commands:")
-	fmt.Println("  add [-t --title ...] [-d --desc --description ...] [-p --priority ...] [-D --duedate ...] [-T --tag ...]")
+	fmt.Println("  add [-t --title ...] [-d --desc --description ...] [-p --priority ...] [-D --duedate ...] [-T --tag ...] [-r --recur ...]")
 	fmt.Println("    Add a new task")
-	fmt.Println("    Priority: l/low, m/med/medium, h/high (default: medium)")
+	fmt.Println("    Priority: l/low, m/med/medium, h/high, u/urgent (default: medium)")
 	fmt.Println("    Duedate formats: 2006-01-02, 01/02/2006, tomorrow, 1d, 3")
 	fmt.Println("    Tag: Single, repeated flag, or comma-separated strings")
+	fmt.Println("    Recur (-r --recur): daily, weekly, monthly, yearly, with optional count (e.g. monthly:12)")
+	fmt.Println("    --parent <id> makes the task a subtask of another task")
+	fmt.Println("    --estimate records the expected effort (e.g. 45, 90m, 1h30m)")
+	fmt.Println("    --start <date> hides the task from list until that date")
+	fmt.Println("    --tag-from-branch adds the current git branch as a tag (skipped outside a repo)")
+	fmt.Println()
+
+	fmt.Println("  add --input <file.json> [--upsert]")
+	fmt.Println("    Add tasks from a JSON array (- reads stdin); one invalid task saves none")
+	fmt.Println("    --upsert updates tasks whose ID already exists instead of failing")
 	fmt.Println()
 
 	fmt.Println("  list [flags]")
 	fmt.Println("    List tasks")
 	fmt.Println("    Flags:")
 	fmt.Println("      -c, --completed    Show completed tasks")
-	fmt.Println("      -d, --due          Filter by due date (today, overdue, week, 3)")
-	fmt.Println("      -p, --priority     Filter by priority (low/medium/high)")
+	fmt.Println("      -d, --due          Filter by due date (today, overdue, week, 3, 2024-01-01:2024-01-31)")
+	fmt.Println("      --overdue          Only overdue tasks, like -d overdue")
+	fmt.Println("      --today            Only tasks due today, like -d today")
+	fmt.Println("      --due-soon         Only tasks due within -due-soon-days (default 7)")
+	fmt.Println("      -p, --priority     Filter by priority (low/medium/high/urgent, or >=medium)")
+	fmt.Println("      --min-priority     Only tasks at or above a priority")
+	fmt.Println("      --max-priority     Only tasks at or below a priority")
 	fmt.Println("      -s, --search       Search in title and description")
+	fmt.Println("      -T, --tag          Only tasks carrying this tag")
+	fmt.Println("      --tags             Also match the search against tags")
+	fmt.Println("      --regex            Treat the search as a case-insensitive regular expression")
+	fmt.Println("      --weekday          Only tasks due on a weekday (e.g. friday, fri)")
+	fmt.Println("      --sort             Sort by priority (default), due, created, updated or title")
+	fmt.Println("      --reverse          Reverse the sort order (undated tasks stay last with --sort due)")
+	fmt.Println("      --limit N          Show at most N tasks (0 or less: all)")
+	fmt.Println("      --offset M         Skip the first M tasks after sorting")
+	fmt.Println("      --only-ids         Print only matching task IDs, one per line")
+	fmt.Println("      --tree             Indent subtasks under their parent")
+	fmt.Println("      --explain          Show which filters each task matched")
+	fmt.Println("      --all, --scheduled Include tasks whose start date hasn't come yet")
+	fmt.Println("      -o, --output       Output format: text (default) or json")
+	fmt.Println()
+
+	fmt.Println("  view save <name> -- <list flags> | view <name> [list flags]")
+	fmt.Println("    Save list filters under a name, then list with them")
+	fmt.Println("    Flags after the name are added to the saved ones and win on conflicts")
+	fmt.Println("  view list | view delete <name>")
+	fmt.Println("    Show or remove saved views")
+	fmt.Println()
+
+	fmt.Println("  watch [list flags] [--interval 1s]")
+	fmt.Println("    Redraw the task list whenever the task store changes on disk")
+	fmt.Println("    Accepts the same filters as list; Ctrl-C stops watching")
+	fmt.Println()
+
+	fmt.Println("  complete [--require-subtasks] [--auto-complete-parent] <task-id>")
+	fmt.Println("    Mark a task as completed, along with any open subtasks")
+	fmt.Println("    --require-subtasks refuses instead while subtasks are open (default: config require_subtasks)")
+	fmt.Println("    --auto-complete-parent also completes the parent once its last subtask is done (default: config auto_complete_parent)")
 	fmt.Println()
 
-	fmt.Println("  complete <task-id>")
-	fmt.Println("    Mark a task as completed")
+	fmt.Println("  complete --all [list filters]")
+	fmt.Println("    Complete every pending task matching the list filters (-p, -s, -d, -T, ...) in one save")
+	fmt.Println()
+
+	fmt.Println("  complete --due <filter> [--tag <tag>] [list filters]")
+	fmt.Println("    As --all, but lists the matches and asks first (skip with -y)")
+	fmt.Println()
+
+	fmt.Println("  complete-by <title search>")
+	fmt.Println("    Complete the one pending task whose title fuzzy-matches the search")
+	fmt.Println("    Lists the candidates and changes nothing when several match")
 	fmt.Println()
 
 	fmt.Println("  uncomplete <task-id>")
 	fmt.Println("    Mark a task as not completed")
 	fmt.Println()
 
-	fmt.Println("  delete <task-id>")
+	fmt.Println("  undo")
+	fmt.Println("    Reverse the most recent add, complete, update or delete (repeat to go further back)")
+	fmt.Println()
+
+	fmt.Println("  delete [--cascade] <task-id>")
 	fmt.Println("    Delete a task")
+	fmt.Println("    Tasks with subtasks are refused unless --cascade deletes them too, after confirming")
+	fmt.Println()
+
+	fmt.Println("  delete --all --yes [--cascade] [--force] [list filters]")
+	fmt.Println("    Delete every task matching the list filters; refuses without --yes (or -yes)")
+	fmt.Println("    Refuses to run if the store loads empty but its file is not (override with --force)")
+	fmt.Println()
+
+	fmt.Println("  rollover [--dry-run] <archive.json>")
+	fmt.Println("    Move completed tasks into an append-only archive file")
+	fmt.Println()
+
+	fmt.Println("  set-due [--relative-to created|updated|now] [--where no-due|all] <offset>")
+	fmt.Println("    Set due dates in bulk to a base timestamp plus an offset (e.g. +14d, 2w)")
+	fmt.Println()
+
+	fmt.Println("  dedupe [--auto]")
+	fmt.Println("    Merge pending tasks with the same title and due day, asking which to keep")
+	fmt.Println("    Tags and descriptions move to the survivor; --auto keeps the oldest")
+	fmt.Println()
+
+	fmt.Println("  plan-today [--all-overdue] [task-id...]")
+	fmt.Println("    Make the given tasks, or every overdue task, due at the end of today")
+	fmt.Println()
+
+	fmt.Println("  reschedule-overdue [--per-day N] [--starting date]")
+	fmt.Println("    Spread overdue tasks over the days from --starting (default: tomorrow),")
+	fmt.Println("    N per day (default: 5), most important first")
+	fmt.Println()
+
+	fmt.Println("  backup <directory>")
+	fmt.Println("    Snapshot every task into a timestamped JSON file in the directory")
+	fmt.Println()
+
+	fmt.Println("  restore <backup-file>")
+	fmt.Println("    Replace all tasks with a backup; a corrupt file is refused unchanged")
+	fmt.Println()
+
+	fmt.Println("  purge [--force]")
+	fmt.Println("    Permanently delete all completed tasks")
+	fmt.Println("    Refuses to run if the store loads empty but its file is not (override with --force)")
+	fmt.Println()
+
+	fmt.Println("  update [-t title] [-d description] [-p priority] [-D due-date|none] [-T tags] [--start date|none] <task-id>")
+	fmt.Println("  update <task-id> [title] [description] [priority] [due-date]")
+	fmt.Println("    Update an existing task, changing only the fields given; -T replaces its tags,")
+	fmt.Println("    --start sets or clears the start date")
 	fmt.Println()
 
-	fmt.Println("  update <task-id> <title> [description] [priority] [due-date]")
-	fmt.Println("    Update an existing task")
+	fmt.Println("  priority <task-id> <level>")
+	fmt.Println("    Change only the priority of a task")
 	fmt.Println()
 
-	fmt.Println("  show <task-id>")
+	fmt.Println("  due <task-id> <date|none>")
+	fmt.Println("    Change only the due date of a task; none clears it")
+	fmt.Println()
+
+	fmt.Println("  edit [--preview] <task-id>")
+	fmt.Println("    Edit a task's fields in $EDITOR")
+	fmt.Println("    --preview shows the changes (old → new) and asks before saving")
+	fmt.Println()
+
+	fmt.Println("  depend <task-id> <blocker-id>")
+	fmt.Println("    Record that a task is blocked by another task")
+	fmt.Println()
+
+	fmt.Println("  deps --check | --fix")
+	fmt.Println("    Find dependencies on missing tasks and dependency cycles (exits non-zero if any)")
+	fmt.Println("    --fix removes them, breaking each cycle at its last edge")
+	fmt.Println()
+
+	fmt.Println("  move-to <dsn> <task-id>")
+	fmt.Println("    Move a task to another store (e.g. json:/path/tasks.json or a plain path)")
+	fmt.Println()
+
+	fmt.Println("  repeat <task-id> --daily|--weekly|--monthly [--count N]")
+	fmt.Println("    Create N pending copies of a task, due one interval apart")
+	fmt.Println()
+
+	fmt.Println("  storage-migrate [--force] <from-dsn> <to-dsn>")
+	fmt.Println("    Copy all tasks between stores; a destination holding tasks is refused")
+	fmt.Println("    unless --force is given to replace it")
+	fmt.Println("    Use gob:/path/tasks.gob for a binary store that loads faster than JSON")
+	fmt.Println()
+
+	fmt.Println("  link <task-id> <task-id>")
+	fmt.Println("    Mark two tasks as related to each other")
+	fmt.Println()
+
+	fmt.Println("  unlink <task-id> <task-id>")
+	fmt.Println("    Remove the relationship between two tasks")
+	fmt.Println()
+
+	fmt.Println("  show [-o json] <task-id>")
 	fmt.Println("    Show details of a specific task")
+	fmt.Println("    -o json prints the task with is_overdue and is_due_today fields")
+	fmt.Println()
+
+	fmt.Println("  note add <task-id> <text>")
+	fmt.Println("    Append a dated note to a task; show lists notes newest first")
+	fmt.Println()
+
+	fmt.Println("  remind [--within <duration>] [--notify-cmd <command>]")
+	fmt.Println("    List pending tasks due within the window (default: 24h)")
+	fmt.Println("    --notify-cmd runs once per task with the title and due date as arguments,")
+	fmt.Println("    also set as GO_FUN_TASK_ID, GO_FUN_TASK_TITLE and GO_FUN_TASK_DUE")
+	fmt.Println()
+
+	fmt.Println("  overdue")
+	fmt.Println("    Show overdue tasks grouped by how late they are")
+	fmt.Println()
+
+	fmt.Println("  histogram [created|completed] [--by day|week] [--last N]")
+	fmt.Println("    Show a bar chart of tasks created or completed per period")
 	fmt.Println()
 
-	fmt.Println("  stats")
+	fmt.Println("  random [-p <priority>] [-t <tag>]")
+	fmt.Println("    Show one pending task picked at random, optionally filtered")
+	fmt.Println()
+
+	fmt.Println("  extremes")
+	fmt.Println("    Show the oldest pending task and the one that took longest to complete")
+	fmt.Println()
+
+	fmt.Println("  workload [--next N] [--capacity 8h]")
+	fmt.Println("    Show the estimated effort due on each of the next N days (default: 7)")
+	fmt.Println("    Days over the capacity (default: config daily_capacity_minutes) are flagged")
+	fmt.Println()
+
+	fmt.Println("  stats [--json]")
 	fmt.Println("    Show task statistics")
+	fmt.Println("    --json prints the counts as one JSON object for dashboards")
+	fmt.Println()
+
+	fmt.Println("  tags [-c|--completed]")
+	fmt.Println("    List tags in use with task counts, most used first")
+	fmt.Println("    Only pending tasks are counted unless --completed is given")
+	fmt.Println()
+
+	fmt.Println("  tag rename <old> <new> | tag delete <tag>")
+	fmt.Println("    Rename or remove a tag on every task; renaming onto an existing tag merges them")
+	fmt.Println()
+
+	fmt.Println("  workspace use <name> | workspace list | workspace current")
+	fmt.Println("    Switch between separate task lists, e.g. work and personal")
+	fmt.Println("    Each workspace keeps its own tasks and config in <data-dir>/<name>;")
+	fmt.Println("    the default workspace uses <data-dir> itself")
+	fmt.Println()
+
+	fmt.Println("  relabel <priority> [label]")
+	fmt.Println("    Set a custom display name for a priority (omit label to reset)")
+	fmt.Println("    Custom names are also accepted wherever a priority is parsed")
 	fmt.Println()
 
-	fmt.Println("  export <format> <filename>")
+	fmt.Println("  export [--summary] [--no-empty-sections=false] [--priority-numeric] [--append] <format> <filename>")
 	fmt.Println("    Export tasks to file")
-	fmt.Println("    Formats: json, csv, markdown")
+	fmt.Println("    Formats: json, jsonl, csv, markdown, ics, html, yaml")
+	fmt.Println("    --summary wraps JSON as {summary, tasks} and adds a CSV comment line")
+	fmt.Println("    --no-empty-sections=false keeps markdown section headers with zero tasks")
+	fmt.Println("    --priority-numeric writes CSV priorities as 1 (low) to 4 (urgent) for spreadsheet sorting")
+	fmt.Println("    --append merges into an existing JSON export by ID (newer wins) or adds JSONL lines")
+	fmt.Println()
+
+	fmt.Println("  changes --since <export.json>")
+	fmt.Println("    Summarize tasks added, removed, completed, reopened and edited since a JSON export")
 	fmt.Println()
 
-	fmt.Println("  export-all <formats> <base-filename>")
+	fmt.Println("  import [--merge skip|overwrite|rename] <format> <filename>")
+	fmt.Println("    Import tasks from a json, csv or yaml export")
+	fmt.Println("    --merge decides what happens when an ID already exists (default: skip)")
+	fmt.Println()
+
+	fmt.Println("  export-all [--output-dir dir] [--workers N] <formats> <base-filename>")
 	fmt.Println("    Export tasks to multiple formats concurrently")
-	fmt.Println("    Formats: comma-separated list (e.g., json,csv,markdown)")
+	fmt.Println("    Formats: comma-separated list (e.g., json,csv,markdown,html,yaml)")
+	fmt.Println("    The base filename must be a plain name; --output-dir picks the directory")
+	fmt.Println("    --workers bounds how many exports run at once")
+	fmt.Println()
+
+	fmt.Println("  export-by-tag [--max-open N] <format> <directory>")
+	fmt.Println("    Export one file per tag into a directory")
+	fmt.Println("    --max-open bounds how many files are written at once")
+	fmt.Println()
+
+	fmt.Println("  serve [--addr localhost:8080]")
+	fmt.Println("    Serve GET /tasks.ics, a live iCalendar feed to subscribe to (?tag= narrows it)")
+	fmt.Println()
+
+	fmt.Println("  rpc")
+	fmt.Println("    Serve newline-delimited JSON requests on stdin, one response per line on stdout")
+	fmt.Println("    Methods: add, list, get, complete, uncomplete, delete")
+	fmt.Println()
+
+	fmt.Println("  shell (alias: interactive)")
+	fmt.Println("    Read commands from stdin, one per line, with the store kept open; quit or exit leaves")
+	fmt.Println("    Quote arguments as in a shell; wrap with rlwrap for line editing and history")
 	fmt.Println()
 
 	fmt.Println("Examples:")
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"go-fun/internal/config"
+)
+
+func TestParseListOptionsRejectsUnknownArguments(t *testing.T) {
+	cfg := config.Default()
+
+	for _, tt := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"misspelled flag", []string{"--tga", "work"}, "unknown flag: --tga"},
+		{"stray argument", []string{"work"}, "unexpected argument: work"},
+		{"missing tag value", []string{"-T"}, "flag needs a value: -T"},
+		{"missing limit value", []string{"-p", "high", "--limit"}, "flag needs a value: --limit"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseListOptions(cfg, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestParseListOptionsBulkArguments(t *testing.T) {
+	cfg := config.Default()
+
+	// delete --all strips its own flags; a typo in the rest still fails
+	args := []string{"--all", "--yes", "--tga", "work"}
+	if _, err := parseListOptions(cfg, withoutFlags(args, []string{"--all", "--yes", "--cascade"}, nil)); err == nil {
+		t.Error("Expected a misspelled filter to fail a bulk delete")
+	}
+
+	// complete --all -T must not fall back to matching every task
+	args = []string{"--all", "-T"}
+	if _, err := parseListOptions(cfg, withoutFlags(args, []string{"--all", "--require-subtasks"}, nil)); err == nil {
+		t.Error("Expected a tag flag without a value to fail a bulk complete")
+	}
+
+	args = []string{"--all", "--yes", "-T", "work", "--cascade", "-c"}
+	opts, err := parseListOptions(cfg, withoutFlags(args, []string{"--all", "--yes", "--cascade"}, nil))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing bulk filters: %v", err)
+	}
+	if opts.Tag != "work" || !opts.ShowCompleted {
+		t.Errorf("Expected tag work with completed tasks, got %+v", opts)
+	}
+}
+
+func TestParseListOptionsAllIncludesScheduled(t *testing.T) {
+	cfg := config.Default()
+
+	opts, err := parseListOptions(cfg, []string{"--all"})
+	if err != nil {
+		t.Fatalf("Unexpected error parsing --all: %v", err)
+	}
+	if !opts.Scheduled {
+		t.Error("Expected list --all to include scheduled tasks")
+	}
+
+	// Bulk commands strip their own --all, so it never widens the match
+	opts, err = parseListOptions(cfg, withoutFlags([]string{"--all", "-T", "work"}, []string{"--all", "--yes", "--cascade"}, nil))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing bulk filters: %v", err)
+	}
+	if opts.Scheduled {
+		t.Error("Expected delete --all to leave scheduled tasks out")
+	}
+}